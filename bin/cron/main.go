@@ -15,55 +15,199 @@
 package main
 
 import (
+	"aio/pkg/config"
+	"aio/pkg/daemon"
+	"aio/pkg/db"
+	"aio/pkg/jobs"
 	"aio/pkg/log"
 	"aio/pkg/utils/fs"
-	"sync"
-
+	"errors"
+	"flag"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
-var wg sync.WaitGroup
+// scheduler struct keeps the jobs of the registry added to the cron service.
+// it is used to reload the jobs when the config file changes, and to control the cron service through the socket.
+type scheduler struct {
+	mu        sync.Mutex
+	c         *cron.Cron
+	ids       map[cron.EntryID]string
+	modTime   int64
+	startedAt time.Time
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
 
-// checkForMainBinary function checks if the main binary exists.
-// if the main binary does not exist, it stops the cron job and exits the program.
-func checkForMainBinary(c *cron.Cron, bin string) {
-	if _, err := os.Stat(bin); os.IsNotExist(err) {
-		c.Stop()
-		log.Err("main cron binary not found, stopping cron job")
-		log.Fat(err)
+// configure function applies the log settings of the config file.
+// if the config file is not valid, the current settings are kept.
+func configure() {
+	c, err := config.Load()
+	if err != nil {
+		log.Err("failed to load the config file, keeping the current settings", "err", err)
+		return
 	}
+
+	level, _ := log.ParseLevel(c.Log.Level) // validated by the config package
+	log.Configure(log.Settings{Level: level, Format: c.Log.Format, MaxSize: c.Log.MaxSize, Retention: c.Log.Retention})
 }
 
-// monitorMainBinary function monitors the main binary.
-// if the main binary is deleted, it stops the cron job and exits the program.
-func monitorMainBinary(c *cron.Cron, bin string) {
-	_, err := c.AddFunc("@every 10s", func() {
-		checkForMainBinary(c, bin)
+// reload function applies the config file, removes the scheduled jobs and adds the jobs of the registry again.
+// if the registry can't be loaded, the scheduled jobs are kept.
+func (s *scheduler) reload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.modTime = config.ModTime()
+	configure()
+	registry, err := jobs.Load()
+	if err != nil {
+		log.Err("failed to load the jobs, keeping the scheduled ones", "err", err)
+		return
+	}
+
+	for id := range s.ids {
+		s.c.Remove(id)
+	}
+
+	s.ids, err = jobs.Schedule(s.c, registry)
+	if err != nil {
+		log.Err("failed to schedule the jobs", "err", err)
+	}
+
+	log.Info("jobs scheduled", "count", len(s.ids))
+}
+
+// Status function returns the state of the cron service, used by the control socket.
+func (s *scheduler) Status() daemon.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := daemon.Status{Pid: os.Getpid(), StartedAt: s.startedAt, Jobs: []daemon.JobStatus{}}
+	for _, e := range s.c.Entries() {
+		name, ok := s.ids[e.ID]
+		if !ok {
+			continue // internal job
+		}
+		status.Jobs = append(status.Jobs, daemon.JobStatus{Name: name, Next: e.Next, Prev: e.Prev, Running: jobs.IsRunning(name)})
+	}
+
+	return status
+}
+
+// Stop function asks the cron service to stop, used by the control socket.
+func (s *scheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// monitor function adds the internal job that watches the main binary and the config file.
+// if the main binary is deleted, it stops the cron service.
+// if the config file changes, it reloads the jobs.
+func (s *scheduler) monitor(bin string) error {
+	_, err := s.c.AddFunc("@every 10s", func() {
+		if _, err := os.Stat(bin); os.IsNotExist(err) {
+			log.Err("main cron binary not found, stopping cron service")
+			s.Stop()
+			return
+		}
+
+		if config.ModTime() != s.modTime {
+			log.Info("config file changed, reloading the jobs")
+			s.reload()
+		}
 	})
 	if err != nil {
-		log.Err("failed to add monitorMainBinary cron job")
-		log.Fat(err)
+		log.Err("failed to add monitor cron job")
+		return err
 	}
+
+	return nil
 }
 
 // main function is the entry point for the cron service.
-// it initializes the cron service and adds cron jobs.
-// it starts the cron service and keeps it running.
+// it locks the pid file, so only one cron service runs at the same time,
+// adds the jobs of the registry and opens the control socket.
+// it keeps running until it receives a SIGTERM or a stop request,
+// then it waits for the running jobs to complete before exiting.
+// the --data-dir flag sets the directory of the files, like the flag of aio.
 func main() {
-	bin, err := fs.Path("cron") // Path to the main binary
+	dataDir := flag.String("data-dir", "", "directory of every file of aio (default from AIO_HOME, or the XDG directories)")
+	flag.Parse()
+	err := fs.SetHome(*dataDir)
+	if err != nil {
+		log.Fat(err)
+	}
+
+	// a service started at login can run before any aio command migrates the data
+	err = daemon.Migrate()
+	if err != nil {
+		log.Fat(err)
+	}
+
+	configure() // before the log file is opened, with the format of the config
+
+	bin, err := fs.BinPath("cron") // Path to the main binary
 	if err != nil {
 		log.Err("failed to get the main binary path", "err", err)
 	}
 
-	c := cron.New()
+	unlock, err := daemon.Lock()
+	if errors.Is(err, daemon.ErrRunning) {
+		log.Warn("cron service already running, exiting", "err", err)
+		return
+	}
 
-	// jobs list
-	pushSchedule(c, bin) // push db to the remote repository every 5 minutes
-	cleanLogs(c, bin)    // clean the logs directory every 24 hours
+	if err != nil {
+		log.Err("failed to lock the pid file")
+		log.Fat(err)
+	}
+
+	defer unlock()
+
+	// the runs left running by a cron service that did not stop gracefully will never end
+	err = db.JobRunsInterrupt()
+	if err != nil {
+		log.Warn("failed to mark the interrupted job runs", "err", err)
+	}
+
+	// the jobs run at the same time, they read the current profile only inside db.ForEachProfile
+	db.Scope()
+
+	s := &scheduler{c: cron.New(), startedAt: time.Now(), stop: make(chan struct{})}
+	s.reload()           // add the jobs of the registry, like the push of the database every 5 minutes
+	err = s.monitor(bin) // monitor the main binary and the config file every 10 seconds
+	if err != nil {
+		log.Fat(err)
+	}
+
+	closeSocket, err := daemon.Listen(s)
+	if err != nil {
+		log.Err("failed to open the control socket, the cron service can be stopped only with a signal", "err", err)
+		closeSocket = func() {}
+	}
+
+	defer closeSocket()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+	s.c.Start() // start the cron service
+	log.Info("cron service started", "pid", os.Getpid())
+
+	select {
+	case sig := <-signals:
+		log.Info("received signal, stopping cron service", "signal", sig)
+	case <-s.stop:
+		log.Info("stop requested, stopping cron service")
+	}
 
-	monitorMainBinary(c, bin) // monitor the main binary every 10 seconds
-	c.Start()                 // start the cron service
-	select {}                 // keep the cron service running
+	ctx := s.c.Stop() // stop scheduling new runs, the context is done when the running jobs complete
+	log.Info("waiting for the running jobs to complete...")
+	<-ctx.Done()
+	log.Info("cron service stopped")
 }
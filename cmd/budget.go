@@ -0,0 +1,257 @@
+// cmd package, money budget command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/inputs"
+	"aio/pkg/jobs"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"aio/pkg/utils/tm"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const moneyBudgetLongDesc = `
+Budget (aio money budget [set|show|delete]) manages the monthly budgets of the categories of the transactions,
+in the base currency of the character. Every budget is an envelope: the amount of a month not spent is carried
+to the next month, unless the budget is set with --no-rollover. The overspent amount is not carried, the next
+month starts again from the budget.
+
+Overspending a budget costs the character money.overspend_hp HP and money.overspend_karma karma of the config file,
+once per category and month. The penalties are applied by the cron service and after the import of a bank statement.
+
+Examples:
+  aio money budget set groceries 400
+  aio money budget set restaurants 150 --no-rollover
+  aio money budget set groceries
+  aio money budget show
+  aio money budget show --month 2026-09
+  aio money budget delete restaurants
+`
+
+// barWidth is the number of cells of the progress bars of the budgets.
+const barWidth = 20
+
+// moneyBudgetCmd represents the money budget command
+var moneyBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Manage the monthly budgets of the categories, with the rollover of the unspent amounts",
+	Long:  moneyBudgetLongDesc,
+}
+
+// moneyBudgetSetCmd represents the money budget set command
+var moneyBudgetSetCmd = &cobra.Command{
+	Use:   "set <category> [amount]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Set the monthly budget of a category from the current month, the amount carried so far is kept",
+	Long: `
+Set (aio money budget set <category> [amount]) sets the monthly budget of a category, in the base currency of the character.
+Without the amount it is asked, the current budget of the category is the default.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noRollover, err := cmd.Flags().GetBool("no-rollover")
+		if err != nil {
+			log.Err("failed to get flag no-rollover")
+			return err
+		}
+
+		category := strings.TrimSpace(args[0])
+		if category == "" {
+			return newUsageError("the category can't be empty", nil)
+		}
+
+		char, err := db.CharGet()
+		if err != nil {
+			return err
+		}
+
+		var amount int64
+		if len(args) == 2 {
+			amount, err = money.Parse(strings.ReplaceAll(args[1], ",", "."), char.Currency)
+		} else {
+			amount, err = askBudget(category, char.Currency)
+			if errors.Is(err, inputs.ErrCanceled) || errors.Is(err, inputs.ErrNoInput) {
+				return err
+			}
+		}
+
+		if err != nil || amount <= 0 {
+			return newUsageError("invalid amount, it must be greater than 0, e.g. 400", err)
+		}
+
+		err = db.BudgetSet(category, amount, !noRollover, time.Now())
+		if err != nil {
+			log.Err("failed to set the budget")
+			return err
+		}
+
+		log.PrintInfo("budget set", "category", category, "amount", money.Format(amount, char.Currency)+" "+char.Currency, "rollover", !noRollover)
+		return nil
+	},
+}
+
+// moneyBudgetShowCmd represents the money budget show command
+var moneyBudgetShowCmd = &cobra.Command{
+	Use:   "show",
+	Args:  cobra.NoArgs,
+	Short: "Show the spending of the month per budget, with the amounts carried from the previous months",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := cmd.Flags().GetString("month")
+		if err != nil {
+			log.Err("failed to get flag month")
+			return err
+		}
+
+		month := time.Now()
+		if value = strings.TrimSpace(value); value != "" {
+			month, err = parseMonth(value)
+			if err != nil {
+				return newUsageError("invalid month "+value+", e.g. 2026-10 or \"Oct 2026\"", err)
+			}
+		}
+
+		report, err := db.BudgetReportOf(month)
+		if err != nil {
+			log.Err("failed to get the budgets")
+			return err
+		}
+
+		currency := report.Currency
+		log.PrintS("Budgets of "+report.Month.Format("January 2006")+", in "+currency, log.TitleStyle)
+		if len(report.Envelopes) == 0 {
+			log.PrintWarn("no budgets found, set one with aio money budget set")
+			return nil
+		}
+
+		budgeted := int64(0)
+		for _, e := range report.Envelopes {
+			budgeted += e.Amount
+			carried := ""
+			if e.Carried > 0 {
+				carried = " (+" + money.Format(e.Carried, currency) + " carried)"
+			}
+
+			remaining := money.Format(e.Remaining(), currency) + " left"
+			if e.Remaining() < 0 {
+				remaining = log.ErrorStyle.Render(money.Format(-e.Remaining(), currency) + " over")
+			}
+
+			log.Print("%-16s %s %10s / %-10s %s%s", e.Category, budgetBar(e.Spent, e.Available()), money.Format(max(e.Spent, 0), currency), money.Format(e.Available(), currency), remaining, carried)
+		}
+
+		log.Print("%s %s %10s / %-10s", log.TitleStyle.Render(fmt.Sprintf("%-16s", "total")), budgetBar(report.Spent, report.MonthBudget), money.Format(report.Spent, currency), money.Format(report.MonthBudget, currency))
+		if budgeted > report.MonthBudget {
+			log.PrintWarn("the budgets of the categories exceed the monthly budget", "budgets", money.Format(budgeted, currency), "monthly budget", money.Format(report.MonthBudget, currency))
+		}
+
+		if report.Unbudgeted > 0 {
+			log.PrintInfo("spent in the categories without a budget", "amount", money.Format(report.Unbudgeted, currency)+" "+currency)
+		}
+
+		return nil
+	},
+}
+
+// moneyBudgetDeleteCmd represents the money budget delete command
+var moneyBudgetDeleteCmd = &cobra.Command{
+	Use:   "delete <category>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete the budget of a category, with the amount carried so far",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		category := strings.TrimSpace(args[0])
+		budgets, err := db.Budgets()
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, b := range budgets {
+			found = found || b.Category == category
+		}
+
+		if !found {
+			return newUsageError("no budget for the category "+category+", the budgets are shown by aio money budget show", nil)
+		}
+
+		err = db.BudgetDelete(category)
+		if err != nil {
+			log.Err("failed to delete the budget")
+			return err
+		}
+
+		log.PrintInfo("budget deleted", "category", category)
+		return nil
+	},
+}
+
+// askBudget function asks the monthly budget of a category, its current budget is the default.
+func askBudget(category, currency string) (int64, error) {
+	budgets, err := db.Budgets()
+	if err != nil {
+		return 0, err
+	}
+
+	ph := ""
+	if i := slices.IndexFunc(budgets, func(b db.Budget) bool { return b.Category == category }); i >= 0 {
+		ph = money.Format(budgets[i].Amount, currency)
+	}
+
+	log.Print("Monthly budget of %s:", category)
+	return inputs.RunCurrency(ph, currency)
+}
+
+// parseMonth function parses a month, like 2026-10 or "Oct 2026", or any date of the month.
+func parseMonth(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01", "Jan 2006", "January 2006", "01/2006"} {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return tm.Parse(s)
+}
+
+// budgetBar function returns a progress bar of the spending of a budget, highlighted when close to the limit
+// and when over it. a bar without a budget is empty.
+func budgetBar(spent, available int64) string {
+	filled := 0
+	if available > 0 {
+		filled = int(min(max(spent, 0)*barWidth/available, barWidth))
+	}
+
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	switch {
+	case spent > available:
+		return log.ErrorStyle.Render(bar)
+	case spent*10 >= available*9:
+		return log.ChangedStyle.Render(bar)
+	}
+	return bar
+}
+
+// printPenalties function applies the penalties of the overspent budgets and prints them.
+func printPenalties() error {
+	penalties, err := db.BudgetPenalize(time.Now())
+	if err != nil {
+		log.Err("failed to apply the budget penalties")
+		return err
+	}
+
+	for _, p := range penalties {
+		log.PrintWarn(jobs.PenaltyMessage(p))
+	}
+
+	return nil
+}
+
+func init() {
+	moneyBudgetSetCmd.Flags().Bool("no-rollover", false, "don't carry the amount not spent to the next month")
+	moneyBudgetShowCmd.Flags().StringP("month", "m", "", "month of the budgets, e.g. 2026-10 or \"Oct 2026\" (default: the current month)")
+	moneyBudgetCmd.AddCommand(moneyBudgetSetCmd, moneyBudgetShowCmd, moneyBudgetDeleteCmd)
+	moneyCmd.AddCommand(moneyBudgetCmd)
+}
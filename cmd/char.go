@@ -0,0 +1,141 @@
+// cmd package, char command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const charEditLongDesc = `
+Edit (aio char edit) changes the values of the character of the current profile chosen at its creation:
+first name, last name, nickname, birthday, currency and monthly budget.
+A new currency converts the balance and the budgets with the exchange rate of today, or of the latest day before it,
+the rates are imported with aio money rates import. A budget given with the new currency is kept as it is.
+
+With the flags only the given values are changed, without flags every value is asked with a form showing the current ones.
+Every change is recorded in the audit table of the database.
+
+Examples:
+  aio char edit --budget 1800
+  aio char edit --nickname "The Reaper" --birthday "02 Jan 2006"
+  aio char edit --currency EUR
+`
+
+// charCmd represents the char command
+var charCmd = &cobra.Command{
+	Use:   "char",
+	Args:  cobra.NoArgs,
+	Short: "Show the status sheet of your character, or manage it",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		char, err := db.CharGet()
+		if err != nil {
+			log.Err("failed to get the character")
+			return err
+		}
+
+		now := time.Now()
+		birthday := "today! 🎂"
+		if days := char.NextBirthday(now); days == 1 {
+			birthday = "tomorrow"
+		} else if days > 1 {
+			birthday = "in " + strconv.Itoa(days) + " days"
+		}
+
+		log.PrintS("%s %s, also known as %s", log.TitleStyle, char.FirstName, char.LastName, char.NickName)
+		log.Print("  Age      %d, next birthday %s", char.Age(now), birthday)
+		log.Print("  Level    %d (%d/%d XP)", char.Level, char.XP, char.NextLevelXP)
+		log.Print("  HP       %d/%d", char.HP, char.MaxHP)
+		log.Print("  PP       %d/%d", char.PP, char.MaxPP)
+		log.Print("  Coins    %d", char.Coins)
+		log.Print("  Karma    %d", char.Karma)
+		log.Print("  Balance  %s %s, monthly budget %s %s", money.Format(char.Balance, char.Currency), char.Currency, money.Format(char.MonthBudget, char.Currency), char.Currency)
+		log.Print("  Playing  since %s", char.CreatedAt.Format("02 Jan 2006"))
+		return nil
+	},
+}
+
+// charEditFlags maps the keys of the values of the character, the environment variables of the onboarding,
+// to the flags of char edit, so an invalid value is reported with the flag that gave it.
+var charEditFlags = map[string]string{
+	"AIO_FIRST_NAME": "--first-name",
+	"AIO_LAST_NAME":  "--last-name",
+	"AIO_NICKNAME":   "--nickname",
+	"AIO_BIRTH_DATE": "--birthday",
+	"AIO_CURRENCY":   "--currency",
+	"AIO_BUDGET":     "--budget",
+}
+
+// charEditCmd represents the char edit command
+var charEditCmd = &cobra.Command{
+	Use:   "edit",
+	Args:  cobra.NoArgs,
+	Short: "Change the name, nickname, birthday or budget of the character",
+	Long:  charEditLongDesc,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		seed := db.Seed{}
+		flags := map[string]*string{
+			"first-name": &seed.FirstName,
+			"last-name":  &seed.LastName,
+			"nickname":   &seed.NickName,
+			"birthday":   &seed.BirthDate,
+			"currency":   &seed.Currency,
+			"budget":     &seed.Budget,
+		}
+
+		for name, value := range flags {
+			v, err := cmd.Flags().GetString(name)
+			if err != nil {
+				log.Err("failed to get flag " + name)
+				return err
+			}
+			*value = v
+		}
+
+		changed, err := db.CharEdit(seed)
+		var value db.ValueError
+		if errors.As(err, &value) {
+			if flag, ok := charEditFlags[value.Key]; ok {
+				value.Key = flag
+				err = value
+			}
+		}
+
+		if err != nil {
+			log.Err("failed to edit the character")
+			return err
+		}
+
+		if !changed {
+			log.PrintInfo("nothing to change")
+			return nil
+		}
+
+		char, err := db.CharGet()
+		if err != nil {
+			log.Err("failed to get the character")
+			return err
+		}
+
+		log.Info("character edited", "character", char.NickName)
+		log.Print("your character is %s %s, also known as %s, born on %s, with a monthly budget of %s %s",
+			char.FirstName, char.LastName, char.NickName, char.BirthDate.Format("02 Jan 2006"), money.Format(char.MonthBudget, char.Currency), char.Currency)
+		return nil
+	},
+}
+
+func init() {
+	charEditCmd.Flags().String("first-name", "", "new first name of the character")
+	charEditCmd.Flags().String("last-name", "", "new last name of the character")
+	charEditCmd.Flags().String("nickname", "", "new nickname of the character, it is also the name of the profile")
+	charEditCmd.Flags().String("birthday", "", "new birth date of the character, in the form of 02 Jan 2006")
+	charEditCmd.Flags().String("currency", "", "new base currency of the character, the balance and the budgets are converted with the exchange rate of today")
+	charEditCmd.Flags().String("budget", "", "new monthly budget of the character")
+	charCmd.AddCommand(charEditCmd)
+	rootCmd.AddCommand(charCmd)
+}
@@ -33,36 +33,37 @@ var completionCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Short: "Generate completion scripts for your shell",
 	Long:  completionLongDesc,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		switch args[0] {
 		case "bash":
 			err := rootCmd.GenBashCompletion(cmd.OutOrStdout())
 			if err != nil {
 				log.Err("failed to generate bash completion")
-				log.Fat(err)
+				return err
 			}
 		case "zsh":
 			err := rootCmd.GenZshCompletion(cmd.OutOrStdout())
 			if err != nil {
 				log.Err("failed to generate zsh completion")
-				log.Fat(err)
+				return err
 			}
 		case "fish":
 			err := rootCmd.GenFishCompletion(cmd.OutOrStdout(), true)
 			if err != nil {
 				log.Err("failed to generate fish completion")
-				log.Fat(err)
+				return err
 			}
 		case "powershell":
 			err := rootCmd.GenPowerShellCompletion(cmd.OutOrStdout())
 			if err != nil {
 				log.Err("failed to generate powershell completion")
-				log.Fat(err)
+				return err
 			}
 		default:
-			log.PrintErr("unsupported shell type")
-			return
+			return newUsageError("unsupported shell type: "+args[0], nil)
 		}
+
+		return nil
 	},
 }
 
@@ -0,0 +1,222 @@
+// cmd package, config command file
+package cmd
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/daemon"
+	"aio/pkg/log"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+)
+
+const configLongDesc = `
+Config (aio config [get|set|list|edit]) manages the settings of aio, kept in the config.toml file of the config directory.
+Every setting has a default value, that can be changed in the config file and overridden by an environment variable
+named after its key, e.g. AIO_LOG_LEVEL overrides log.level. The lists are written separated by commas.
+
+  log.level                minimum level of the messages: debug, info, warn, error, fatal
+  log.format               format of the log files: text, json
+  log.max_size             maximum size of a log file in megabytes
+  log.retention            number of days the rotated log files are kept
+  sync.interval            time between two pushes of the cron service, e.g. 5m
+  sync.host                prefix of the remote repositories linked with aio -l, e.g. git@github.com:
+  rewards.task_xp          experience points earned when a task is completed
+  rewards.birthday_coins   coins gifted on the birthday of the character
+  rewards.anniversary_xp   experience points gifted on the anniversary of the creation of the character
+  reminders.lead_times     times before the due date when a reminder is shown, e.g. 1h,15m
+  money.currency           base currency of the new characters, a three letters ISO 4217 code, e.g. EUR
+  money.renewal_warning    days before the renewal of a subscription when a warning is shown, 0 to disable
+  money.overspend_hp       health points lost when the budget of a category is overspent in a month, 0 to disable
+  money.overspend_karma    karma lost when the budget of a category is overspent in a month, 0 to disable
+  colors.bright            color of the titles, as an ANSI code (0-255) or a hex code (#ff8800)
+  colors.error             color of the errors
+  colors.change            color of the changed values
+
+The jobs of the cron service are in the [[jobs]] tables of the file, see aio cron --help.
+
+Examples:
+  aio config set sync.interval 15m
+  aio config get log.level
+`
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the settings of aio",
+	Long:  configLongDesc,
+	// the config commands do not need the database, and must run with an invalid config file, to fix it
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := prepare(cmd)
+		if err != nil {
+			return err
+		}
+
+		err = daemon.Migrate()
+		if err != nil {
+			log.Err("failed to migrate the data of the previous version")
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			log.Warn("invalid config file, using the defaults", "err", err)
+		}
+
+		applyConfig(cfg)
+		return nil
+	},
+}
+
+// completeKeys function completes the first argument with the keys of the config.
+func completeKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.Keys(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Args:              cobra.ExactArgs(1),
+	Short:             "Show the value of a setting",
+	ValidArgsFunction: completeKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Err("failed to load the config file")
+			return err
+		}
+
+		value, err := cfg.Value(args[0])
+		if err != nil {
+			return newUsageError("invalid key", err)
+		}
+
+		log.Print("%s", value)
+		return nil
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:               "set <key> <value>",
+	Args:              cobra.ExactArgs(2),
+	Short:             "Change the value of a setting in the config file",
+	ValidArgsFunction: completeKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		if _, err := config.Default().Value(key); err != nil {
+			return newUsageError("invalid key", err)
+		}
+
+		err := config.Update(func(c *config.Config) error {
+			err := c.Set(key, value)
+			if err != nil {
+				return err
+			}
+			return c.Validate()
+		})
+
+		if err != nil {
+			return newUsageError("invalid value", err)
+		}
+
+		log.Info("config changed", "key", key, "value", value)
+		if config.Source(key) == "env" {
+			log.PrintWarn("the value is overridden by the environment variable", "variable", config.Env(key))
+		}
+
+		return nil
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the settings with their value and source (default, file or env)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := config.File()
+		if err != nil {
+			return err
+		}
+
+		// an invalid config file is reported, and the defaults are listed, to help fixing it
+		cfg, err := config.Load()
+		if err != nil {
+			log.PrintWarn("invalid config file, listing the defaults", "err", err)
+		}
+
+		log.PrintS("Config file: %s", log.TitleStyle, file)
+		for _, key := range config.Keys() {
+			value, err := cfg.Value(key)
+			if err != nil {
+				return err
+			}
+
+			source := config.Source(key)
+			if source == "env" {
+				source += " " + config.Env(key)
+			}
+
+			log.Print("  %-24s %-18s (%s)", key, value, source)
+		}
+
+		log.Print("  %-24s %d", "jobs", len(cfg.Jobs))
+		return nil
+	},
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Args:  cobra.NoArgs,
+	Short: "Open the config file in the editor ($VISUAL or $EDITOR)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := config.File()
+		if err != nil {
+			return err
+		}
+
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+
+		if editor == "" {
+			editor = "vi"
+			if runtime.GOOS == "windows" {
+				editor = "notepad"
+			}
+		}
+
+		c := exec.Command(editor, file)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		err = c.Run()
+		if err != nil {
+			log.Err("failed to run the editor", "editor", editor)
+			return err
+		}
+
+		_, err = config.Load()
+		if err != nil {
+			return errors.Join(err, errors.New("the config file is not valid, run aio config edit again to fix it"))
+		}
+
+		log.Info("config file edited", "file", file)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,207 @@
+// cmd package, cron command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/jobs"
+	"aio/pkg/log"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+)
+
+const cronLongDesc = `
+Cron (aio cron [list|enable|disable|run|history]) manages the jobs run in background by the cron service.
+The jobs are defined in the [[jobs]] tables of the config file (aio config edit). Every job has a name and a schedule,
+expressed as a cron expression ("0 9 * * 1-5") or a descriptor ("@every 1h", "@daily").
+The built in jobs (push, cleanlogs, reminders, events, recurring, budgets) can be rescheduled or disabled, and new jobs can be added with one of these kinds:
+- command: runs the shell command in the "command" field
+- reminder: shows a desktop notification with the "message" field
+- backup: creates a backup of the database
+- report: shows a desktop notification with the character stats
+
+Example of jobs in the config file:
+  [[jobs]]
+    name = "push"
+    schedule = "@every 15m"
+
+  [[jobs]]
+    name = "stretch"
+    kind = "reminder"
+    schedule = "0 * * * *"
+    message = "Time to stretch!"
+
+The push runs every sync.interval of the config file, unless the push job has its own schedule.
+The cron service reloads the jobs when the config file changes.
+A job never overlaps with itself: if its previous run is still running, in the cron service or in aio cron run,
+the new run is skipped.
+Every run is recorded in the state.db file, in the state directory, and can be shown with aio cron history.
+`
+
+// cronCmd represents the cron command
+var cronCmd = &cobra.Command{
+	Use:   "cron",
+	Short: "Manage the jobs of the cron service",
+	Long:  cronLongDesc,
+}
+
+// cronListCmd represents the cron list command
+var cronListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the jobs with their schedule and next run",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registry, err := jobs.Load()
+		if err != nil {
+			log.Err("failed to load the jobs")
+			return err
+		}
+
+		now := time.Now()
+		for _, j := range registry {
+			next := "disabled"
+			if j.IsEnabled() {
+				schedule, err := cron.ParseStandard(j.Schedule)
+				if err != nil {
+					log.Err("failed to parse the job schedule", "job", j.Name)
+					return err
+				}
+				next = "next run " + schedule.Next(now).Format("2006-01-02 15:04:05")
+			}
+
+			log.PrintS("%s", log.TitleStyle, j.Name)
+			log.Print("  kind: %s, schedule: %s, %s", j.Kind, j.Schedule, next)
+			if j.Description != "" {
+				log.Print("  %s", j.Description)
+			}
+		}
+
+		return nil
+	},
+}
+
+// cronEnableCmd represents the cron enable command
+var cronEnableCmd = &cobra.Command{
+	Use:   "enable <job>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Enable a job",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := jobs.SetEnabled(args[0], true)
+		if err != nil {
+			log.Err("failed to enable the job", "job", args[0])
+			return err
+		}
+
+		log.PrintInfo("job enabled", "job", args[0])
+
+		return nil
+	},
+}
+
+// cronDisableCmd represents the cron disable command
+var cronDisableCmd = &cobra.Command{
+	Use:   "disable <job>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Disable a job",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := jobs.SetEnabled(args[0], false)
+		if err != nil {
+			log.Err("failed to disable the job", "job", args[0])
+			return err
+		}
+
+		log.PrintInfo("job disabled", "job", args[0])
+
+		return nil
+	},
+}
+
+// cronRunCmd represents the cron run command
+var cronRunCmd = &cobra.Command{
+	Use:   "run <job>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Run a job immediately",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		j, err := jobs.Find(args[0])
+		if err != nil {
+			log.Err("failed to find the job", "job", args[0])
+			return err
+		}
+
+		err = j.Exec(jobs.TriggerManual)
+		if errors.Is(err, jobs.ErrStillRunning) {
+			log.PrintWarn("job still running, this run has been skipped", "job", args[0])
+			return nil
+		}
+
+		if err != nil {
+			log.Err("failed to run the job", "job", args[0])
+			return err
+		}
+
+		log.PrintInfo("job completed", "job", args[0])
+
+		return nil
+	},
+}
+
+// cronHistoryCmd represents the cron history command
+var cronHistoryCmd = &cobra.Command{
+	Use:   "history [job]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Show the last runs of the jobs, with their status and errors",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			log.Err("failed to get flag limit")
+			return err
+		}
+
+		job := ""
+		if len(args) == 1 {
+			job = args[0]
+		}
+
+		runs, err := db.JobRuns(job, limit)
+		if err != nil {
+			log.Err("failed to get the job runs")
+			return err
+		}
+
+		if len(runs) == 0 {
+			log.PrintWarn("no runs found")
+			return nil
+		}
+
+		for _, r := range runs {
+			style := lipgloss.NewStyle()
+			switch r.Status {
+			case db.RunFailed, db.RunInterrupted:
+				style = log.ErrorStyle
+			case db.RunSkipped, db.RunRunning:
+				style = log.ChangedStyle
+			}
+
+			log.Print(
+				"%s  %-12s %s %-8s %s",
+				r.StartedAt.Format("2006-01-02 15:04:05"), r.Job, style.Render(fmt.Sprintf("%-11s", r.Status)), r.Duration(), r.Trigger,
+			)
+			if r.Error != "" {
+				log.PrintS("  %s", log.ErrorStyle, strings.ReplaceAll(r.Error, "\n", ": "))
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	cronHistoryCmd.Flags().IntP("limit", "n", 20, "number of runs to show")
+	cronCmd.AddCommand(cronListCmd, cronEnableCmd, cronDisableCmd, cronRunCmd, cronHistoryCmd)
+	rootCmd.AddCommand(cronCmd)
+}
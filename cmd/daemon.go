@@ -0,0 +1,190 @@
+// cmd package, daemon command file
+package cmd
+
+import (
+	"aio/pkg/daemon"
+	"aio/pkg/log"
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const daemonLongDesc = `
+Daemon (aio daemon [start|stop|restart|status|install|uninstall]) manages the cron service, the process that runs the jobs in background.
+The cron service is started automatically by every aio command, if it is not running.
+It writes its pid in the cron.pid file and listens on the cron.sock control socket, in the data directory.
+When stopped, it waits for the running jobs to complete before exiting.
+
+The cron service can be installed as a system service, started at login and restarted on failure:
+a systemd user unit on Linux (its logs are sent to the journal, see journalctl --user -u aio-cron),
+a launchd agent on macOS and a Task Scheduler task on Windows.
+Once installed, start, stop and restart go through the service manager.
+`
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:         "daemon",
+	Short:       "Start, stop and inspect the cron service",
+	Long:        daemonLongDesc,
+	Annotations: map[string]string{"daemon": "manage"},
+}
+
+// daemonStartCmd represents the daemon start command
+var daemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Args:  cobra.NoArgs,
+	Short: "Start the cron service in background",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pid, err := daemon.Start()
+		if errors.Is(err, daemon.ErrRunning) {
+			log.PrintWarn("cron service already running", "pid", pid)
+			return nil
+		}
+
+		if err != nil {
+			log.Err("failed to start cron service")
+			return err
+		}
+
+		log.PrintInfo("cron service started", "pid", pid)
+
+		return nil
+	},
+}
+
+// daemonStopCmd represents the daemon stop command
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Args:  cobra.NoArgs,
+	Short: "Stop the cron service, waiting for the running jobs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := daemon.Stop()
+		if errors.Is(err, daemon.ErrNotRunning) {
+			log.PrintWarn("cron service not running")
+			return nil
+		}
+
+		if err != nil {
+			log.Err("failed to stop cron service")
+			return err
+		}
+
+		log.PrintInfo("cron service stopped")
+
+		return nil
+	},
+}
+
+// daemonRestartCmd represents the daemon restart command
+var daemonRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Args:  cobra.NoArgs,
+	Short: "Restart the cron service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := daemon.Stop()
+		if err != nil && !errors.Is(err, daemon.ErrNotRunning) {
+			log.Err("failed to stop cron service")
+			return err
+		}
+
+		pid, err := daemon.Start()
+		if err != nil {
+			log.Err("failed to start cron service")
+			return err
+		}
+
+		log.PrintInfo("cron service restarted", "pid", pid)
+
+		return nil
+	},
+}
+
+// daemonStatusCmd represents the daemon status command
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Args:  cobra.NoArgs,
+	Short: "Show the state of the cron service and of its jobs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pid, running := daemon.Running()
+		if !running {
+			log.PrintWarn("cron service not running")
+			return nil
+		}
+
+		s, err := daemon.GetStatus()
+		if err != nil {
+			log.PrintWarn("cron service running, but the control socket is not available", "pid", pid, "err", err)
+			return nil
+		}
+
+		log.PrintS("Cron service running", log.TitleStyle)
+		log.Print("PID:     %d", s.Pid)
+		if daemon.Installed() {
+			file, _ := daemon.ServiceFile()
+			log.Print("Service: %s", file)
+		}
+		log.Print("Started: %s (up %s)", s.StartedAt.Format("2006-01-02 15:04:05"), time.Since(s.StartedAt).Round(time.Second))
+		for _, j := range s.Jobs {
+			state := "idle"
+			if j.Running {
+				state = "running"
+			}
+
+			prev := "never"
+			if !j.Prev.IsZero() {
+				prev = j.Prev.Format("2006-01-02 15:04:05")
+			}
+
+			log.Print("  %-12s %-8s last run %s, next run %s", j.Name, state, prev, j.Next.Format("2006-01-02 15:04:05"))
+		}
+
+		return nil
+	},
+}
+
+// daemonInstallCmd represents the daemon install command
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Args:  cobra.NoArgs,
+	Short: "Install the cron service as a system service started at login",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := daemon.Install()
+		if err != nil {
+			log.Err("failed to install cron service")
+			return err
+		}
+
+		log.PrintInfo("cron service installed", "file", file)
+
+		return nil
+	},
+}
+
+// daemonUninstallCmd represents the daemon uninstall command
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Args:  cobra.NoArgs,
+	Short: "Remove the cron service from the system services",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !daemon.Installed() {
+			log.PrintWarn("cron service not installed")
+			return nil
+		}
+
+		err := daemon.Uninstall()
+		if err != nil {
+			log.Err("failed to uninstall cron service")
+			return err
+		}
+
+		log.PrintInfo("cron service uninstalled")
+
+		return nil
+	},
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonStartCmd, daemonStopCmd, daemonRestartCmd, daemonStatusCmd, daemonInstallCmd, daemonUninstallCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,125 @@
+// cmd package, errors handling file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/git"
+	"aio/pkg/inputs"
+	"aio/pkg/log"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/gen2brain/beeep"
+)
+
+// exit codes of aio, scripts can use them to handle the errors
+const (
+	exitError       = 1   // unexpected error, check the log files
+	exitUsage       = 2   // invalid command, arguments or flags
+	exitNoCharacter = 3   // the database has no character, or the chosen profile does not exist
+	exitLocked      = 4   // the database is locked by another process
+	exitNoRemote    = 5   // no remote repository linked
+	exitNoInput     = 6   // an input is required, but the prompts are disabled
+	exitCanceled    = 130 // the user canceled an input
+)
+
+// usageError struct represents an error caused by an invalid command, argument or flag.
+type usageError struct {
+	err error
+}
+
+// Error function returns the message of the usage error.
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap function returns the wrapped error.
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// newUsageError function returns a usage error with the given message.
+// if err is not nil, it is joined to the message.
+func newUsageError(msg string, err error) error {
+	if err != nil {
+		return usageError{errors.Join(errors.New(msg), err)}
+	}
+	return usageError{errors.New(msg)}
+}
+
+// started is set when a command starts running, the errors returned before are usage errors
+// (unknown command, invalid arguments or flags).
+var started bool
+
+// exitCode function returns the exit code for an error.
+func exitCode(err error) int {
+	var usage usageError
+	var value db.ValueError
+	switch {
+	case errors.Is(err, inputs.ErrCanceled):
+		return exitCanceled
+	case errors.Is(err, inputs.ErrNoInput):
+		return exitNoInput
+	case errors.As(err, &usage), errors.As(err, &value), errors.Is(err, db.ErrProfileInUse), errors.Is(err, db.ErrNoRate), errors.Is(err, db.ErrNoTask), errors.Is(err, db.ErrTaskCompleted), !started:
+		return exitUsage
+	case errors.Is(err, db.ErrNoCharacter), errors.Is(err, db.ErrNoProfile):
+		return exitNoCharacter
+	case errors.Is(err, db.ErrLocked):
+		return exitLocked
+	case errors.Is(err, git.ErrNoRemote):
+		return exitNoRemote
+	default:
+		return exitError
+	}
+}
+
+// describe function returns the message of an error on a single line.
+// the errors of the external commands, like git, include their standard error.
+func describe(err error) string {
+	msg := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		msg += ": " + strings.TrimSpace(string(exitErr.Stderr))
+	}
+
+	lines := []string{}
+	for _, l := range strings.Split(msg, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	return strings.Join(lines, ": ")
+}
+
+// handleError function is the central handler of the errors returned by the commands.
+// it logs the error, prints it to the console and returns the exit code.
+// only the unexpected errors show a desktop alert, the expected ones are explained in the console.
+func handleError(err error) int {
+	code := exitCode(err)
+	msg := describe(err)
+	switch code {
+	case exitCanceled:
+		log.Info("canceled by the user")
+	case exitUsage:
+		log.Warn("invalid command", "err", err)
+		log.PrintErr(msg)
+		log.PrintErr("run aio --help for the usage")
+	case exitError:
+		log.Err("command failed", "err", err, "code", code)
+		logDir, _ := log.Dir()
+		log.PrintErr(msg)
+		log.PrintErr("an error occurred, check the log files", "log-dir", logDir)
+		beeep.Alert("aio: an error occurred", "To see the full error, check the log file in this folder: "+logDir, "")
+	case exitNoInput:
+		log.Err("command failed", "err", err, "code", code)
+		log.PrintErr(msg)
+		log.PrintErr("pass the values with the flags of the command, or --yes to confirm, see aio --help")
+	default:
+		log.Err("command failed", "err", err, "code", code)
+		log.PrintErr(msg)
+	}
+
+	return code
+}
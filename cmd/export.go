@@ -0,0 +1,136 @@
+// cmd package, export command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/transfer"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const exportLongDesc = `
+Export (aio export) writes the data of the database to files, to keep a backup or to read them with other tools.
+Every profile is exported, the tables can be chosen with --tables, an unknown table lists the valid ones.
+
+The formats are:
+  json   a single file with every table, written to the standard output without --output
+  csv    a directory with a file for every table, the empty cells are null values
+  md     a single file with a markdown table for every table, to read the data, it can't be imported
+
+The json and csv exports can be imported back with aio import.
+
+Examples:
+  aio export > backup.json
+  aio export --format csv --output backup
+  aio export --format md --tables tasks,habits --output tasks.md
+`
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Args:  cobra.NoArgs,
+	Short: "Export the data of the database to json, csv or markdown",
+	Long:  exportLongDesc,
+	// the export does not need the character, a database without one can be exported
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := load(cmd)
+		if err != nil {
+			return err
+		}
+
+		err = db.InitSchema()
+		if err != nil {
+			log.Err("failed to initialize the database")
+			return err
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			log.Err("failed to get flag format")
+			return err
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			log.Err("failed to get flag output")
+			return err
+		}
+
+		tables, err := getTables(cmd)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(transfer.Formats, format) {
+			return newUsageError("invalid format "+format+", the formats are: "+strings.Join(transfer.Formats, ", "), nil)
+		}
+
+		err = transfer.Export(format, output, tables)
+		if errors.Is(err, transfer.ErrFormat) {
+			return usageError{err}
+		}
+
+		if err != nil {
+			log.Err("failed to export the database")
+			return err
+		}
+
+		if output != "" {
+			log.Info("database exported", "format", format, "output", output)
+			log.PrintInfo("database exported", "output", output)
+		}
+		return nil
+	},
+}
+
+// getTables function returns the tables chosen with the --tables flag, validated against the tables of the database.
+func getTables(cmd *cobra.Command) ([]string, error) {
+	names, err := cmd.Flags().GetStringSlice("tables")
+	if err != nil {
+		log.Err("failed to get flag tables")
+		return nil, err
+	}
+
+	all, err := db.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if !slices.Contains(all, name) {
+			return nil, newUsageError("unknown table "+name+", the tables are: "+strings.Join(all, ", "), nil)
+		}
+	}
+
+	return names, nil
+}
+
+// completeTables function completes the --tables flag with the tables of the database.
+func completeTables(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	tables, err := db.Tables()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return tables, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFormats function completes the --format flag with the formats of the exports.
+func completeFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return transfer.Formats, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	exportCmd.Flags().StringP("format", "f", transfer.FormatJSON, "format of the export: json, csv, md")
+	exportCmd.Flags().StringP("output", "o", "", "file of the export, or directory of the csv export (default: the standard output)")
+	exportCmd.Flags().StringSliceP("tables", "t", nil, "tables to export, separated by commas (default: every table)")
+	exportCmd.RegisterFlagCompletionFunc("format", completeFormats)
+	exportCmd.RegisterFlagCompletionFunc("tables", completeTables)
+	rootCmd.AddCommand(exportCmd)
+}
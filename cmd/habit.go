@@ -0,0 +1,73 @@
+// cmd package, habit command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// habitCmd represents the habit command
+var habitCmd = &cobra.Command{
+	Use:   "habit",
+	Short: "Manage your habits",
+}
+
+// habitAddCmd represents the habit add command
+var habitAddCmd = &cobra.Command{
+	Use:   "add <title>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Add a new habit, repeated on some days of the week at a specific time",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, err := cmd.Flags().GetString("days")
+		if err != nil {
+			log.Err("failed to get flag days")
+			return err
+		}
+
+		at, err := cmd.Flags().GetString("at")
+		if err != nil {
+			log.Err("failed to get flag at")
+			return err
+		}
+
+		err = db.HabitCreate(args[0], days, at)
+		if err != nil {
+			log.Err("failed to create the habit")
+			return err
+		}
+
+		log.PrintInfo("habit added", "title", args[0])
+
+		return nil
+	},
+}
+
+// habitListCmd represents the habit list command
+var habitListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the habits",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habits, err := db.Habits()
+		if err != nil {
+			log.Err("failed to get the habits")
+			return err
+		}
+
+		for _, h := range habits {
+			log.Print("%s %s (%s at %s)", log.TitleStyle.Render(strconv.Itoa(h.ID)), h.Title, h.Days, h.At)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	habitAddCmd.Flags().String("days", "daily", `days of the habit, "daily" or a comma separated list (e.g. "mon,wed,fri")`)
+	habitAddCmd.Flags().String("at", "09:00", "time of the day of the habit (HH:MM)")
+	habitCmd.AddCommand(habitAddCmd, habitListCmd)
+	rootCmd.AddCommand(habitCmd)
+}
@@ -0,0 +1,327 @@
+// cmd package, history command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/git"
+	"aio/pkg/inputs"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+const historyLongDesc = `
+History (aio history [list|diff|restore]) manages the versions of the database saved in the local repository.
+A version can be referenced by its commit hash or by a point in time, in that case the last version saved before that time is used.
+
+Examples:
+  aio history list
+  aio history diff "yesterday at 18:00"
+  aio history restore "3 days ago"
+  aio history restore a1b2c3d --table daily_logins
+`
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List, compare and restore the versions of the database",
+	Long:  historyLongDesc,
+}
+
+// historyListCmd represents the history list command
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the versions of the database",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		history, err := git.History()
+		if err != nil {
+			log.Err("failed to get the database history")
+			return err
+		}
+
+		if len(history) == 0 {
+			log.PrintWarn("no versions found")
+			return nil
+		}
+
+		for _, v := range history {
+			log.Print("%s  %s  %s", log.TitleStyle.Render(v.Hash), v.Date.Format("2006-01-02 15:04"), v.Message)
+		}
+
+		return nil
+	},
+}
+
+// historyDiffCmd represents the history diff command
+var historyDiffCmd = &cobra.Command{
+	Use:   "diff <version> [version]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Compare a version of the database with the current one or with another version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := git.Find(args[0])
+		if err != nil {
+			log.Err("failed to find the version to compare")
+			return err
+		}
+
+		fromFile, err := git.Snapshot(from)
+		if err != nil {
+			log.Err("failed to read the version to compare")
+			return err
+		}
+
+		defer os.Remove(fromFile)
+
+		toFile, toLabel := "", "current"
+		if len(args) == 2 {
+			to, err := git.Find(args[1])
+			if err != nil {
+				log.Err("failed to find the version to compare")
+				return err
+			}
+
+			toFile, err = git.Snapshot(to)
+			if err != nil {
+				log.Err("failed to read the version to compare")
+				return err
+			}
+
+			defer os.Remove(toFile)
+			toLabel = to.Hash
+		}
+
+		err = printDiff(fromFile, from.Hash, toFile, toLabel)
+		if err != nil {
+			log.Err("failed to compare the versions")
+			return err
+		}
+
+		return nil
+	},
+}
+
+// historyRestoreCmd represents the history restore command
+var historyRestoreCmd = &cobra.Command{
+	Use:   "restore [version]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Restore the database, or a single table, to a previous version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table, err := cmd.Flags().GetString("table")
+		if err != nil {
+			log.Err("failed to get flag table")
+			return err
+		}
+
+		ref := ""
+		if len(args) == 1 {
+			ref = args[0]
+		}
+
+		err = restore(ref, table)
+		if err != nil {
+			log.Err("failed to restore the database")
+			return err
+		}
+
+		return nil
+	},
+}
+
+// selectVersion function asks the user to choose a version of the database.
+func selectVersion() (git.Version, error) {
+	history, err := git.History()
+	if err != nil {
+		return git.Version{}, err
+	}
+
+	if len(history) == 0 {
+		return git.Version{}, errors.New("no versions found")
+	}
+
+	options := make([]string, len(history))
+	for i, v := range history {
+		options[i] = v.String()
+	}
+
+	log.Print("Select the version to restore:\n")
+	i, _, err := inputs.RunSelect(options)
+	if err != nil {
+		return git.Version{}, err
+	}
+
+	log.Print("")
+	return history[i], nil
+}
+
+// restore function restores the database, or a single table if table is not empty, to a previous version.
+// if ref is empty the user is asked to choose the version.
+// before restoring, it shows a preview of the character stats at that version and asks for confirmation.
+func restore(ref, table string) error {
+	var v git.Version
+	var err error
+	if ref == "" {
+		v, err = selectVersion()
+	} else {
+		v, err = git.Find(ref)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	file, err := git.Snapshot(v)
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file)
+
+	log.PrintS("Version %s saved on %s: %s", log.TitleStyle, v.Hash, v.Date.Format("2006-01-02 15:04"), v.Message)
+	err = printDiff(file, v.Hash, "", "current")
+	if err != nil {
+		return err
+	}
+
+	question := "Do you want to restore the database to this version?"
+	if table != "" {
+		question = "Do you want to restore the table " + table + " to this version?"
+	}
+
+	ok, err := inputs.RunConfirm(question)
+	if err != nil || !ok {
+		return err
+	}
+
+	// the restore commits the database, the push job must not commit or push meanwhile
+	unlock, err := lockPush()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if table == "" {
+		return git.Restore(v)
+	}
+
+	err = db.RestoreTable(file, table)
+	if err != nil {
+		return err
+	}
+
+	// the changes made since the last commit are included in the restore commit
+	return db.JournalCommit("restore table " + table + " to " + v.Hash + " (" + v.Date.Format("2006-01-02 15:04") + ")")
+}
+
+// printDiff function prints the character stats and the number of rows of every table of two versions of the database.
+// an empty file means the current database.
+func printDiff(fromFile, fromLabel, toFile, toLabel string) error {
+	charAt := func(file string) (*db.Character, error) {
+		if file == "" {
+			return db.CharGet()
+		}
+		return db.CharGetAt(file)
+	}
+
+	countsAt := func(file string) (map[string]int, error) {
+		if file == "" {
+			return db.Counts()
+		}
+		return db.CountsAt(file)
+	}
+
+	from, err := charAt(fromFile)
+	if err != nil {
+		return err
+	}
+
+	to, err := charAt(toFile)
+	if err != nil {
+		return err
+	}
+
+	rows := [][3]string{
+		{"Name", from.FirstName + " " + from.LastName, to.FirstName + " " + to.LastName},
+		{"Nickname", from.NickName, to.NickName},
+		{"Level", fmt.Sprint(from.Level), fmt.Sprint(to.Level)},
+		{"XP", fmt.Sprintf("%d/%d", from.XP, from.NextLevelXP), fmt.Sprintf("%d/%d", to.XP, to.NextLevelXP)},
+		{"HP", fmt.Sprintf("%d/%d", from.HP, from.MaxHP), fmt.Sprintf("%d/%d", to.HP, to.MaxHP)},
+		{"PP", fmt.Sprintf("%d/%d", from.PP, from.MaxPP), fmt.Sprintf("%d/%d", to.PP, to.MaxPP)},
+		{"Coins", fmt.Sprint(from.Coins), fmt.Sprint(to.Coins)},
+		{"Karma", fmt.Sprint(from.Karma), fmt.Sprint(to.Karma)},
+		{"Currency", from.Currency, to.Currency},
+		{"Budget", money.Format(from.MonthBudget, from.Currency), money.Format(to.MonthBudget, to.Currency)},
+		{"Balance", money.Format(from.Balance, from.Currency), money.Format(to.Balance, to.Currency)},
+	}
+
+	fromCounts, err := countsAt(fromFile)
+	if err != nil {
+		return err
+	}
+
+	toCounts, err := countsAt(toFile)
+	if err != nil {
+		return err
+	}
+
+	tables := []string{}
+	for t := range fromCounts {
+		tables = append(tables, t)
+	}
+	for t := range toCounts {
+		if _, ok := fromCounts[t]; !ok {
+			tables = append(tables, t)
+		}
+	}
+	slices.Sort(tables)
+
+	for _, t := range tables {
+		rows = append(rows, [3]string{t + " rows", fmt.Sprint(fromCounts[t]), fmt.Sprint(toCounts[t])})
+	}
+
+	printDiffTable(rows, fromLabel, toLabel)
+	return nil
+}
+
+// printDiffTable function prints the rows of a diff, highlighting the values that changed.
+func printDiffTable(rows [][3]string, fromLabel, toLabel string) {
+	width := [3]int{len("Field"), len(fromLabel), len(toLabel)}
+	for _, r := range rows {
+		for i, v := range r {
+			width[i] = max(width[i], lipgloss.Width(v))
+		}
+	}
+
+	line := func(r [3]string) string {
+		cells := make([]string, len(r))
+		for i, v := range r {
+			cells[i] = v + strings.Repeat(" ", width[i]-lipgloss.Width(v))
+		}
+		return strings.Join(cells, "  ")
+	}
+
+	log.Print("")
+	log.PrintS("%s", log.TitleStyle, line([3]string{"Field", fromLabel, toLabel}))
+	for _, r := range rows {
+		if r[1] != r[2] {
+			log.PrintS("%s", log.ChangedStyle, line(r))
+			continue
+		}
+		log.Print("%s", line(r))
+	}
+	log.Print("")
+}
+
+func init() {
+	historyRestoreCmd.Flags().StringP("table", "t", "", "restore only the given table")
+	historyCmd.AddCommand(historyListCmd, historyDiffCmd, historyRestoreCmd)
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,173 @@
+// cmd package, import command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/inputs"
+	"aio/pkg/log"
+	"aio/pkg/transfer"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const importLongDesc = `
+Import (aio import <path>) replaces the data of the database with the ones of an export made with aio export.
+The path is a json file, a directory of csv files or a single csv file named after its table, "-" reads json from the standard input.
+The format is detected from the path, or chosen with --format.
+
+Every table of the export replaces the table of the database, the other tables are not changed.
+The tables are checked against the schema of the database before the import: the unknown tables and columns,
+the missing required columns and the rows referring to missing rows of another table stop the import, nothing is changed.
+With --dry-run the import is only checked, and the rows that would change are shown.
+
+The version of the database before the import is saved, it can be restored with aio history restore.
+
+Examples:
+  aio import backup.json --dry-run
+  aio import backup --tables tasks,habits
+  aio export | ssh laptop aio import - --yes
+`
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <path>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Import the data of an export in json or csv",
+	Long:  importLongDesc,
+	// the import does not need the character, an export can be imported on a new device
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := load(cmd)
+		if err != nil {
+			return err
+		}
+
+		err = db.InitSchema()
+		if err != nil {
+			log.Err("failed to initialize the database")
+			return err
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			log.Err("failed to get flag format")
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			log.Err("failed to get flag dry-run")
+			return err
+		}
+
+		names, err := getTables(cmd)
+		if err != nil {
+			return err
+		}
+
+		if format == "" && path == "-" {
+			format = transfer.FormatJSON
+		}
+
+		if format == "" {
+			format = transfer.Detect(path)
+		}
+
+		if format == "" {
+			return newUsageError("unknown format of "+path+", choose it with --format json or --format csv", nil)
+		}
+
+		tables, err := transfer.Read(format, path, names)
+		if errors.Is(err, transfer.ErrFormat) || errors.Is(err, transfer.ErrInvalid) || errors.Is(err, os.ErrNotExist) {
+			return usageError{err}
+		}
+
+		if err != nil {
+			log.Err("failed to read the export", "path", path)
+			return err
+		}
+
+		// the invalid exports are reported by the dry run before the confirmation
+		results, err := db.Import(tables, true)
+		if errors.Is(err, db.ErrLocked) {
+			return err
+		}
+
+		if err != nil {
+			return usageError{errors.Join(errors.New("invalid export "+path), err)}
+		}
+
+		if len(results) == 0 {
+			log.PrintWarn("no tables found in the export " + path)
+			return nil
+		}
+
+		for _, r := range results {
+			log.Print("%s: %d rows replaced by %d rows", log.TitleStyle.Render(r.Table), r.Replaced, r.Rows)
+		}
+
+		if dryRun {
+			log.PrintInfo("dry run, nothing has been changed")
+			return nil
+		}
+
+		question := "Do you want to replace the data of the table " + results[0].Table + "?"
+		if len(results) > 1 {
+			question = "Do you want to replace the data of " + strconv.Itoa(len(results)) + " tables?"
+		}
+
+		ok, err := inputs.RunConfirm(question)
+		if err != nil || !ok {
+			return err
+		}
+
+		// the changes made since the last commit are saved first, so the version before the import can be restored
+		err = db.JournalCommit("")
+		if err != nil {
+			log.Err("failed to save the version before the import")
+			return err
+		}
+
+		_, err = db.Import(tables, false)
+		if err != nil {
+			log.Err("failed to import the export", "path", path)
+			return err
+		}
+
+		err = db.JournalCommit("import " + filepath.Base(path))
+		if err != nil {
+			log.Err("failed to save the import")
+			return err
+		}
+
+		log.Info("export imported", "path", path, "format", format)
+		log.PrintInfo("export imported", "tables", strings.Join(tableNames(results), ", "))
+		return nil
+	},
+}
+
+// tableNames function returns the names of the imported tables.
+func tableNames(results []db.Imported) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Table
+	}
+	return names
+}
+
+func init() {
+	importCmd.Flags().StringP("format", "f", "", "format of the export: json, csv (default: detected from the path)")
+	importCmd.Flags().StringSliceP("tables", "t", nil, "tables to import, separated by commas (default: every table of the export)")
+	importCmd.Flags().Bool("dry-run", false, "check the import and show the changes, without changing the database")
+	importCmd.RegisterFlagCompletionFunc("format", completeFormats)
+	importCmd.RegisterFlagCompletionFunc("tables", completeTables)
+	rootCmd.AddCommand(importCmd)
+}
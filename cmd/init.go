@@ -0,0 +1,98 @@
+// cmd package, init command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+
+	"github.com/spf13/cobra"
+)
+
+const initLongDesc = `
+Init (aio init) creates the database and the character, without running any other command.
+An existing character is not changed.
+Every aio command creates them when they do not exist, asking the values of the character to the user:
+with init the values can be passed with the flags, so aio can be set up in scripts and CI.
+
+A value without a flag is read from its environment variable, and if it is not set the user is asked for it.
+With --no-input, or when the input is not a terminal, a missing value is an error.
+
+  --first-name  AIO_FIRST_NAME
+  --last-name   AIO_LAST_NAME
+  --nickname    AIO_NICKNAME
+  --birth-date  AIO_BIRTH_DATE  in the form of 02 Jan 2006
+  --currency    AIO_CURRENCY    the base currency, e.g. EUR (default: money.currency of the config)
+  --budget      AIO_BUDGET      the monthly budget, in the base currency
+
+Examples:
+  aio init --first-name Jhon --last-name Smith --nickname "The Reaper" --birth-date "02 Jan 2006" --budget 1500
+  AIO_FIRST_NAME=Jhon AIO_LAST_NAME=Smith aio init --no-input --nickname Reaper --birth-date "02 Jan 2006" --budget 1500
+`
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Args:  cobra.NoArgs,
+	Short: "Create the database and the character",
+	Long:  initLongDesc,
+	// the database is initialized with the values of the flags, instead of the root one
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		seed, err := getSeed(cmd)
+		if err != nil {
+			return err
+		}
+
+		return setup(cmd, seed)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		char, err := db.CharGet()
+		if err != nil {
+			log.Err("failed to get the character")
+			return err
+		}
+
+		// an existing character is not changed, the values of the flags are used only to create it
+		log.Print("aio is ready, your character is %s %s, also known as %s", char.FirstName, char.LastName, char.NickName)
+		log.Info("aio initialized", "character", char.NickName)
+		return nil
+	},
+}
+
+// addSeedFlags function adds the flags of the values of a new character to the command.
+func addSeedFlags(cmd *cobra.Command) {
+	cmd.Flags().String("first-name", "", "first name of the character")
+	cmd.Flags().String("last-name", "", "last name of the character")
+	cmd.Flags().String("nickname", "", "nickname of the character")
+	cmd.Flags().String("birth-date", "", "birth date of the character, in the form of 02 Jan 2006")
+	cmd.Flags().String("currency", "", "base currency of the character, e.g. EUR")
+	cmd.Flags().String("budget", "", "monthly budget of the character")
+}
+
+// getSeed function returns the values of a new character passed with the flags added by addSeedFlags.
+func getSeed(cmd *cobra.Command) (db.Seed, error) {
+	seed := db.Seed{}
+	flags := map[string]*string{
+		"first-name": &seed.FirstName,
+		"last-name":  &seed.LastName,
+		"nickname":   &seed.NickName,
+		"birth-date": &seed.BirthDate,
+		"currency":   &seed.Currency,
+		"budget":     &seed.Budget,
+	}
+
+	for name, value := range flags {
+		v, err := cmd.Flags().GetString(name)
+		if err != nil {
+			log.Err("failed to get flag " + name)
+			return seed, err
+		}
+		*value = v
+	}
+
+	return seed, nil
+}
+
+func init() {
+	addSeedFlags(initCmd)
+	rootCmd.AddCommand(initCmd)
+}
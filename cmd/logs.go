@@ -0,0 +1,135 @@
+// cmd package, logs command file
+package cmd
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/tm"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const logsLongDesc = `
+Logs (aio logs) shows the entries of the log files, in the logs folder of the state directory
+(~/.local/state/aio/logs by default, or the logs folder of AIO_HOME when it is set).
+Every process writes its own file (aio.log for the cli, cron.log for the cron service).
+A log file is rotated every day or when it exceeds the maximum size, the rotated files are compressed
+and deleted after the retention period by the cleanlogs job.
+
+The log files can be configured with these keys of the config file (aio config set), or their environment variables:
+  log.level      AIO_LOG_LEVEL      minimum level of the messages (default info), also set by --log-level, --verbose and --quiet
+  log.format     AIO_LOG_FORMAT     format of the log files, "text" (default) or "json"
+  log.max_size   AIO_LOG_MAX_SIZE   maximum size of a log file in megabytes (default 10)
+  log.retention  AIO_LOG_RETENTION  number of days the rotated files are kept (default 14)
+
+Examples:
+  aio logs --level warn --since "2 days ago"
+  aio logs --process cron --follow
+`
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Args:  cobra.NoArgs,
+	Short: "Show the log entries of aio and of the cron service",
+	Long:  logsLongDesc,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		levelFlag, err := cmd.Flags().GetString("level")
+		if err != nil {
+			log.Err("failed to get flag level")
+			return err
+		}
+
+		sinceFlag, err := cmd.Flags().GetString("since")
+		if err != nil {
+			log.Err("failed to get flag since")
+			return err
+		}
+
+		follow, err := cmd.Flags().GetBool("follow")
+		if err != nil {
+			log.Err("failed to get flag follow")
+			return err
+		}
+
+		process, err := cmd.Flags().GetString("process")
+		if err != nil {
+			log.Err("failed to get flag process")
+			return err
+		}
+
+		level, err := log.ParseLevel(levelFlag)
+		if err != nil {
+			return newUsageError("invalid level "+levelFlag+", valid levels: debug, info, warn, error, fatal", nil)
+		}
+
+		since := time.Now().Add(-24 * time.Hour)
+		if sinceFlag != "" {
+			since, err = tm.Parse(sinceFlag)
+			if err != nil {
+				return newUsageError("invalid since time", err)
+			}
+		}
+
+		entries, err := log.Read(process, since, level)
+		if err != nil {
+			log.Err("failed to read the log files")
+			return err
+		}
+
+		for _, e := range entries {
+			printEntry(e, process == "")
+		}
+
+		if !follow {
+			return nil
+		}
+
+		stop := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+		go func() {
+			<-signals
+			close(stop)
+		}()
+
+		err = log.Follow(process, level, stop, func(e log.Entry) { printEntry(e, process == "") })
+		if err != nil {
+			log.Err("failed to follow the log files")
+			return err
+		}
+
+		return nil
+	},
+}
+
+// printEntry function prints a log entry, with the name of its process if the entries of every process are shown.
+// the errors are highlighted.
+func printEntry(e log.Entry, showProcess bool) {
+	prefix := ""
+	if showProcess && e.Process != "" {
+		prefix = log.TitleStyle.Render(e.Process) + " "
+	}
+
+	if e.Level >= log.ErrorLevel {
+		lines := strings.Split(e.Text, "\n")
+		for i, l := range lines {
+			lines[i] = log.ErrorStyle.Render(l)
+		}
+		log.Print("%s%s", prefix, strings.Join(lines, "\n"))
+		return
+	}
+
+	log.Print("%s%s", prefix, e.Text)
+}
+
+func init() {
+	logsCmd.Flags().StringP("level", "l", "debug", "minimum level of the entries (debug, info, warn, error, fatal)")
+	logsCmd.Flags().StringP("since", "s", "", `show the entries written since this time (default "24 hours ago", e.g. "2 days ago")`)
+	logsCmd.Flags().BoolP("follow", "f", false, "keep showing the new entries")
+	logsCmd.Flags().StringP("process", "p", "", "show only the entries of a process (aio, cron)")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,416 @@
+// cmd package, money command file
+package cmd
+
+import (
+	"aio/pkg/bank"
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"aio/pkg/utils/str"
+	"aio/pkg/utils/tm"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const moneyLongDesc = `
+Money (aio money [import|list|report|rule|rates|recurring|subscriptions|budget]) manages the finance ledger of the character,
+the transactions of its bank accounts. The transactions are imported from the bank statements, their amounts are added
+to the balance of the character. A transaction already imported is skipped, so the overlapping statements can be imported.
+The recurring transactions, like the subscriptions, are recorded by the cron service on their schedule.
+
+Every amount has a currency, the character has a base currency, of its budget and of its balance. The amounts in other
+currencies are converted to it with the exchange rate of their date, the rates are imported from a csv file.
+
+The category rules categorise the imported transactions: a rule matches the transactions whose description or payee
+contains its pattern, ignoring the case, the longest matching pattern wins.
+
+The budgets of the categories are monthly envelopes, the amount not spent is carried to the next month. Overspending
+a budget costs the character HP and karma.
+
+Examples:
+  aio money import statement.ofx
+  aio money import january.csv --date-column "Booking date" --amount-column 4 --date-format 02/01/2006
+  aio money rule add "esselunga" groceries
+  aio money list --limit 50
+  aio money rates import eurofxref-hist.csv
+  aio money report --from "01 Jan 2026" --to "31 Mar 2026"
+  aio money recurring add Netflix 12.99 --schedule "every month on the 15th"
+  aio money subscriptions
+  aio money budget set groceries 400
+  aio money budget show
+`
+
+const moneyImportLongDesc = `
+Import (aio money import <file>) imports the transactions of a bank statement, the format is detected from the file:
+  csv    a csv file exported by the bank, its columns are detected from the header or chosen with the flags
+  ofx    an OFX or QFX file, also known as Quicken or Money file
+  camt   an ISO 20022 CAMT.053 file, the end of day statement of the european banks
+
+The columns of a csv file are chosen by the name in the header or by their position, starting from 1.
+The amount is a single column, or a credit and a debit column. The format of the dates is a Go layout, e.g. 02/01/2006
+for 31/12/2024, it is detected if not given, the day before the month. The lines before the header are skipped.
+
+The currency of the amounts is read from the statement: the currency column of a csv file, the CURDEF of an OFX file
+and the Ccy of the amounts of a CAMT.053 file. Without it, the amounts are in the --currency given, or in the base currency
+of the character. The amounts in another currency than the base one need the exchange rates of their dates.
+
+With --dry-run the statement is read and the new transactions are shown, without importing them.`
+
+// moneyCmd represents the money command
+var moneyCmd = &cobra.Command{
+	Use:   "money",
+	Short: "Manage the finance ledger, import the bank statements",
+	Long:  moneyLongDesc,
+}
+
+// moneyImportCmd represents the money import command
+var moneyImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Import the transactions of a bank statement, csv, OFX, QFX or CAMT.053",
+	Long:  moneyImportLongDesc,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		values := map[string]string{}
+		for _, name := range []string{"format", "account", "date-column", "amount-column", "debit-column", "credit-column", "description-column", "payee-column", "currency-column", "currency", "date-format", "decimal", "delimiter"} {
+			value, err := cmd.Flags().GetString(name)
+			if err != nil {
+				log.Err("failed to get flag " + name)
+				return err
+			}
+			values[name] = value
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			log.Err("failed to get flag dry-run")
+			return err
+		}
+
+		if d := values["decimal"]; d != "" && d != "." && d != "," {
+			return newUsageError("invalid decimal separator "+d+", use . or ,", nil)
+		}
+
+		values["delimiter"] = strings.ReplaceAll(values["delimiter"], `\t`, "\t")
+		if len([]rune(values["delimiter"])) > 1 {
+			return newUsageError("invalid delimiter "+values["delimiter"]+", use a single character", nil)
+		}
+
+		format := values["format"]
+		if format == "" {
+			format = bank.Detect(file)
+		}
+
+		if format == "" {
+			return newUsageError("unknown format of "+file+", choose it with --format "+strings.Join(bank.Formats, ", --format "), nil)
+		}
+
+		if !slices.Contains(bank.Formats, format) {
+			return newUsageError("invalid format "+format+", the formats are: "+strings.Join(bank.Formats, ", "), nil)
+		}
+
+		currency := values["currency"]
+		if currency == "" {
+			char, err := db.CharGet()
+			if err != nil {
+				return err
+			}
+			currency = char.Currency
+		}
+
+		if _, err := money.Currency(currency); err != nil {
+			return newUsageError("invalid currency "+currency, err)
+		}
+
+		opts := bank.Options{
+			Account:  values["account"],
+			Currency: currency,
+			CSV: bank.CSV{
+				Date:        values["date-column"],
+				Amount:      values["amount-column"],
+				Debit:       values["debit-column"],
+				Credit:      values["credit-column"],
+				Description: values["description-column"],
+				Payee:       values["payee-column"],
+				Currency:    values["currency-column"],
+				DateFormat:  values["date-format"],
+				Decimal:     values["decimal"],
+				Delimiter:   values["delimiter"],
+			},
+		}
+
+		transactions, err := bank.Read(format, file, opts)
+		if errors.Is(err, bank.ErrInvalid) || errors.Is(err, os.ErrNotExist) {
+			return usageError{err}
+		}
+
+		if err != nil {
+			log.Err("failed to read the bank statement", "file", file)
+			return err
+		}
+
+		result, err := db.TransactionsImport(transactions, dryRun)
+
+		if err != nil {
+			log.Err("failed to import the transactions", "file", file)
+			return err
+		}
+
+		for _, t := range result.Imported {
+			printTransaction(t)
+		}
+
+		if dryRun {
+			log.PrintInfo("dry run, nothing has been imported", "new", len(result.Imported), "duplicates", result.Duplicates, "total", signedAmount(result.Total, result.Currency)+" "+result.Currency)
+			return nil
+		}
+
+		log.Info("bank statement imported", "file", file, "format", format, "new", len(result.Imported), "duplicates", result.Duplicates)
+		log.PrintInfo("bank statement imported", "new", len(result.Imported), "duplicates", result.Duplicates, "balance", signedAmount(result.Total, result.Currency)+" "+result.Currency)
+		return printPenalties()
+	},
+}
+
+// printTransaction function prints a transaction on a line: date, amount, currency, payee, description and category.
+func printTransaction(t db.Transaction) {
+	text := t.Payee
+	if t.Description != "" && t.Description != t.Payee {
+		text = strings.TrimPrefix(text+" - "+t.Description, " - ")
+	}
+
+	category := ""
+	if t.Category != "" {
+		category = " " + log.TitleStyle.Render("["+t.Category+"]")
+	}
+
+	log.Print("%s %10s %s  %s%s", t.Date.Format("2006-01-02"), signedAmount(t.Amount, t.Currency), t.Currency, text, category)
+}
+
+// signedAmount function formats an amount in minor units of a currency with its sign and the decimals of the currency.
+func signedAmount(minor int64, currency string) string {
+	s := money.Format(minor, currency)
+	if minor > 0 {
+		s = "+" + s
+	}
+	return s
+}
+
+// moneyListCmd represents the money list command
+var moneyListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the last transactions, the most recent first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			log.Err("failed to get flag limit")
+			return err
+		}
+
+		if limit < 1 {
+			return newUsageError("invalid limit "+strconv.Itoa(limit)+", it must be at least 1", nil)
+		}
+
+		transactions, err := db.Transactions(limit)
+		if err != nil {
+			log.Err("failed to get the transactions")
+			return err
+		}
+
+		if len(transactions) == 0 {
+			log.PrintWarn("no transactions found, import a bank statement with aio money import")
+			return nil
+		}
+
+		for _, t := range transactions {
+			printTransaction(t)
+		}
+
+		return nil
+	},
+}
+
+// moneyReportCmd represents the money report command
+var moneyReportCmd = &cobra.Command{
+	Use:   "report",
+	Args:  cobra.NoArgs,
+	Short: "Show the income and the expenses per category, in the base currency",
+	Long: `
+Report (aio money report) shows the income, the expenses and the net of the transactions between two dates, per category
+and in total, in the base currency of the character. Every amount in another currency is converted with the exchange rate
+of its date, or of the latest date before it, so the rates must be imported first with aio money rates import.
+The default period is the current month.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		values := map[string]string{}
+		for _, name := range []string{"from", "to"} {
+			value, err := cmd.Flags().GetString(name)
+			if err != nil {
+				log.Err("failed to get flag " + name)
+				return err
+			}
+			values[name] = strings.TrimSpace(value)
+		}
+
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		to := from.AddDate(0, 1, -1)
+		var err error
+		if values["from"] != "" {
+			from, err = tm.Parse(values["from"])
+			if err != nil {
+				return newUsageError("invalid start date", err)
+			}
+		}
+
+		if values["to"] != "" {
+			to, err = tm.Parse(values["to"])
+			if err != nil {
+				return newUsageError("invalid end date", err)
+			}
+		}
+
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+		to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local)
+		if to.Before(from) {
+			return newUsageError("the end date is before the start date", nil)
+		}
+
+		// the end date is included, the report ends at the start of the next day
+		report, err := db.TransactionsReport(from, to.AddDate(0, 0, 1))
+
+		if err != nil {
+			log.Err("failed to get the report of the transactions")
+			return err
+		}
+
+		log.PrintS("Report from "+from.Format("02 Jan 2006")+" to "+to.Format("02 Jan 2006")+", in "+report.Currency, log.TitleStyle)
+		if report.Count == 0 {
+			log.PrintWarn("no transactions found in the period")
+			return nil
+		}
+
+		log.Print("%-20s %12s %12s %12s", "", "income", "expenses", "net")
+		for _, c := range report.Categories {
+			category := c.Category
+			if category == "" {
+				category = "uncategorised"
+			}
+			log.Print("%-20s %12s %12s %12s  %s", category, signedAmount(c.Income, report.Currency), signedAmount(c.Expenses, report.Currency), signedAmount(c.Income+c.Expenses, report.Currency), str.Plural(c.Count, "transaction"))
+		}
+
+		log.Print("%s %12s %12s %12s  %s", log.TitleStyle.Render(fmt.Sprintf("%-20s", "total")), signedAmount(report.Income, report.Currency), signedAmount(report.Expenses, report.Currency), signedAmount(report.Income+report.Expenses, report.Currency), str.Plural(report.Count, "transaction"))
+		return nil
+	},
+}
+
+// moneyRuleCmd represents the money rule command
+var moneyRuleCmd = &cobra.Command{
+	Use:   "rule",
+	Short: "Manage the category rules of the imported transactions",
+}
+
+// moneyRuleAddCmd represents the money rule add command
+var moneyRuleAddCmd = &cobra.Command{
+	Use:   "add <pattern> <category>",
+	Args:  cobra.ExactArgs(2),
+	Short: "Add a category rule, the transactions not yet categorised that match it are categorised too",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern, category := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+		if pattern == "" || category == "" {
+			return newUsageError("the pattern and the category can't be empty", nil)
+		}
+
+		err := db.CategoryRuleCreate(pattern, category)
+		if err != nil {
+			log.Err("failed to add the category rule")
+			return err
+		}
+
+		log.PrintInfo("category rule added", "pattern", pattern, "category", category)
+		return nil
+	},
+}
+
+// moneyRuleListCmd represents the money rule list command
+var moneyRuleListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the category rules",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rules, err := db.CategoryRules()
+		if err != nil {
+			log.Err("failed to get the category rules")
+			return err
+		}
+
+		for _, r := range rules {
+			log.Print("%s %q -> %s", log.TitleStyle.Render(strconv.Itoa(r.ID)), r.Pattern, r.Category)
+		}
+
+		return nil
+	},
+}
+
+// moneyRuleDeleteCmd represents the money rule delete command
+var moneyRuleDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a category rule, the categorised transactions keep their category",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return newUsageError("invalid rule id: "+args[0], nil)
+		}
+
+		rules, err := db.CategoryRules()
+		if err != nil {
+			return err
+		}
+
+		if !slices.ContainsFunc(rules, func(r db.CategoryRule) bool { return r.ID == id }) {
+			return newUsageError("no category rule with id "+args[0]+", the rules are listed by aio money rule list", nil)
+		}
+
+		err = db.CategoryRuleDelete(id)
+		if err != nil {
+			log.Err("failed to delete the category rule")
+			return err
+		}
+
+		log.PrintInfo("category rule deleted", "id", id)
+		return nil
+	},
+}
+
+func init() {
+	moneyImportCmd.Flags().StringP("format", "f", "", "format of the statement: csv, ofx, camt (default: detected from the file)")
+	moneyImportCmd.Flags().String("account", "", "account of the transactions, instead of the one of the statement")
+	moneyImportCmd.Flags().String("date-column", "", "csv column of the booking date, a header name or a position")
+	moneyImportCmd.Flags().String("amount-column", "", "csv column of the amount, negative for the expenses")
+	moneyImportCmd.Flags().String("debit-column", "", "csv column of the expenses, without an amount column")
+	moneyImportCmd.Flags().String("credit-column", "", "csv column of the incomes, without an amount column")
+	moneyImportCmd.Flags().String("description-column", "", "csv column of the description")
+	moneyImportCmd.Flags().String("payee-column", "", "csv column of the payee")
+	moneyImportCmd.Flags().String("currency-column", "", "csv column of the currency of the amounts (default: detected)")
+	moneyImportCmd.Flags().String("currency", "", "currency of the amounts when the statement doesn't tell it (default: the base currency)")
+	moneyImportCmd.Flags().String("date-format", "", "csv format of the dates as a Go layout, e.g. 02/01/2006 (default: detected)")
+	moneyImportCmd.Flags().String("decimal", "", "csv decimal separator of the amounts, . or , (default: detected)")
+	moneyImportCmd.Flags().String("delimiter", "", `csv separator of the columns, e.g. ";" or "\t" (default: detected)`)
+	moneyImportCmd.Flags().Bool("dry-run", false, "show the new transactions without importing them")
+	moneyImportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return bank.Formats, cobra.ShellCompDirectiveNoFileComp
+	})
+	moneyListCmd.Flags().IntP("limit", "n", 20, "maximum number of transactions to list")
+	moneyReportCmd.Flags().String("from", "", "first day of the report (default: the first day of the month)")
+	moneyReportCmd.Flags().String("to", "", "last day of the report (default: the last day of the month)")
+	moneyRuleCmd.AddCommand(moneyRuleAddCmd, moneyRuleListCmd, moneyRuleDeleteCmd)
+	moneyCmd.AddCommand(moneyImportCmd, moneyListCmd, moneyReportCmd, moneyRuleCmd)
+	rootCmd.AddCommand(moneyCmd)
+}
@@ -0,0 +1,162 @@
+// cmd package, profile command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/inputs"
+	"aio/pkg/log"
+
+	"github.com/spf13/cobra"
+)
+
+const profileLongDesc = `
+Profile (aio profile [list|create|switch|delete]) manages the profiles, so the members of a household can share aio.
+Every profile has its own character, with its tasks, habits and reminders, the nickname of the character is the name of the profile.
+
+The commands use the active profile, chosen with aio profile switch on every device, or the first one created.
+A single command can use another profile with --profile or the AIO_PROFILE variable.
+The reminders of the cron service are shown for the active profile.
+
+Examples:
+  aio profile create --first-name Jane --last-name Smith --nickname Jay --birth-date "02 Jan 2008" --switch
+  aio task list --profile Jay
+`
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage the profiles of the household",
+	Long:  profileLongDesc,
+}
+
+// completeProfiles function completes the first argument with the nicknames of the profiles.
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	profiles, err := db.Profiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := []string{}
+	for _, p := range profiles {
+		names = append(names, p.NickName)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// profileListCmd represents the profile list command
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the profiles, the one in use is marked with *",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := db.Profiles()
+		if err != nil {
+			log.Err("failed to get the profiles")
+			return err
+		}
+
+		for _, p := range profiles {
+			mark := " "
+			if p.Active {
+				mark = "*"
+			}
+			log.Print("%s %s %s %s, level %d", mark, log.TitleStyle.Render(p.NickName), p.FirstName, p.LastName, p.Level)
+		}
+
+		return nil
+	},
+}
+
+// profileCreateCmd represents the profile create command
+var profileCreateCmd = &cobra.Command{
+	Use:   "create",
+	Args:  cobra.NoArgs,
+	Short: "Create a new profile with a new character",
+	Long: `
+Create (aio profile create) creates a new profile, asking the values of its character like the first start of aio.
+The values can be passed with the same flags and variables of aio init, see aio init --help.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		seed, err := getSeed(cmd)
+		if err != nil {
+			return err
+		}
+
+		switchTo, err := cmd.Flags().GetBool("switch")
+		if err != nil {
+			log.Err("failed to get flag switch")
+			return err
+		}
+
+		nickname, err := db.ProfileCreate(seed)
+		if err != nil {
+			log.Err("failed to create the profile")
+			return err
+		}
+
+		log.Info("profile created", "profile", nickname)
+		if !switchTo {
+			log.Print("switch to the new profile with: aio profile switch %q", nickname)
+			return nil
+		}
+
+		err = db.ProfileSwitch(nickname)
+		if err != nil {
+			return err
+		}
+
+		log.PrintInfo("profile switched", "profile", nickname)
+		return nil
+	},
+}
+
+// profileSwitchCmd represents the profile switch command
+var profileSwitchCmd = &cobra.Command{
+	Use:               "switch <nickname>",
+	Args:              cobra.ExactArgs(1),
+	Short:             "Make a profile the active one on this device",
+	ValidArgsFunction: completeProfiles,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := db.ProfileSwitch(args[0])
+		if err != nil {
+			return err
+		}
+
+		log.Info("profile switched", "profile", args[0])
+		log.PrintInfo("profile switched", "profile", args[0])
+		return nil
+	},
+}
+
+// profileDeleteCmd represents the profile delete command
+var profileDeleteCmd = &cobra.Command{
+	Use:               "delete <nickname>",
+	Args:              cobra.ExactArgs(1),
+	Short:             "Delete a profile, with its character, tasks, habits and reminders",
+	ValidArgsFunction: completeProfiles,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ok, err := inputs.RunConfirm("Do you want to delete the profile " + args[0] + " and all its data?")
+		if err != nil || !ok {
+			return err
+		}
+
+		err = db.ProfileDelete(args[0])
+		if err != nil {
+			return err
+		}
+
+		log.Info("profile deleted", "profile", args[0])
+		log.PrintInfo("profile deleted", "profile", args[0])
+		return nil
+	},
+}
+
+func init() {
+	addSeedFlags(profileCreateCmd)
+	profileCreateCmd.Flags().Bool("switch", false, "make the new profile the active one")
+	profileCmd.AddCommand(profileListCmd, profileCreateCmd, profileSwitchCmd, profileDeleteCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -0,0 +1,106 @@
+// cmd package, money rates command file
+package cmd
+
+import (
+	"aio/pkg/bank"
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/str"
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+const moneyRatesLongDesc = `
+Rates (aio money rates [import|list]) manages the exchange rates, used to convert the amounts in other currencies
+to the base currency of the character, with the rate of their date or of the latest date before it.
+The rates are imported from a csv file, aio never downloads them. The file is in the long format, a rate per line:
+  date,base,quote,rate
+  2026-01-02,EUR,USD,1.0321
+or in the wide format of the European Central Bank, e.g. eurofxref-hist.csv, a date per line and a currency per column,
+with the rates of the --base currency:
+  Date,USD,JPY,GBP
+  2026-01-02,1.0321,162.74,0.8284
+A rate is the number of units of the quote currency for one unit of the base currency. The inverse rates and the cross
+rates through a common currency are computed, e.g. USD to GBP from EUR to USD and EUR to GBP.
+A rate imported again for the same date and currencies is replaced.
+
+Examples:
+  aio money rates import eurofxref-hist.csv
+  aio money rates import rates.csv --base USD
+  aio money rates list
+`
+
+// moneyRatesCmd represents the money rates command
+var moneyRatesCmd = &cobra.Command{
+	Use:   "rates",
+	Short: "Manage the exchange rates, imported from a csv file",
+	Long:  moneyRatesLongDesc,
+}
+
+// moneyRatesImportCmd represents the money rates import command
+var moneyRatesImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Import the exchange rates of a csv file, in the long format or in the wide format of the ECB",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, err := cmd.Flags().GetString("base")
+		if err != nil {
+			log.Err("failed to get flag base")
+			return err
+		}
+
+		rates, err := bank.ReadRates(args[0], base)
+		if errors.Is(err, bank.ErrInvalid) || errors.Is(err, os.ErrNotExist) {
+			return usageError{err}
+		}
+
+		if err != nil {
+			log.Err("failed to read the exchange rates", "file", args[0])
+			return err
+		}
+
+		changed, err := db.RatesImport(rates)
+		if err != nil {
+			log.Err("failed to import the exchange rates", "file", args[0])
+			return err
+		}
+
+		log.Info("exchange rates imported", "file", args[0], "rates", len(rates), "changed", changed)
+		log.PrintInfo("exchange rates imported", "rates", len(rates), "new or changed", changed)
+		return nil
+	},
+}
+
+// moneyRatesListCmd represents the money rates list command
+var moneyRatesListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the latest exchange rate of every pair of currencies",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rates, err := db.Rates()
+		if err != nil {
+			log.Err("failed to get the exchange rates")
+			return err
+		}
+
+		if len(rates) == 0 {
+			log.PrintWarn("no exchange rates found, import them with aio money rates import")
+			return nil
+		}
+
+		for _, r := range rates {
+			log.Print("%s %12s  on %s, %s", log.TitleStyle.Render(r.Base+"/"+r.Quote), strconv.FormatFloat(r.Rate, 'f', -1, 64), r.Date.Format("02 Jan 2006"), str.Plural(r.Dates, "date"))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	moneyRatesImportCmd.Flags().String("base", "EUR", "base currency of the rates of a file without a base column, like the ECB files")
+	moneyRatesCmd.AddCommand(moneyRatesImportCmd, moneyRatesListCmd)
+	moneyCmd.AddCommand(moneyRatesCmd)
+}
@@ -0,0 +1,297 @@
+// cmd package, money recurring command file
+package cmd
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"aio/pkg/utils/tm"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const moneyRecurringLongDesc = `
+Recurring (aio money recurring [add|list|delete]) manages the transactions repeated on a schedule, like the subscriptions,
+the rent or the salary. The recurring job of the cron service records their due occurrences in the ledger, and adds
+their amounts to the balance of the character, the occurrences missed while the cron service was not running too.
+
+The schedule is written in words, e.g.:
+  "every month on the 1st", "every 3 months on the last day", "mondays", "every 2 weeks on friday",
+  "every year on 02 Jan", "daily", "weekly", "quarterly", "yearly"
+Without a day, the schedule repeats the day of its start, e.g. "monthly" from the 15th is every month on the 15th.
+
+The amount is an expense, unless --income is given. It is in the base currency of the character, or in the --currency given,
+then every occurrence is converted to the base currency with the exchange rate of its date. Use the recurring transactions for the payments missing from
+the imported bank statements, otherwise they are counted twice.
+
+Examples:
+  aio money recurring add Netflix 12.99 --schedule "every month on the 15th" --category entertainment
+  aio money recurring add Salary 2100 --income --schedule "every month on the 27th"
+  aio money recurring add Gym 240 --schedule yearly --from "01 Sep 2026"
+  aio money recurring add "Cloud storage" 9.99 --currency USD --schedule monthly
+`
+
+// moneyRecurringCmd represents the money recurring command
+var moneyRecurringCmd = &cobra.Command{
+	Use:   "recurring",
+	Short: "Manage the recurring transactions, recorded by the cron service on their schedule",
+	Long:  moneyRecurringLongDesc,
+}
+
+// moneyRecurringAddCmd represents the money recurring add command
+var moneyRecurringAddCmd = &cobra.Command{
+	Use:   "add <description> <amount>",
+	Args:  cobra.ExactArgs(2),
+	Short: "Add a recurring transaction, an expense unless --income is given",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		values := map[string]string{}
+		for _, name := range []string{"schedule", "from", "until", "payee", "category", "account", "currency"} {
+			value, err := cmd.Flags().GetString(name)
+			if err != nil {
+				log.Err("failed to get flag " + name)
+				return err
+			}
+			values[name] = strings.TrimSpace(value)
+		}
+
+		income, err := cmd.Flags().GetBool("income")
+		if err != nil {
+			log.Err("failed to get flag income")
+			return err
+		}
+
+		description := strings.TrimSpace(args[0])
+		if description == "" {
+			return newUsageError("the description can't be empty", nil)
+		}
+
+		currency := values["currency"]
+		if currency == "" {
+			char, err := db.CharGet()
+			if err != nil {
+				return err
+			}
+			currency = char.Currency
+		}
+
+		currency, err = money.Currency(currency)
+		if err != nil {
+			return newUsageError("invalid currency "+values["currency"], err)
+		}
+
+		amount, err := money.Parse(strings.ReplaceAll(args[1], ",", "."), currency)
+		if err != nil || amount == 0 {
+			return newUsageError("invalid amount "+args[1]+", e.g. 12.99", err)
+		}
+
+		// the sign is given by --income, the amount is written without it
+		if amount > 0 {
+			amount = -amount
+		}
+
+		if income {
+			amount = -amount
+		}
+
+		if values["schedule"] == "" {
+			return newUsageError("the schedule is required, e.g. --schedule \"every month on the 1st\"", nil)
+		}
+
+		sc, err := tm.ParseSchedule(values["schedule"])
+		if err != nil {
+			return newUsageError("invalid schedule "+values["schedule"], err)
+		}
+
+		r := db.Recurring{
+			Description: description,
+			Amount:      amount,
+			Currency:    currency,
+			Payee:       values["payee"],
+			Account:     values["account"],
+			Category:    values["category"],
+			Schedule:    sc.String(),
+			StartsAt:    time.Now(),
+		}
+
+		if values["from"] != "" {
+			r.StartsAt, err = tm.Parse(values["from"])
+			if err != nil {
+				return newUsageError("invalid start date", err)
+			}
+		}
+
+		if values["until"] != "" {
+			until, err := tm.Parse(values["until"])
+			if err != nil {
+				return newUsageError("invalid end date", err)
+			}
+
+			if until.Before(r.StartsAt) {
+				return newUsageError("the end date is before the start date", nil)
+			}
+			r.EndsAt = &until
+		}
+
+		r.StartsAt = time.Date(r.StartsAt.Year(), r.StartsAt.Month(), r.StartsAt.Day(), 0, 0, 0, 0, time.Local)
+		err = db.RecurringCreate(r)
+		if err != nil {
+			log.Err("failed to add the recurring transaction")
+			return err
+		}
+
+		next := sc.Next(r.StartsAt, r.StartsAt.AddDate(0, 0, -1))
+		log.PrintInfo("recurring transaction added", "description", description, "amount", signedAmount(amount, currency)+" "+currency, "schedule", sc.String(), "next", tm.Format(next))
+		return nil
+	},
+}
+
+// moneyRecurringListCmd represents the money recurring list command
+var moneyRecurringListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the recurring transactions, the next one first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recurrings, err := db.Recurrings()
+		if err != nil {
+			log.Err("failed to get the recurring transactions")
+			return err
+		}
+
+		if len(recurrings) == 0 {
+			log.PrintWarn("no recurring transactions found, add one with aio money recurring add")
+			return nil
+		}
+
+		for _, r := range recurrings {
+			next := "next " + r.NextAt.Format("Mon 02 Jan 2006")
+			if r.Ended() {
+				next = "ended"
+			}
+
+			category := ""
+			if r.Category != "" {
+				category = " " + log.TitleStyle.Render("["+r.Category+"]")
+			}
+
+			log.Print("%s %-24s %10s %s  %-30s %s%s", log.TitleStyle.Render(strconv.Itoa(r.ID)), r.Description, signedAmount(r.Amount, r.Currency), r.Currency, r.Schedule, next, category)
+		}
+
+		return nil
+	},
+}
+
+// moneyRecurringDeleteCmd represents the money recurring delete command
+var moneyRecurringDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a recurring transaction, its recorded occurrences stay in the ledger",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return newUsageError("invalid recurring transaction id: "+args[0], nil)
+		}
+
+		recurrings, err := db.Recurrings()
+		if err != nil {
+			return err
+		}
+
+		if !slices.ContainsFunc(recurrings, func(r db.Recurring) bool { return r.ID == id }) {
+			return newUsageError("no recurring transaction with id "+args[0]+", they are listed by aio money recurring list", nil)
+		}
+
+		err = db.RecurringDelete(id)
+		if err != nil {
+			log.Err("failed to delete the recurring transaction")
+			return err
+		}
+
+		log.PrintInfo("recurring transaction deleted", "id", id)
+		return nil
+	},
+}
+
+// moneySubscriptionsCmd represents the money subscriptions command
+var moneySubscriptionsCmd = &cobra.Command{
+	Use:   "subscriptions",
+	Args:  cobra.NoArgs,
+	Short: "List the subscriptions, the recurring expenses, with their monthly and annual cost",
+	Long: `
+Subscriptions (aio money subscriptions) lists the recurring expenses not yet ended, with their cost in a month and in a year,
+on average, and the totals in the base currency, converted with the latest exchange rates. The renewals of the next money.renewal_warning days of the config are highlighted,
+the recurring job of the cron service warns them with a desktop notification. The expenses repeated more than once a month
+are not renewed, they are never warned.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recurrings, err := db.Recurrings()
+		if err != nil {
+			log.Err("failed to get the recurring transactions")
+			return err
+		}
+
+		char, err := db.CharGet()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		warning := now.AddDate(0, 0, config.Get().Money.RenewalWarning)
+		monthly, annual := 0.0, 0.0
+		count := 0
+		for _, r := range recurrings {
+			if r.Amount >= 0 || r.Ended() {
+				continue
+			}
+
+			sc, err := tm.ParseSchedule(r.Schedule)
+			if err != nil {
+				log.Warn("skipping recurring transaction with an invalid schedule", "description", r.Description, "err", err)
+				continue
+			}
+
+			// the costs are averages, in units of the currency, the totals are in the base currency
+			year := money.Major(-r.Amount, r.Currency) * sc.PerYear()
+			base, err := db.Convert(-r.Amount, r.Currency, char.Currency, now)
+			if err != nil {
+				return errors.Join(errors.New("failed to convert "+r.Description+" to "+char.Currency), err)
+			}
+
+			monthly += money.Major(base, char.Currency) * sc.PerYear() / 12
+			annual += money.Major(base, char.Currency) * sc.PerYear()
+			count++
+
+			next := "renews " + r.NextAt.Format("Mon 02 Jan 2006")
+			if !r.NextAt.After(warning) && sc.PerYear() <= 12 {
+				next = log.ChangedStyle.Render(next)
+			}
+
+			log.Print("%s %-24s %9s %s  %-30s %9.2f/month %9.2f/year  %s", log.TitleStyle.Render(strconv.Itoa(r.ID)), r.Description, money.Format(-r.Amount, r.Currency), r.Currency, r.Schedule, year/12, year, next)
+		}
+
+		if count == 0 {
+			log.PrintWarn("no subscriptions found, add one with aio money recurring add")
+			return nil
+		}
+
+		log.Print("%s %.2f/month %.2f/year %s", log.TitleStyle.Render("total"), monthly, annual, char.Currency)
+		return nil
+	},
+}
+
+func init() {
+	moneyRecurringAddCmd.Flags().StringP("schedule", "s", "", `schedule of the transaction, e.g. "every month on the 1st" or "mondays"`)
+	moneyRecurringAddCmd.Flags().String("from", "", "start of the schedule, a past date records the past occurrences too (default: today)")
+	moneyRecurringAddCmd.Flags().String("until", "", "end of the schedule (default: never)")
+	moneyRecurringAddCmd.Flags().String("payee", "", "counterparty of the transaction")
+	moneyRecurringAddCmd.Flags().String("category", "", "category of the transaction (default: given by the category rules)")
+	moneyRecurringAddCmd.Flags().String("account", "", "bank account of the transaction")
+	moneyRecurringAddCmd.Flags().String("currency", "", "currency of the amount (default: the base currency)")
+	moneyRecurringAddCmd.Flags().Bool("income", false, "the transaction is an income, e.g. the salary")
+	moneyRecurringCmd.AddCommand(moneyRecurringAddCmd, moneyRecurringListCmd, moneyRecurringDeleteCmd)
+	moneyCmd.AddCommand(moneyRecurringCmd, moneySubscriptionsCmd)
+}
@@ -0,0 +1,95 @@
+// cmd package, remind command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const remindLongDesc = `
+Remind (aio remind [list|snooze]) manages the reminders of the upcoming tasks and habits.
+The cron service shows a desktop notification at every lead time before a task due date or a habit occurrence.
+The lead times can be changed with aio config set reminders.lead_times 1h,15m.
+`
+
+// remindCmd represents the remind command
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Manage the reminders of the tasks and habits",
+	Long:  remindLongDesc,
+}
+
+// remindListCmd represents the remind list command
+var remindListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the last reminders shown",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			log.Err("failed to get flag limit")
+			return err
+		}
+
+		notifications, err := db.Notifications(limit)
+		if err != nil {
+			log.Err("failed to get the reminders")
+			return err
+		}
+
+		for _, n := range notifications {
+			snoozed := ""
+			if n.SnoozedUntil != nil {
+				snoozed = ", snoozed until " + n.SnoozedUntil.Format("2006-01-02 15:04")
+			}
+			log.Print(
+				"%s %s %q due %s, shown %s before on %s%s",
+				log.TitleStyle.Render(strconv.Itoa(n.ID)), n.Kind, n.Title, n.DueAt.Format("2006-01-02 15:04"),
+				n.Lead, n.FiredAt.Format("2006-01-02 15:04"), snoozed,
+			)
+		}
+
+		return nil
+	},
+}
+
+// remindSnoozeCmd represents the remind snooze command
+var remindSnoozeCmd = &cobra.Command{
+	Use:   "snooze <id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Snooze a reminder, it will be shown again later",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return newUsageError("invalid reminder id: "+args[0], nil)
+		}
+
+		d, err := cmd.Flags().GetDuration("for")
+		if err != nil {
+			log.Err("failed to get flag for")
+			return err
+		}
+
+		until := time.Now().Add(d)
+		err = db.NotificationSnooze(id, until)
+		if err != nil {
+			log.Err("failed to snooze the reminder")
+			return err
+		}
+
+		log.PrintInfo("reminder snoozed", "id", id, "until", until.Format("15:04"))
+
+		return nil
+	},
+}
+
+func init() {
+	remindListCmd.Flags().IntP("limit", "n", 20, "number of reminders to show")
+	remindSnoozeCmd.Flags().Duration("for", 10*time.Minute, "snooze duration (e.g. 10m, 1h)")
+	remindCmd.AddCommand(remindListCmd, remindSnoozeCmd)
+	rootCmd.AddCommand(remindCmd)
+}
@@ -2,13 +2,15 @@
 package cmd
 
 import (
+	"aio/pkg/config"
+	"aio/pkg/daemon"
 	"aio/pkg/db"
 	"aio/pkg/git"
+	"aio/pkg/inputs"
 	"aio/pkg/log"
-	cmdutils "aio/pkg/utils/cmd"
 	"aio/pkg/utils/fs"
+	"errors"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -19,6 +21,10 @@ health, and productivity, transforming life into a video game.
 Track progress, achieve goals, and level up in all aspects of your journey—making productivity fun and rewarding.
 It is a fun way to keep track of your life and improve yourself.
 
+The data are kept in the XDG directories: the database and its history in $XDG_DATA_HOME/aio (~/.local/share/aio),
+the logs and the runtime files in $XDG_STATE_HOME/aio (~/.local/state/aio) and the config in $XDG_CONFIG_HOME/aio (~/.config/aio).
+With --data-dir or AIO_HOME every file is kept in a single directory.
+
 For more information, visit the project page at https://github.com/Tagliapietra96/aio`
 
 // rootCmd represents the base command when called without any subcommands
@@ -27,86 +33,257 @@ var rootCmd = &cobra.Command{
 	Short: "A all in one application",
 	Long:  rootLongDesc,
 	// init the database and user if they do not exist
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		err := db.Init()
-		if err != nil {
-			log.Err("failed to initialize the database")
-			log.Fat(err)
-		}
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setup(cmd, db.Seed{})
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		revert, err := cmd.Flags().GetBool("revert")
 		if err != nil {
 			log.Err("failed to get flag revert")
-			log.Fat(err)
+			return err
+		}
+
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			log.Err("failed to get flag to")
+			return err
+		}
+
+		if to != "" && !revert {
+			return newUsageError("the --to flag requires --revert", nil)
 		}
 
 		if revert {
-			err := git.Revert()
+			err := restore(to, "")
 			if err != nil {
 				log.Err("failed to revert the db version")
-				log.Fat(err)
+				return err
 			}
 		}
 
 		addRemote, err := cmd.Flags().GetBool("link-remote")
 		if err != nil {
 			log.Err("failed to get flag link-remote")
-			log.Fat(err)
+			return err
+		}
+
+		remote, err := cmd.Flags().GetString("remote")
+		if err != nil {
+			log.Err("failed to get flag remote")
+			return err
+		}
+
+		if remote != "" && !addRemote {
+			return newUsageError("the --remote flag requires --link-remote", nil)
 		}
 
 		if addRemote {
-			err := git.LinkRepo()
+			err := git.LinkRepo(remote)
 			if err != nil {
 				log.Err("failed to link the remote repository")
-				log.Fat(err)
-
+				return err
 			}
 		}
-	},
-	PersistentPostRun: func(cmd *cobra.Command, args []string) {
-		// launch the cron service
-		bin, err := fs.Path("cron")
-		if err != nil {
-			log.Err("failed to get cron binary path")
-			log.Fat(err)
-		}
 
-		running := false
-		out, err := cmdutils.Output("pgrep", "-f", bin)
-		if err == nil {
-			running = strings.TrimSpace(string(out)) != ""
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		// launch the cron service, unless the command manages it
+		if managesDaemon(cmd) {
+			return nil
 		}
 
-		if !running {
-			err = os.Chmod(bin, 0755)
+		// the command succeeded, so a failed start is only reported
+		if _, running := daemon.Running(); !running {
+			_, err := daemon.Start()
 			if err != nil {
-				log.Err("failed to change cron binary permissions")
-				log.Fat(err)
+				log.Err("failed to start cron service", "err", err)
+				log.PrintWarn("failed to start the cron service, run aio daemon start for details")
 			}
+		}
 
-			err := cmdutils.StartBinaryWithInhibitSystemSleep(bin)
-			if err != nil {
-				log.Err("failed to start cron service")
-				log.Fat(err)
-			}
+		return nil
+	},
+}
+
+// setup function prepares the execution of a command: it applies the flags, migrates the data of the previous versions,
+// loads the config file and initializes the database, creating the character with the values of the seed if it does not exist.
+func setup(cmd *cobra.Command, seed db.Seed) error {
+	err := load(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = db.Init(seed)
+	if err != nil {
+		log.Err("failed to initialize the database")
+		return err
+	}
+
+	return nil
+}
+
+// load function applies the flags, migrates the data of the previous versions and loads the config file.
+// it is the part of setup shared by the commands that do not need the character.
+func load(cmd *cobra.Command) error {
+	err := prepare(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = daemon.Migrate()
+	if err != nil {
+		log.Err("failed to migrate the data of the previous version")
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Err("failed to load the config file")
+		return errors.Join(err, errors.New("fix the config file with aio config edit"))
+	}
+
+	applyConfig(cfg)
+	return nil
+}
+
+// prepare function applies the log, input, data directory and profile flags.
+// the errors returned before the end of prepare are usage errors.
+func prepare(cmd *cobra.Command) error {
+	err := setLogLevel(cmd)
+	if err != nil {
+		return newUsageError("failed to set the log level", err)
+	}
 
-			log.Info("started cron service")
+	err = setInputMode(cmd)
+	if err != nil {
+		return newUsageError("failed to set the input mode", err)
+	}
+
+	dataDir, err := cmd.Flags().GetString("data-dir")
+	if err != nil {
+		return err
+	}
+
+	err = fs.SetHome(dataDir)
+	if err != nil {
+		return newUsageError("invalid data directory", err)
+	}
+
+	name, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = os.Getenv("AIO_PROFILE")
+	}
+	db.SetProfile(name)
+
+	started = true
+	return nil
+}
+
+// applyConfig function applies the settings of the config file to the log and to the console styles.
+// the other packages read their settings when they need them.
+func applyConfig(c config.Config) {
+	level, _ := log.ParseLevel(c.Log.Level) // validated by the config package
+	log.Configure(log.Settings{Level: level, Format: c.Log.Format, MaxSize: c.Log.MaxSize, Retention: c.Log.Retention})
+	log.SetColors(c.Colors.Bright, c.Colors.Error, c.Colors.Change)
+	inputs.SetColors(c.Colors.Bright, c.Colors.Error, c.Colors.Change)
+}
+
+// setInputMode function applies the input flags: --no-input disables the prompts
+// and --yes answers yes to the confirmations. the prompts are disabled also when the input is not a terminal,
+// or with the AIO_NO_INPUT and AIO_YES variables.
+func setInputMode(cmd *cobra.Command) error {
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
+
+	noInput, err := cmd.Flags().GetBool("no-input")
+	if err != nil {
+		return err
+	}
+
+	inputs.SetYes(yes)
+	inputs.SetNoInput(noInput)
+	return nil
+}
+
+// setLogLevel function applies the log flags: --log-level sets the level of the log file and of the console,
+// --verbose is a shortcut for the debug level and --quiet hides the console output, except the errors.
+// without flags, the log.level of the config file is used, or the AIO_LOG_LEVEL variable.
+func setLogLevel(cmd *cobra.Command) error {
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return err
+	}
+
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return err
+	}
+
+	levelFlag, err := cmd.Flags().GetString("log-level")
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+
+	if levelFlag != "" {
+		level, err := log.ParseLevel(levelFlag)
+		if err != nil {
+			return errors.New("invalid log level " + levelFlag + ", valid levels: debug, info, warn, error, fatal")
 		}
-	},
+		log.SetLevel(level)
+	}
+
+	log.SetQuiet(quiet)
+	return nil
+}
+
+// managesDaemon function returns true if the command, or one of its parents, manages the cron service.
+// these commands must not start the cron service when they end.
+func managesDaemon(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations["daemon"] == "manage" {
+			return true
+		}
+	}
+	return false
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// the errors returned by the commands are handled here, it exits with the exit code of the error.
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		log.Err("failed to execute the root command")
-		log.Fat(err)
+		os.Exit(handleError(err))
 	}
 }
 
 func init() {
+	rootCmd.SilenceErrors = true // the errors are printed by handleError
+	rootCmd.SilenceUsage = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "show the debug messages and write them to the log file")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "hide the console output, except the errors")
+	rootCmd.PersistentFlags().String("log-level", "", "minimum level of the messages: debug, info, warn, error, fatal (default from the config file)")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "answer yes to every confirmation (also AIO_YES)")
+	rootCmd.PersistentFlags().Bool("no-input", false, "never prompt, fail when an input is required (also AIO_NO_INPUT)")
+	rootCmd.PersistentFlags().String("data-dir", "", "directory of every file of aio (default from AIO_HOME, or the XDG directories)")
+	rootCmd.PersistentFlags().String("profile", "", "nickname of the profile to use (default from AIO_PROFILE, or the active profile)")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
 	rootCmd.Flags().BoolP("revert", "r", false, "revert the db version")
+	rootCmd.Flags().String("to", "", "version to revert to with --revert, a commit hash or a date (default: ask)")
 	rootCmd.Flags().BoolP("link-remote", "l", false, "add a remote repository")
+	rootCmd.Flags().String("remote", "", "remote repository to link with --link-remote, in the form of YourUsername/repo-name (default: ask)")
 }
@@ -0,0 +1,128 @@
+// cmd package, sync command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/git"
+	"aio/pkg/jobs"
+	"aio/pkg/log"
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const syncLongDesc = `
+Sync (aio sync [status|now]) manages the synchronization of the database with the remote repository.
+The cron service pushes the changes every 5 minutes, when a push fails the next attempts are delayed:
+- network errors are retried with an exponential backoff
+- authentication errors are retried with a longer backoff
+- conflicts are not retried until a sync is forced with 'aio sync now'
+`
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Show and force the synchronization with the remote repository",
+	Long:  syncLongDesc,
+}
+
+// syncStatusCmd represents the sync status command
+var syncStatusCmd = &cobra.Command{
+	Use:   "status",
+	Args:  cobra.NoArgs,
+	Short: "Show the last success, the pending commits and the last error",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := git.LoadSyncState()
+		if err != nil {
+			log.Err("failed to load the sync state")
+			return err
+		}
+
+		pending, err := git.Pending()
+		if err != nil {
+			log.Err("failed to count the pending commits")
+			return err
+		}
+
+		printSyncState(s, pending)
+
+		return nil
+	},
+}
+
+// syncNowCmd represents the sync now command
+var syncNowCmd = &cobra.Command{
+	Use:   "now",
+	Args:  cobra.NoArgs,
+	Short: "Commit the changes and push them immediately, ignoring the backoff",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := lockPush()
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		err = db.JournalCommit("")
+		if err != nil {
+			log.Err("failed to commit the changes")
+			return err
+		}
+
+		s, err := git.Sync(true)
+		if err != nil && !errors.Is(err, git.ErrAuth) && !errors.Is(err, git.ErrNetwork) && !errors.Is(err, git.ErrConflict) {
+			log.Err("failed to sync with the remote repository")
+			return err
+		}
+
+		pending, err := git.Pending()
+		if err != nil {
+			log.Err("failed to count the pending commits")
+			return err
+		}
+
+		printSyncState(s, pending)
+
+		return nil
+	},
+}
+
+// lockPush function locks the push job while a command commits the database or pushes it,
+// so the cron service doesn't commit or push at the same time. it fails if the push job is running.
+func lockPush() (func(), error) {
+	unlock, err := jobs.Lock("push")
+	if errors.Is(err, jobs.ErrStillRunning) {
+		return nil, errors.Join(errors.New("the cron service is pushing the database, try again in a moment"), err)
+	}
+	return unlock, err
+}
+
+// printSyncState function prints the sync state to the console.
+func printSyncState(s *git.SyncState, pending int) {
+	when := func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+
+	log.PrintS("Sync status: %s", log.TitleStyle, s.Status())
+	log.Print("Last success:    %s", when(s.LastSuccess))
+	log.Print("Last attempt:    %s", when(s.LastAttempt))
+	log.Print("Pending commits: %d", pending)
+	if s.ErrorKind != "" {
+		log.PrintS("Last error:      %s (%d consecutive failures)", log.ErrorStyle, s.ErrorKind, s.Failures)
+		log.Print("%s", s.LastError)
+		if s.ErrorKind == git.ErrConflict.Error() {
+			log.Print("Resolve the conflict in the data directory with git, then run 'aio sync now'.")
+		} else {
+			log.Print("Next attempt:    %s", when(s.NextAttempt))
+		}
+	}
+}
+
+func init() {
+	syncCmd.AddCommand(syncStatusCmd, syncNowCmd)
+	rootCmd.AddCommand(syncCmd)
+}
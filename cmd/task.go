@@ -0,0 +1,104 @@
+// cmd package, task command file
+package cmd
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/tm"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// taskCmd represents the task command
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Manage your tasks",
+}
+
+// taskAddCmd represents the task add command
+var taskAddCmd = &cobra.Command{
+	Use:   "add <title>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Add a new task, with an optional due date",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		due, err := cmd.Flags().GetString("due")
+		if err != nil {
+			log.Err("failed to get flag due")
+			return err
+		}
+
+		var dueAt *time.Time
+		if due != "" {
+			t, err := tm.Parse(due)
+			if err != nil {
+				return newUsageError("invalid due date", err)
+			}
+			dueAt = &t
+		}
+
+		err = db.TaskCreate(args[0], dueAt)
+		if err != nil {
+			log.Err("failed to create the task")
+			return err
+		}
+
+		log.PrintInfo("task added", "title", args[0])
+
+		return nil
+	},
+}
+
+// taskListCmd represents the task list command
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the tasks not yet completed",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tasks, err := db.Tasks()
+		if err != nil {
+			log.Err("failed to get the tasks")
+			return err
+		}
+
+		for _, t := range tasks {
+			due := ""
+			if t.DueAt != nil {
+				due = " (due " + tm.Format(*t.DueAt) + ")"
+			}
+			log.Print("%s %s%s", log.TitleStyle.Render(strconv.Itoa(t.ID)), t.Title, due)
+		}
+
+		return nil
+	},
+}
+
+// taskDoneCmd represents the task done command
+var taskDoneCmd = &cobra.Command{
+	Use:   "done <id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Complete a task and earn experience points",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return newUsageError("invalid task id: "+args[0], nil)
+		}
+
+		err = db.TaskComplete(id)
+		if err != nil {
+			log.Err("failed to complete the task")
+			return err
+		}
+
+		log.PrintInfo("task completed", "id", id)
+
+		return nil
+	},
+}
+
+func init() {
+	taskAddCmd.Flags().StringP("due", "d", "", `due date of the task (e.g. "tomorrow at 18:00")`)
+	taskCmd.AddCommand(taskAddCmd, taskListCmd, taskDoneCmd)
+	rootCmd.AddCommand(taskCmd)
+}
@@ -0,0 +1,200 @@
+// bank package reads the bank statements, to import their transactions in the finance ledger.
+// the statements are csv files, with a mapping of their columns, OFX or QFX files and ISO 20022 CAMT.053 files.
+package bank
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// formats of the bank statements
+const (
+	FormatCSV  = "csv"  // a csv file, its columns are mapped with the CSV options
+	FormatOFX  = "ofx"  // an OFX or QFX file, version 1 (SGML) or 2 (XML)
+	FormatCAMT = "camt" // an ISO 20022 CAMT.053 file, the end of day statement
+)
+
+// Formats are the formats of the bank statements.
+var Formats = []string{FormatCSV, FormatOFX, FormatCAMT}
+
+// ErrInvalid is returned when a bank statement can't be read.
+var ErrInvalid = errors.New("invalid bank statement")
+
+// Options struct represents the options of the import of a bank statement.
+type Options struct {
+	Account  string // the account of the transactions, instead of the one of the statement, e.g. "credit card"
+	Currency string // the currency of the amounts when the statement doesn't tell it, e.g. the base currency
+	CSV      CSV
+}
+
+// Detect function returns the format of a bank statement from its content, or from its extension.
+// it returns an empty string if the format is unknown.
+func Detect(path string) string {
+	head := make([]byte, 4096)
+	file, err := os.Open(path)
+	if err == nil {
+		n, _ := file.Read(head)
+		head = head[:n]
+		file.Close()
+	}
+
+	switch {
+	case bytes.Contains(head, []byte("OFXHEADER")), bytes.Contains(bytes.ToUpper(head), []byte("<OFX>")):
+		return FormatOFX
+	case bytes.Contains(head, []byte("camt.053")), bytes.Contains(head, []byte("BkToCstmrStmt")):
+		return FormatCAMT
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".txt":
+		return FormatCSV
+	case ".ofx", ".qfx":
+		return FormatOFX
+	case ".xml", ".053":
+		return FormatCAMT
+	default:
+		return ""
+	}
+}
+
+// Read function reads the transactions of a bank statement in the given format.
+func Read(format, path string, opts Options) ([]db.Transaction, error) {
+	if !slices.Contains(Formats, format) {
+		return nil, errors.Join(ErrInvalid, errors.New("unknown format "+format+", the formats are: "+strings.Join(Formats, ", ")))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Err("failed to read the bank statement", "file", path)
+		return nil, err
+	}
+
+	fallback, err := money.Currency(opts.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []db.Transaction
+	switch format {
+	case FormatCSV:
+		transactions, err = readCSV(data, opts.CSV, fallback)
+	case FormatOFX:
+		transactions, err = readOFX(data, fallback)
+	case FormatCAMT:
+		transactions, err = readCAMT(data, fallback)
+	}
+
+	if err != nil {
+		return nil, errors.Join(ErrInvalid, errors.New(path+" can't be read as "+format), err)
+	}
+
+	for i := range transactions {
+		transactions[i].Source = format
+		if opts.Account != "" {
+			transactions[i].Account = opts.Account
+		}
+	}
+
+	return transactions, nil
+}
+
+// currencyOf function returns the currency of an amount of a statement, or the fallback if the statement doesn't tell it.
+func currencyOf(s, fallback string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return fallback, nil
+	}
+	return money.Currency(s)
+}
+
+// ParseAmount function parses an amount of a bank statement, e.g. "-1.234,56 €", "(12.50)" or "12.50-",
+// in minor units of its currency. decimal is the decimal separator, "." or ",", the other one is a thousands separator.
+// with an empty decimal the separator is guessed from the amount.
+func ParseAmount(s, decimal, currency string) (int64, error) {
+	original := s
+	negative := false
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+	}
+
+	if strings.HasSuffix(s, "-") {
+		negative = true
+		s = strings.TrimSuffix(s, "-")
+	}
+
+	// keep the digits, the separators and the sign, drop the currency and the spaces
+	s = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' || r == '-' || r == '+' {
+			return r
+		}
+		return -1
+	}, s)
+
+	if decimal == "" {
+		decimal = guessDecimal(s)
+	}
+
+	thousands := ","
+	if decimal == "," {
+		thousands = "."
+	}
+
+	s = strings.ReplaceAll(s, thousands, "")
+	s = strings.Replace(s, decimal, ".", 1)
+
+	// some banks write more decimals than the currency has, e.g. -12.5000, the extra zeros are dropped
+	if whole, decimals, ok := strings.Cut(s, "."); ok && len(decimals) > money.Exponent(currency) {
+		extra := decimals[money.Exponent(currency):]
+		if strings.Trim(extra, "0") == "" {
+			s = whole + "." + decimals[:money.Exponent(currency)]
+		}
+	}
+
+	n, err := money.Parse(s, currency)
+	if err != nil {
+		return 0, errors.New("invalid amount " + strconv.Quote(original))
+	}
+
+	if negative && n > 0 {
+		n = -n
+	}
+	return n, nil
+}
+
+// guessDecimal function returns the decimal separator of the amounts, from the first amount where it is clear:
+// the last separator of an amount with both separators, or a separator not followed by three digits, like "12,50".
+// a separator repeated in an amount is a thousands separator, the default is ".".
+func guessDecimal(amounts ...string) string {
+	for _, s := range amounts {
+		s = strings.Map(func(r rune) rune {
+			if (r >= '0' && r <= '9') || r == '.' || r == ',' {
+				return r
+			}
+			return -1
+		}, s)
+		dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+		switch {
+		case dot >= 0 && comma >= 0 && comma > dot:
+			return ","
+		case dot >= 0 && comma >= 0:
+			return "."
+		case comma >= 0 && strings.Count(s, ",") > 1:
+			return "."
+		case dot >= 0 && strings.Count(s, ".") > 1:
+			return ","
+		case comma >= 0 && len(s)-comma-1 != 3:
+			return ","
+		case dot >= 0 && len(s)-dot-1 != 3:
+			return "."
+		}
+	}
+	return "."
+}
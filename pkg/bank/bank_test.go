@@ -0,0 +1,97 @@
+package bank
+
+import (
+	"aio/pkg/db"
+	"testing"
+	"time"
+)
+
+func TestGuessDecimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []string
+		want    string
+	}{
+		{name: "dot", amounts: []string{"12.50"}, want: "."},
+		{name: "comma", amounts: []string{"12,50"}, want: ","},
+		{name: "both, comma last", amounts: []string{"1.234,56"}, want: ","},
+		{name: "both, dot last", amounts: []string{"1,234.56"}, want: "."},
+		{name: "repeated comma", amounts: []string{"1,234,567"}, want: "."},
+		{name: "repeated dot", amounts: []string{"1.234.567"}, want: ","},
+		{name: "one decimal", amounts: []string{"-3,5"}, want: ","},
+		{name: "three digits are ambiguous", amounts: []string{"1,234", "12,50"}, want: ","},
+		{name: "three digits only", amounts: []string{"1.234", "5.678"}, want: "."},
+		{name: "currency symbols", amounts: []string{"€ 1.234,56"}, want: ","},
+		{name: "no separators", amounts: []string{"", "1500"}, want: "."},
+		{name: "no amounts", want: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := guessDecimal(tt.amounts...); got != tt.want {
+				t.Errorf("guessDecimal(%q) = %q, want %q", tt.amounts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		s        string
+		decimal  string
+		currency string
+		want     int64
+		invalid  bool
+	}{
+		{s: "12.50", currency: "EUR", want: 1250},
+		{s: "-1.234,56 €", currency: "EUR", want: -123456},
+		{s: "1,234.56", decimal: ".", currency: "USD", want: 123456},
+		{s: "1.234", decimal: ",", currency: "EUR", want: 123400},
+		{s: "(12.50)", currency: "EUR", want: -1250},
+		{s: "12.50-", currency: "EUR", want: -1250},
+		{s: "+3,5", currency: "EUR", want: 350},
+		{s: "-12.5000", currency: "EUR", want: -1250},
+		{s: "1234", currency: "JPY", want: 1234},
+		{s: "1.234", decimal: ",", currency: "JPY", want: 1234},
+		{s: "12.345", decimal: ".", currency: "EUR", invalid: true},
+		{s: "12.5", currency: "JPY", invalid: true},
+		{s: "abc", currency: "EUR", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseAmount(tt.s, tt.decimal, tt.currency)
+			if (err != nil) != tt.invalid {
+				t.Fatalf("ParseAmount(%q) error = %v, want an error %v", tt.s, err, tt.invalid)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseAmount(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+// day function returns the local midnight of a date, like the dates of the transactions read.
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+// equal function compares the transactions read with the expected ones, field by field.
+func equal(t *testing.T, got, want []db.Transaction) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%d transactions, want %d: %+v", len(got), len(want), got)
+	}
+
+	for i := range want {
+		if !got[i].Date.Equal(want[i].Date) {
+			t.Errorf("transaction %d: date %s, want %s", i, got[i].Date, want[i].Date)
+		}
+
+		got[i].Date = want[i].Date
+		if got[i] != want[i] {
+			t.Errorf("transaction %d:\n got %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// bank package CAMT.053 statements functions
+package bank
+
+import (
+	"aio/pkg/db"
+	"encoding/xml"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// camtDocument struct represents a CAMT.053 file, the names of the elements are the same in every version of the standard.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// camtStatement struct represents a statement of an account.
+type camtStatement struct {
+	IBAN    string      `xml:"Acct>Id>IBAN"`
+	Other   string      `xml:"Acct>Id>Othr>Id"`
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+// camtEntry struct represents an entry of a statement, a booked transaction.
+type camtEntry struct {
+	Amount struct {
+		Value    string `xml:",chardata"`
+		Currency string `xml:"Ccy,attr"`
+	} `xml:"Amt"`
+	Indicator string `xml:"CdtDbtInd"` // CRDT for an income, DBIT for an expense
+	Status    struct {
+		Text string `xml:",chardata"` // the status up to the version 7
+		Code string `xml:"Cd"`        // the status from the version 8
+	} `xml:"Sts"`
+	BookingDate     string        `xml:"BookgDt>Dt"`
+	BookingDateTime string        `xml:"BookgDt>DtTm"`
+	ValueDate       string        `xml:"ValDt>Dt"`
+	Ref             string        `xml:"AcctSvcrRef"`
+	Info            string        `xml:"AddtlNtryInf"`
+	Details         []camtDetails `xml:"NtryDtls>TxDtls"`
+}
+
+// camtDetails struct represents the details of a transaction of an entry.
+type camtDetails struct {
+	Remittance  []string `xml:"RmtInf>Ustrd"`
+	Creditor    string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorPty string   `xml:"RltdPties>Cdtr>Pty>Nm"` // from the version 8
+	Debtor      string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorPty   string   `xml:"RltdPties>Dbtr>Pty>Nm"` // from the version 8
+}
+
+// camtNoRef are the references written by the banks that do not give one, they do not identify the entry.
+var camtNoRef = []string{"NOTPROVIDED", "NONREF"}
+
+// readCAMT function reads the transactions of a CAMT.053 statement, the pending entries are skipped.
+// the payee is the creditor of an expense or the debtor of an income, the description is the remittance information.
+// the currency is the Ccy of the amount, or the fallback without it.
+func readCAMT(data []byte, fallback string) ([]db.Transaction, error) {
+	var doc camtDocument
+	err := xml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(doc.Statements) == 0 {
+		return nil, errors.New("the BkToCstmrStmt>Stmt element is missing")
+	}
+
+	transactions := []db.Transaction{}
+	for _, stmt := range doc.Statements {
+		account := stmt.IBAN
+		if account == "" {
+			account = stmt.Other
+		}
+
+		for i, e := range stmt.Entries {
+			status := strings.TrimSpace(e.Status.Text + e.Status.Code)
+			if status == "PDNG" || status == "INFO" {
+				continue
+			}
+
+			entry := "entry " + strconv.Itoa(i+1) + ": "
+			date := e.BookingDate
+			if date == "" {
+				date = e.BookingDateTime
+			}
+
+			if date == "" {
+				date = e.ValueDate
+			}
+
+			if len(date) < 10 {
+				return nil, errors.New(entry + "invalid booking date " + strconv.Quote(date))
+			}
+
+			t := db.Transaction{Account: account, Ref: strings.TrimSpace(e.Ref)}
+			if slices.Contains(camtNoRef, strings.ToUpper(t.Ref)) {
+				t.Ref = ""
+			}
+
+			t.Date, err = time.ParseInLocation("2006-01-02", date[:10], time.Local)
+			if err != nil {
+				return nil, errors.New(entry + "invalid booking date " + strconv.Quote(date))
+			}
+
+			t.Currency, err = currencyOf(e.Amount.Currency, fallback)
+			if err != nil {
+				return nil, errors.New(entry + err.Error())
+			}
+
+			// the amounts are always positive, with the dot as decimal separator
+			t.Amount, err = ParseAmount(e.Amount.Value, ".", t.Currency)
+			if err != nil {
+				return nil, errors.New(entry + err.Error())
+			}
+
+			if e.Indicator == "DBIT" {
+				t.Amount = -t.Amount
+			}
+
+			remittance := []string{}
+			for _, d := range e.Details {
+				remittance = append(remittance, d.Remittance...)
+				if t.Payee != "" {
+					continue
+				}
+
+				if e.Indicator == "DBIT" {
+					t.Payee = d.Creditor + d.CreditorPty
+				} else {
+					t.Payee = d.Debtor + d.DebtorPty
+				}
+			}
+
+			t.Description = strings.Join(remittance, " ")
+			if t.Description == "" {
+				t.Description = e.Info
+			}
+
+			transactions = append(transactions, t)
+		}
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,72 @@
+package bank
+
+import (
+	"aio/pkg/db"
+	"testing"
+	"time"
+)
+
+// camt is a CAMT.053 statement with a booked expense, a booked income, a pending entry and an entry without a reference.
+const camt = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+<BkToCstmrStmt><Stmt>
+<Acct><Id><IBAN>IT60X0542811101000000123456</IBAN></Id></Acct>
+<Ntry>
+	<Amt Ccy="EUR">2.50</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>
+	<BookgDt><Dt>2026-10-01</Dt></BookgDt><AcctSvcrRef>R1</AcctSvcrRef>
+	<NtryDtls><TxDtls><RmtInf><Ustrd>Coffee</Ustrd><Ustrd>Latte</Ustrd></RmtInf><RltdPties><Cdtr><Nm>Bar</Nm></Cdtr></RltdPties></TxDtls></NtryDtls>
+</Ntry>
+<Ntry>
+	<Amt Ccy="CHF">2100</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts><Cd>BOOK</Cd></Sts>
+	<BookgDt><DtTm>2026-10-02T09:00:00</DtTm></BookgDt><AcctSvcrRef>R2</AcctSvcrRef>
+	<NtryDtls><TxDtls><RltdPties><Dbtr><Pty><Nm>ACME</Nm></Pty></Dbtr></RltdPties></TxDtls></NtryDtls>
+	<AddtlNtryInf>Salary</AddtlNtryInf>
+</Ntry>
+<Ntry>
+	<Amt Ccy="EUR">99.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>PDNG</Sts>
+	<BookgDt><Dt>2026-10-03</Dt></BookgDt>
+</Ntry>
+<Ntry>
+	<Amt>10.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>
+	<ValDt><Dt>2026-10-04</Dt></ValDt><AcctSvcrRef>NOTPROVIDED</AcctSvcrRef>
+	<AddtlNtryInf>Fee</AddtlNtryInf>
+</Ntry>
+</Stmt></BkToCstmrStmt>
+</Document>
+`
+
+func TestReadCAMT(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []db.Transaction
+		invalid bool
+	}{
+		{
+			name: "statement",
+			data: camt,
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 1), Amount: -250, Currency: "EUR", Description: "Coffee Latte", Payee: "Bar", Account: "IT60X0542811101000000123456", Ref: "R1"},
+				{Date: day(2026, time.October, 2), Amount: 210000, Currency: "CHF", Description: "Salary", Payee: "ACME", Account: "IT60X0542811101000000123456", Ref: "R2"},
+				{Date: day(2026, time.October, 4), Amount: -1000, Currency: "EUR", Description: "Fee", Account: "IT60X0542811101000000123456"},
+			},
+		},
+		{name: "not a statement", data: "<Document></Document>", invalid: true},
+		{name: "not xml", data: "Date,Amount", invalid: true},
+		{name: "no booking date", data: "<Document><BkToCstmrStmt><Stmt><Ntry><Amt>1</Amt></Ntry></Stmt></BkToCstmrStmt></Document>", invalid: true},
+		{name: "invalid amount", data: "<Document><BkToCstmrStmt><Stmt><Ntry><Amt>one</Amt><BookgDt><Dt>2026-10-01</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCAMT([]byte(tt.data), "EUR")
+			if (err != nil) != tt.invalid {
+				t.Fatalf("error = %v, want an error %v", err, tt.invalid)
+			}
+
+			if !tt.invalid {
+				equal(t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// bank package csv statements functions
+package bank
+
+import (
+	"aio/pkg/db"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV struct represents the mapping of the columns of a csv statement, and how its values are written.
+// a column is chosen by the name in its header or by its position, starting from 1.
+// the empty columns are detected from the header, by their usual names in english, italian, german and spanish.
+// the amount is a single column, or the difference of a credit and a debit column.
+type CSV struct {
+	Date        string
+	Amount      string
+	Debit       string // the column of the expenses, used without an amount column
+	Credit      string // the column of the incomes, used without an amount column
+	Description string
+	Payee       string
+	Currency    string // the column of the currencies of the amounts, detected if empty
+	DateFormat  string // the layout of the dates, e.g. 02/01/2006, detected if empty
+	Decimal     string // the decimal separator of the amounts, "." or ",", detected if empty
+	Delimiter   string // the separator of the columns, detected if empty
+}
+
+// headers are the usual names of the columns of the csv statements, lower case without the text in brackets.
+var headers = map[string][]string{
+	"date":        {"date", "booking date", "transaction date", "posting date", "posted date", "value date", "data", "data operazione", "data contabile", "data valuta", "buchungstag", "buchungsdatum", "datum", "fecha", "fecha operación"},
+	"amount":      {"amount", "transaction amount", "importo", "betrag", "importe", "montant"},
+	"debit":       {"debit", "debit amount", "debits", "withdrawal", "withdrawals", "money out", "paid out", "addebiti", "uscite", "soll", "cargo"},
+	"credit":      {"credit", "credit amount", "credits", "deposit", "deposits", "money in", "paid in", "accrediti", "entrate", "haben", "abono"},
+	"description": {"description", "details", "memo", "narrative", "reference", "transaction description", "causale", "descrizione", "descrizione operazione", "verwendungszweck", "buchungstext", "concepto", "libellé"},
+	"currency":    {"currency", "ccy", "valuta", "divisa", "währung", "waehrung", "moneda", "devise"},
+	"payee":       {"payee", "name", "counterparty", "beneficiary", "merchant", "recipient", "beneficiario", "controparte", "empfänger", "auftraggeber/empfänger", "beguenstigter/zahlungspflichtiger", "beneficiario/ordenante"},
+}
+
+// brackets matches the text in brackets of a header, e.g. the currency of "Amount (EUR)".
+var brackets = regexp.MustCompile(`\s*[(\[].*?[)\]]`)
+
+// dateFormats are the layouts of the dates tried when the layout is not given, the day before the month.
+var dateFormats = []string{
+	"2006-01-02", "02/01/2006", "01/02/2006", "02.01.2006", "2006/01/02", "02-01-2006",
+	"2/1/2006", "1/2/2006", "02/01/06", "01/02/06", "02.01.06", "2.1.2006",
+	"2006-01-02 15:04:05", "2006-01-02T15:04:05", "02 Jan 2006", "2 Jan 2006", "Jan 2, 2006", "20060102",
+}
+
+// header function normalizes a header of a csv statement.
+func header(s string) string {
+	s = strings.TrimPrefix(s, "\ufeff") // the byte order mark of the files saved by the spreadsheets
+	return strings.ToLower(strings.TrimSpace(brackets.ReplaceAllString(s, "")))
+}
+
+// column function returns the index of a column of the header, chosen by name or by position, or detected by its usual names.
+// it returns -1 if the column is not found.
+func column(head []string, choice, field string) (int, error) {
+	if choice != "" {
+		if n, err := strconv.Atoi(choice); err == nil {
+			if n < 1 || n > len(head) {
+				return -1, errors.New("the " + field + " column " + choice + " is out of range, the statement has " + strconv.Itoa(len(head)) + " columns")
+			}
+			return n - 1, nil
+		}
+
+		for i, h := range head {
+			if header(h) == header(choice) {
+				return i, nil
+			}
+		}
+		return -1, errors.New("the " + field + " column " + strconv.Quote(choice) + " is not in the header: " + strings.Join(head, ", "))
+	}
+
+	for i, h := range head {
+		for _, name := range headers[field] {
+			if header(h) == name {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// delimiter function returns the separator of the columns of a csv statement, the one repeated the most in a line
+// of its first ten lines, usually the header: the title lines of some statements have no separators, or a few.
+func delimiter(data []byte) rune {
+	best, count := ',', 0
+	for i, line := range bytes.SplitN(data, []byte("\n"), 11) {
+		if i == 10 {
+			break
+		}
+
+		for _, d := range []rune{',', ';', '\t', '|'} {
+			if n := bytes.Count(line, []byte(string(d))); n > count {
+				best, count = d, n
+			}
+		}
+	}
+	return best
+}
+
+// dateFormat function returns the first layout of the dates that parses every date.
+func dateFormat(dates []string) (string, error) {
+	for _, layout := range dateFormats {
+		ok := true
+		for _, d := range dates {
+			if _, err := time.ParseInLocation(layout, d, time.Local); err != nil {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			return layout, nil
+		}
+	}
+
+	example := ""
+	if len(dates) > 0 {
+		example = ", e.g. " + strconv.Quote(dates[0])
+	}
+	return "", errors.New("unknown format of the dates" + example + ", choose it with --date-format, e.g. 02/01/2006")
+}
+
+// isDate function returns true if s is a date in the given layout, or in one of the known layouts if layout is empty.
+func isDate(s, layout string) bool {
+	if layout == "" {
+		_, err := dateFormat([]string{strings.TrimSpace(s)})
+		return err == nil
+	}
+
+	_, err := time.ParseInLocation(layout, strings.TrimSpace(s), time.Local)
+	return err == nil
+}
+
+// readCSV function reads the transactions of a csv statement.
+// the header is the first line with the date column, the lines before it are skipped, like the title of some statements.
+// without a header, the columns must be chosen by their position. without a currency column, the amounts are in the fallback currency.
+func readCSV(data []byte, opts CSV, fallback string) ([]db.Transaction, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = delimiter(data)
+	if opts.Delimiter != "" {
+		r.Comma = []rune(opts.Delimiter)[0]
+	}
+	r.FieldsPerRecord = -1 // the title lines have fewer columns
+	r.LazyQuotes = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// the header, the first line with the date column, or no header if the columns are chosen by their position
+	start, date := 0, -1
+	for i, record := range records {
+		date, err = column(record, opts.Date, "date")
+		if err == nil && date >= 0 {
+			start = i
+			break
+		}
+	}
+
+	if date < 0 {
+		return nil, errors.New("the date column is not found, choose it with --date-column")
+	}
+
+	head := records[start]
+	idx := map[string]int{"date": date}
+	for field, choice := range map[string]string{"amount": opts.Amount, "debit": opts.Debit, "credit": opts.Credit, "description": opts.Description, "payee": opts.Payee, "currency": opts.Currency} {
+		idx[field], err = column(head, choice, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if idx["amount"] < 0 && idx["debit"] < 0 && idx["credit"] < 0 {
+		return nil, errors.New("the amount column is not found, choose it with --amount-column, or --debit-column and --credit-column")
+	}
+
+	// the first line is data if its date is a date, with the columns chosen by their position
+	rows := records[start+1:]
+	if date < len(head) && isDate(head[date], opts.DateFormat) {
+		rows = records[start:]
+	}
+
+	cell := func(row []string, field string) string {
+		if i := idx[field]; i >= 0 && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	// the empty lines and the totals at the end of some statements have no date
+	dates, amounts := []string{}, []string{}
+	valid := [][]string{}
+	for _, row := range rows {
+		if cell(row, "date") == "" {
+			continue
+		}
+
+		valid = append(valid, row)
+		dates = append(dates, cell(row, "date"))
+		amounts = append(amounts, cell(row, "amount"), cell(row, "debit"), cell(row, "credit"))
+	}
+
+	layout := opts.DateFormat
+	if layout == "" {
+		layout, err = dateFormat(dates)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decimal := opts.Decimal
+	if decimal == "" {
+		decimal = guessDecimal(amounts...)
+	}
+
+	transactions := []db.Transaction{}
+	for i, row := range valid {
+		line := "row " + strconv.Itoa(i+1) + ": "
+		t := db.Transaction{Description: cell(row, "description"), Payee: cell(row, "payee")}
+		t.Date, err = time.ParseInLocation(layout, cell(row, "date"), time.Local)
+		if err != nil {
+			return nil, errors.New(line + "invalid date " + strconv.Quote(cell(row, "date")) + ", expected the format " + layout)
+		}
+
+		t.Currency, err = currencyOf(cell(row, "currency"), fallback)
+		if err != nil {
+			return nil, errors.New(line + err.Error())
+		}
+
+		if idx["amount"] >= 0 {
+			t.Amount, err = ParseAmount(cell(row, "amount"), decimal, t.Currency)
+			if err != nil {
+				return nil, errors.New(line + err.Error())
+			}
+		} else {
+			for field, sign := range map[string]int64{"credit": 1, "debit": -1} {
+				if cell(row, field) == "" {
+					continue
+				}
+
+				n, err := ParseAmount(cell(row, field), decimal, t.Currency)
+				if err != nil {
+					return nil, errors.New(line + err.Error())
+				}
+
+				// the debits are written as positive or as negative amounts
+				if n < 0 {
+					n = -n
+				}
+				t.Amount += sign * n
+			}
+		}
+
+		t.Date = time.Date(t.Date.Year(), t.Date.Month(), t.Date.Day(), 0, 0, 0, 0, time.Local)
+		transactions = append(transactions, t)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,123 @@
+package bank
+
+import (
+	"aio/pkg/db"
+	"testing"
+	"time"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		opts    CSV
+		want    []db.Transaction
+		invalid bool
+	}{
+		{
+			name: "header detected",
+			data: "Date,Description,Amount\n2026-10-01,Coffee,-2.50\n2026-10-02,Salary,2100.00\n",
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 1), Amount: -250, Currency: "EUR", Description: "Coffee"},
+				{Date: day(2026, time.October, 2), Amount: 210000, Currency: "EUR", Description: "Salary"},
+			},
+		},
+		{
+			name: "italian statement with a title",
+			data: "Estratto conto\n\nData;Descrizione;Importo;Divisa\n01/10/2026;Caffè;-1.234,50;EUR\n02/10/2026;Ricarica;5;CHF\n",
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 1), Amount: -123450, Currency: "EUR", Description: "Caffè"},
+				{Date: day(2026, time.October, 2), Amount: 500, Currency: "CHF", Description: "Ricarica"},
+			},
+		},
+		{
+			name: "debit and credit columns",
+			data: "Posted Date,Payee,Details,Money Out,Money In\n13/10/2026,Bar,Coffee,2.50,\n14/10/2026,ACME,Salary,,2100.00\n15/10/2026,Shop,Refund,-10.00,\n",
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 13), Amount: -250, Currency: "EUR", Description: "Coffee", Payee: "Bar"},
+				{Date: day(2026, time.October, 14), Amount: 210000, Currency: "EUR", Description: "Salary", Payee: "ACME"},
+				{Date: day(2026, time.October, 15), Amount: -1000, Currency: "EUR", Description: "Refund", Payee: "Shop"},
+			},
+		},
+		{
+			name: "columns by position without header",
+			data: "01.10.2026\tCoffee\t-2,50\n02.10.2026\tLunch\t-12,00\n",
+			opts: CSV{Date: "1", Description: "2", Amount: "3"},
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 1), Amount: -250, Currency: "EUR", Description: "Coffee"},
+				{Date: day(2026, time.October, 2), Amount: -1200, Currency: "EUR", Description: "Lunch"},
+			},
+		},
+		{
+			name: "date format and decimal given",
+			data: "when,what,how much\n10/02/2026,Coffee,\"1,250\"\n",
+			opts: CSV{Date: "when", Description: "what", Amount: "how much", DateFormat: "01/02/2006", Decimal: ","},
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 2), Amount: 125, Currency: "EUR", Description: "Coffee"},
+			},
+		},
+		{
+			name: "totals without a date are skipped",
+			data: "Date,Amount\n2026-10-01,-2.50\n,-2.50\n",
+			want: []db.Transaction{{Date: day(2026, time.October, 1), Amount: -250, Currency: "EUR"}},
+		},
+		{name: "no date column", data: "Description,Amount\nCoffee,-2.50\n", invalid: true},
+		{name: "no amount column", data: "Date,Description\n2026-10-01,Coffee\n", invalid: true},
+		{name: "column out of range", data: "Date,Amount\n2026-10-01,-2.50\n", opts: CSV{Amount: "5"}, invalid: true},
+		{name: "unknown date format", data: "Date,Amount\n1st October,-2.50\n", invalid: true},
+		{name: "invalid amount", data: "Date,Amount\n2026-10-01,two\n", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCSV([]byte(tt.data), tt.opts, "EUR")
+			if (err != nil) != tt.invalid {
+				t.Fatalf("error = %v, want an error %v", err, tt.invalid)
+			}
+
+			if !tt.invalid {
+				equal(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelimiter(t *testing.T) {
+	tests := map[string]rune{
+		"a,b,c\n1;2":   ',',
+		"a;b;c\n1,2":   ';',
+		"a\tb\tc":      '\t',
+		"a|b|c":        '|',
+		"single":       ',',
+		"a;b,c;d\n1,2": ';',
+		"Statement, October\n\nDate;Description;Amount\n": ';',
+	}
+
+	for data, want := range tests {
+		if got := delimiter([]byte(data)); got != want {
+			t.Errorf("delimiter(%q) = %q, want %q", data, got, want)
+		}
+	}
+}
+
+func TestDateFormat(t *testing.T) {
+	tests := []struct {
+		dates   []string
+		want    string
+		invalid bool
+	}{
+		{dates: []string{"2026-10-01"}, want: "2006-01-02"},
+		{dates: []string{"01/10/2026", "31/10/2026"}, want: "02/01/2006"},
+		{dates: []string{"10/01/2026", "10/31/2026"}, want: "01/02/2006"},
+		{dates: []string{"1.10.2026"}, want: "2.1.2006"},
+		{dates: []string{"20261001"}, want: "20060102"},
+		{dates: []string{"2026-10-01", "01/10/2026"}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		got, err := dateFormat(tt.dates)
+		if (err != nil) != tt.invalid || got != tt.want {
+			t.Errorf("dateFormat(%q) = %q, %v, want %q", tt.dates, got, err, tt.want)
+		}
+	}
+}
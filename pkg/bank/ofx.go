@@ -0,0 +1,142 @@
+// bank package OFX and QFX statements functions
+package bank
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/utils/money"
+	"errors"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// readOFX function reads the transactions of an OFX or QFX statement.
+// the version 1 of OFX is SGML, its elements are not closed, so the file is read as a sequence of tags and values,
+// this reads the version 2, XML, too. the transactions are the STMTTRN aggregates, the account is the first ACCTID,
+// the currency is the CURDEF of the statement, or the fallback without it.
+func readOFX(data []byte, fallback string) ([]db.Transaction, error) {
+	s := string(data)
+	start := strings.Index(strings.ToUpper(s), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("the OFX element is missing")
+	}
+	s = s[start:]
+
+	transactions := []db.Transaction{}
+	account, currency := "", fallback
+	var t *db.Transaction
+	var amount string
+	var name, memo string
+
+	// end function adds the transaction being read to the list
+	end := func() error {
+		if t == nil {
+			return nil
+		}
+
+		if t.Date.IsZero() {
+			return errors.New("transaction " + t.Ref + ": the DTPOSTED element is missing")
+		}
+
+		// the amount is parsed at the end, the CURDEF of the statement may follow the transactions in a broken file
+		n, err := ParseAmount(amount, "", currency)
+		if err != nil {
+			return errors.New("transaction " + t.Ref + ": " + err.Error())
+		}
+
+		t.Amount, t.Currency = n, currency
+		t.Payee, t.Description = name, memo
+		if memo == "" {
+			t.Description = name
+		}
+
+		transactions = append(transactions, *t)
+		t = nil
+		return nil
+	}
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			break
+		}
+
+		gt := strings.IndexByte(s[lt:], '>')
+		if gt < 0 {
+			return nil, errors.New("unterminated tag")
+		}
+
+		tag := strings.ToUpper(strings.TrimSpace(s[lt+1 : lt+gt]))
+		s = s[lt+gt+1:]
+		value := s
+		if next := strings.IndexByte(s, '<'); next >= 0 {
+			value = s[:next]
+		}
+		value = html.UnescapeString(strings.TrimSpace(value))
+
+		switch tag {
+		case "STMTTRN":
+			// the previous transaction may be not closed in a broken SGML file
+			if err := end(); err != nil {
+				return nil, err
+			}
+			t = &db.Transaction{Account: account}
+			name, memo, amount = "", "", ""
+		case "/STMTTRN":
+			if err := end(); err != nil {
+				return nil, err
+			}
+		case "ACCTID":
+			if account == "" {
+				account = value
+			}
+		case "CURDEF":
+			cur, err := money.Currency(value)
+			if err != nil {
+				return nil, err
+			}
+			currency = cur
+		}
+
+		if t == nil {
+			continue
+		}
+
+		switch tag {
+		case "DTPOSTED":
+			date, err := ofxDate(value)
+			if err != nil {
+				return nil, err
+			}
+			t.Date = date
+		case "TRNAMT":
+			amount = value
+		case "FITID":
+			t.Ref = value
+		case "NAME":
+			name = value
+		case "MEMO":
+			memo = value
+		}
+	}
+
+	if err := end(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// ofxDate function parses a date of an OFX file, e.g. 20240115 or 20240115120000.000[-5:EST], only the day is kept.
+func ofxDate(s string) (time.Time, error) {
+	if len(s) < 8 {
+		return time.Time{}, errors.New("invalid date " + strconv.Quote(s))
+	}
+
+	t, err := time.ParseInLocation("20060102", s[:8], time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("invalid date " + strconv.Quote(s))
+	}
+	return t, nil
+}
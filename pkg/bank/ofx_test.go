@@ -0,0 +1,78 @@
+package bank
+
+import (
+	"aio/pkg/db"
+	"testing"
+	"time"
+)
+
+// ofxSGML is an OFX version 1 statement, its elements are not closed.
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKACCTFROM><BANKID>123<ACCTID>9876<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20261001120000.000[-5:EST]<TRNAMT>-2.50<FITID>T1<NAME>Coffee &amp; Co<MEMO>Latte</STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20261002<TRNAMT>2,100.00<FITID>T2<NAME>ACME Payroll
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+// ofxXML is an OFX version 2 statement, without the currency.
+const ofxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+<CCACCTFROM><ACCTID>4111</ACCTID></CCACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><DTPOSTED>20261005</DTPOSTED><TRNAMT>-12,50</TRNAMT><FITID>C1</FITID><NAME>Shop</NAME></STMTTRN>
+</BANKTRANLIST>
+</CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1></OFX>
+`
+
+func TestReadOFX(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []db.Transaction
+		invalid bool
+	}{
+		{
+			name: "version 1",
+			data: ofxSGML,
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 1), Amount: -250, Currency: "USD", Description: "Latte", Payee: "Coffee & Co", Account: "9876", Ref: "T1"},
+				{Date: day(2026, time.October, 2), Amount: 210000, Currency: "USD", Description: "ACME Payroll", Payee: "ACME Payroll", Account: "9876", Ref: "T2"},
+			},
+		},
+		{
+			name: "version 2 without currency",
+			data: ofxXML,
+			want: []db.Transaction{
+				{Date: day(2026, time.October, 5), Amount: -1250, Currency: "EUR", Description: "Shop", Payee: "Shop", Account: "4111", Ref: "C1"},
+			},
+		},
+		{name: "no OFX element", data: "OFXHEADER:100\n", invalid: true},
+		{name: "no date", data: "<OFX><STMTTRN><TRNAMT>1.00<FITID>X</STMTTRN></OFX>", invalid: true},
+		{name: "invalid date", data: "<OFX><STMTTRN><DTPOSTED>2026<TRNAMT>1.00</STMTTRN></OFX>", invalid: true},
+		{name: "invalid amount", data: "<OFX><STMTTRN><DTPOSTED>20261001<TRNAMT>one</STMTTRN></OFX>", invalid: true},
+		{name: "unterminated tag", data: "<OFX><STMTTRN", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readOFX([]byte(tt.data), "EUR")
+			if (err != nil) != tt.invalid {
+				t.Fatalf("error = %v, want an error %v", err, tt.invalid)
+			}
+
+			if !tt.invalid {
+				equal(t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// bank package exchange rates files functions
+package bank
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateHeaders are the usual names of the columns of the exchange rates files in the long format, one rate per line.
+var rateHeaders = map[string][]string{
+	"date":  {"date", "day", "time_period"},
+	"base":  {"base", "from", "base currency", "source"},
+	"quote": {"quote", "to", "currency", "quote currency", "target"},
+	"rate":  {"rate", "exchange rate", "value", "obs_value"},
+}
+
+// ReadRates function reads the exchange rates of a csv file, in the long format, with a rate per line:
+//
+//	date,base,quote,rate
+//	2026-01-02,EUR,USD,1.0321
+//
+// or in the wide format of the European Central Bank, with a column per currency and the rates of a date per line,
+// the rates of the base currency given here:
+//
+//	Date,USD,JPY,GBP
+//	2026-01-02,1.0321,162.74,0.8284
+//
+// the long format without a base column has the rates of the base currency given here too.
+// the empty rates, or N/A like the holidays of the ECB files, are skipped.
+func ReadRates(path, base string) ([]db.Rate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Err("failed to read the exchange rates", "file", path)
+		return nil, err
+	}
+
+	rates, err := readRates(data, base)
+	if err != nil {
+		return nil, errors.Join(ErrInvalid, errors.New(path+" can't be read as exchange rates"), err)
+	}
+
+	return rates, nil
+}
+
+// readRates function reads the exchange rates of a csv file, in the long or in the wide format.
+func readRates(data []byte, base string) ([]db.Rate, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = delimiter(data)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("the file has no rates")
+	}
+
+	head := records[0]
+	idx := map[string]int{}
+	for field, names := range rateHeaders {
+		idx[field] = -1
+		for i, h := range head {
+			for _, name := range names {
+				if header(h) == name && idx[field] < 0 {
+					idx[field] = i
+				}
+			}
+		}
+	}
+
+	if idx["date"] < 0 {
+		return nil, errors.New("the date column is not found, the first line must be the header, e.g. date,base,quote,rate")
+	}
+
+	dates := []string{}
+	for _, record := range records[1:] {
+		if idx["date"] < len(record) && strings.TrimSpace(record[idx["date"]]) != "" {
+			dates = append(dates, strings.TrimSpace(record[idx["date"]]))
+		}
+	}
+
+	layout, err := dateFormat(dates)
+	if err != nil {
+		return nil, err
+	}
+
+	// the columns of the currencies, the base and the quote of the long format, or the quote of every column of the wide one
+	long := idx["rate"] >= 0 && idx["quote"] >= 0
+	quotes := map[int]string{}
+	if !long {
+		base, err = money.Currency(base)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, h := range head {
+			if i == idx["date"] || strings.TrimSpace(h) == "" {
+				continue
+			}
+
+			quotes[i], err = money.Currency(h)
+			if err != nil {
+				return nil, errors.New("the column " + strconv.Quote(h) + " is not a currency, the header of the wide format is the date and the currencies, e.g. Date,USD,JPY")
+			}
+		}
+	}
+
+	rates := []db.Rate{}
+	for n, record := range records[1:] {
+		line := "row " + strconv.Itoa(n+1) + ": "
+		cell := func(i int) string {
+			if i >= 0 && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		if cell(idx["date"]) == "" {
+			continue
+		}
+
+		date, err := time.ParseInLocation(layout, cell(idx["date"]), time.Local)
+		if err != nil {
+			return nil, errors.New(line + "invalid date " + strconv.Quote(cell(idx["date"])) + ", expected the format " + layout)
+		}
+
+		if long {
+			from := base
+			if idx["base"] >= 0 {
+				from = cell(idx["base"])
+			}
+			base, err := money.Currency(from)
+			if err != nil {
+				return nil, errors.New(line + err.Error())
+			}
+
+			rate, err := rateOf(line, base, cell(idx["quote"]), cell(idx["rate"]), date)
+			if err != nil {
+				return nil, err
+			}
+
+			if rate != nil {
+				rates = append(rates, *rate)
+			}
+			continue
+		}
+
+		for i, quote := range quotes {
+			rate, err := rateOf(line, base, quote, cell(i), date)
+			if err != nil {
+				return nil, err
+			}
+
+			if rate != nil {
+				rates = append(rates, *rate)
+			}
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("the file has no rates")
+	}
+
+	return rates, nil
+}
+
+// rateOf function returns the exchange rate of a cell, or nil if the cell is empty or N/A.
+func rateOf(line, base, quote, value string, date time.Time) (*db.Rate, error) {
+	if value == "" || strings.EqualFold(value, "N/A") || value == "-" {
+		return nil, nil
+	}
+
+	quote, err := money.Currency(quote)
+	if err != nil {
+		return nil, errors.New(line + err.Error())
+	}
+
+	rate, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil || rate <= 0 {
+		return nil, errors.New(line + "invalid rate " + strconv.Quote(value) + " from " + base + " to " + quote)
+	}
+
+	if quote == base {
+		return nil, nil
+	}
+
+	return &db.Rate{Date: date, Base: base, Quote: quote, Rate: rate}, nil
+}
@@ -0,0 +1,463 @@
+// config package loads the settings of aio from the config file.
+// the settings have a default value, that can be changed in the config file (config.toml in the config directory)
+// and overridden by an environment variable, named after the key: log.level is overridden by AIO_LOG_LEVEL.
+package config
+
+import (
+	"aio/pkg/utils/fs"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config struct represents the settings of aio.
+type Config struct {
+	Log       Log       `toml:"log"`
+	Sync      Sync      `toml:"sync"`
+	Rewards   Rewards   `toml:"rewards"`
+	Reminders Reminders `toml:"reminders"`
+	Money     Money     `toml:"money"`
+	Colors    Colors    `toml:"colors"`
+	Jobs      []Job     `toml:"jobs"`
+}
+
+// Log struct represents the settings of the log files.
+type Log struct {
+	Level     string `toml:"level"`     // minimum level of the messages: debug, info, warn, error, fatal
+	Format    string `toml:"format"`    // format of the log files, text or json
+	MaxSize   int64  `toml:"max_size"`  // maximum size of a log file in megabytes, before it is rotated
+	Retention int    `toml:"retention"` // number of days the rotated log files are kept
+}
+
+// Sync struct represents the settings of the synchronization with the remote repository.
+type Sync struct {
+	Interval string `toml:"interval"` // time between two pushes of the cron service, e.g. 5m
+	Host     string `toml:"host"`     // prefix of the remote repositories linked with aio -l
+}
+
+// Rewards struct represents the rewards earned by the character.
+type Rewards struct {
+	TaskXP        int `toml:"task_xp"`        // experience points earned when a task is completed
+	BirthdayCoins int `toml:"birthday_coins"` // coins gifted on the birthday of the character
+	AnniversaryXP int `toml:"anniversary_xp"` // experience points gifted on the anniversary of the creation of the character
+}
+
+// Reminders struct represents the settings of the reminders of the tasks and habits.
+type Reminders struct {
+	LeadTimes []string `toml:"lead_times"` // times before the due date when a reminder is shown, e.g. 1h
+}
+
+// Money struct represents the settings of the finance ledger.
+type Money struct {
+	Currency       string `toml:"currency"`        // base currency of the new characters, and of the amounts of the versions before the currencies
+	RenewalWarning int    `toml:"renewal_warning"` // days before the renewal of a subscription when a warning is shown, 0 to disable
+	OverspendHP    int    `toml:"overspend_hp"`    // health points lost when the budget of a category is overspent in a month
+	OverspendKarma int    `toml:"overspend_karma"` // karma lost when the budget of a category is overspent in a month
+}
+
+// Colors struct represents the colors of the console output, as ANSI codes (0-255) or hex codes (#ff8800).
+type Colors struct {
+	Bright string `toml:"bright"` // titles and selected values
+	Error  string `toml:"error"`  // errors
+	Change string `toml:"change"` // changed values and matches
+}
+
+// Job struct represents a job of the cron service in the config file:
+// the settings of a built in job, or a job defined by the user.
+type Job struct {
+	Name        string   `toml:"name" json:"name"`
+	Kind        string   `toml:"kind,omitempty" json:"kind,omitempty"`
+	Schedule    string   `toml:"schedule,omitempty" json:"schedule,omitempty"`
+	Enabled     *bool    `toml:"enabled,omitempty" json:"enabled,omitempty"`
+	Command     string   `toml:"command,omitempty" json:"command,omitempty"`
+	Message     string   `toml:"message,omitempty" json:"message,omitempty"`
+	Description string   `toml:"description,omitempty" json:"description,omitempty"`
+	LeadTimes   []string `toml:"lead_times,omitempty" json:"lead_times,omitempty"`
+}
+
+// header is written at the top of the config file.
+const header = `# aio config file, managed with aio config get|set|list|edit.
+# the keys not in the file have their default value, aio config list shows every key.
+# every key can be overridden by an environment variable, e.g. log.level by AIO_LOG_LEVEL.
+
+`
+
+// Default function returns the default settings.
+func Default() Config {
+	return Config{
+		Log:       Log{Level: "info", Format: "text", MaxSize: 10, Retention: 14},
+		Sync:      Sync{Interval: "5m", Host: "git@github.com:"},
+		Rewards:   Rewards{TaskXP: 10, BirthdayCoins: 100, AnniversaryXP: 100},
+		Reminders: Reminders{LeadTimes: []string{"1h", "15m"}},
+		Money:     Money{Currency: "EUR", RenewalWarning: 3, OverspendHP: 10, OverspendKarma: 1},
+		Colors:    Colors{Bright: "15", Error: "196", Change: "214"},
+		Jobs:      []Job{},
+	}
+}
+
+// the settings loaded by Load, returned by Get
+var (
+	mu      sync.Mutex
+	current *Config
+)
+
+// File function returns the path of the config file.
+func File() (string, error) {
+	return fs.ConfigPath("config.toml")
+}
+
+// ModTime function returns the last modification time of the config file, in unix nanoseconds.
+// it is used by the cron service to reload the settings when the config file changes.
+func ModTime() int64 {
+	file, err := File()
+	if err != nil {
+		return 0
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0
+	}
+
+	return info.ModTime().UnixNano()
+}
+
+// read function returns the default settings changed by the config file, without the environment variables,
+// and the keys set in the file. if the file does not exist, it is created with the default settings.
+// the jobs of the jobs.json file of the previous versions are moved to the config file.
+func read() (Config, map[string]bool, error) {
+	c := Default()
+	file, err := File()
+	if err != nil {
+		return c, nil, err
+	}
+
+	defined := map[string]bool{}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		err = Save(c)
+		if err != nil {
+			return c, nil, err
+		}
+	} else if err != nil {
+		return c, nil, errors.New("failed to read the config file: " + err.Error())
+	} else {
+		meta, err := toml.Decode(string(data), &c)
+		if err != nil {
+			return c, nil, errors.Join(errors.New("invalid config file "+file), err)
+		}
+
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return c, nil, errors.New("unknown key in the config file " + file + ": " + undecoded[0].String())
+		}
+
+		for _, k := range meta.Keys() {
+			defined[k.String()] = true
+		}
+	}
+
+	err = foldJobs(&c)
+	return c, defined, err
+}
+
+// legacyBuiltins are the settings of the built in jobs written in the jobs.json file by the previous versions.
+// they are not moved to the config file, so the built in jobs follow the settings of the config, like sync.interval.
+var legacyBuiltins = map[string]Job{
+	"push":      {Schedule: "@every 5m"},
+	"cleanlogs": {Schedule: "@every 24h"},
+	"reminders": {Schedule: "@every 1m", LeadTimes: []string{"1h", "15m"}},
+}
+
+// foldJobs function moves the jobs of the jobs.json file of the previous versions to the config file,
+// if the config file has no jobs. the jobs.json file is renamed to jobs.json.bak.
+func foldJobs(c *Config) error {
+	legacy, err := fs.ConfigPath("jobs.json")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(legacy)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(c.Jobs) == 0 {
+		jobs := []Job{}
+		err = json.Unmarshal(data, &jobs)
+		if err != nil {
+			return errors.Join(errors.New("invalid jobs config file "+legacy), err)
+		}
+
+		for _, j := range jobs {
+			if def, ok := legacyBuiltins[j.Name]; ok {
+				// keep only the settings changed by the user
+				j.Kind, j.Description = "", ""
+				if j.Schedule == def.Schedule {
+					j.Schedule = ""
+				}
+				if j.Enabled != nil && *j.Enabled {
+					j.Enabled = nil
+				}
+				if slices.Equal(j.LeadTimes, def.LeadTimes) {
+					j.LeadTimes = nil
+				}
+				if j.Schedule == "" && j.Enabled == nil && j.LeadTimes == nil {
+					continue
+				}
+			}
+			c.Jobs = append(c.Jobs, j)
+		}
+
+		err = Save(*c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(legacy, legacy+".bak")
+}
+
+// Load function reads the settings from the config file and the environment variables, and validates them.
+// the settings are kept in memory, and returned by Get until the next Load.
+func Load() (Config, error) {
+	c, _, err := read()
+	if err != nil {
+		return Default(), err
+	}
+
+	for _, key := range Keys() {
+		if value, ok := os.LookupEnv(Env(key)); ok {
+			err = c.Set(key, value)
+			if err != nil {
+				return Default(), errors.Join(errors.New("invalid value of "+Env(key)), err)
+			}
+		}
+	}
+
+	err = c.Validate()
+	if err != nil {
+		return Default(), err
+	}
+
+	mu.Lock()
+	current = &c
+	mu.Unlock()
+	return c, nil
+}
+
+// Get function returns the settings loaded by the last Load.
+// if they have not been loaded yet, they are loaded now, and the defaults are returned if they are not valid.
+func Get() Config {
+	mu.Lock()
+	c := current
+	mu.Unlock()
+	if c != nil {
+		return *c
+	}
+
+	loaded, _ := Load()
+	return loaded
+}
+
+// Save function validates the settings and writes them to the config file.
+// only the values different from the defaults are written, so a new default applies to the keys never changed.
+func Save(c Config) error {
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+
+	file, err := File()
+	if err != nil {
+		return err
+	}
+
+	defaults := Default()
+	sections := map[string]map[string]any{}
+	for _, key := range Keys() {
+		value, _ := c.Value(key)
+		if def, _ := defaults.Value(key); value == def {
+			continue
+		}
+
+		f, _ := c.field(key)
+		section, name, _ := strings.Cut(key, ".")
+		if sections[section] == nil {
+			sections[section] = map[string]any{}
+		}
+		sections[section][name] = f.Interface()
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(header)
+	err = toml.NewEncoder(buf).Encode(sections)
+	if err == nil && len(c.Jobs) > 0 {
+		buf.WriteString("\n")
+		err = toml.NewEncoder(buf).Encode(struct {
+			Jobs []Job `toml:"jobs"`
+		}{c.Jobs})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(file, buf.Bytes(), 0644)
+	if err != nil {
+		return errors.New("failed to write the config file: " + err.Error())
+	}
+
+	return nil
+}
+
+// Update function applies fn to the settings of the config file, without the environment variables,
+// and saves them. the settings in memory are reloaded.
+func Update(fn func(c *Config) error) error {
+	c, _, err := read()
+	if err != nil {
+		return err
+	}
+
+	err = fn(&c)
+	if err != nil {
+		return err
+	}
+
+	err = Save(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = Load()
+	return err
+}
+
+// Env function returns the environment variable that overrides a key, e.g. AIO_LOG_LEVEL for log.level.
+func Env(key string) string {
+	return "AIO_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Source function returns where the value of a key comes from: env, file or default.
+func Source(key string) string {
+	if _, ok := os.LookupEnv(Env(key)); ok {
+		return "env"
+	}
+
+	_, defined, err := read()
+	if err == nil && defined[key] {
+		return "file"
+	}
+
+	return "default"
+}
+
+// Keys function returns the keys of the settings, in the form section.key, e.g. log.level.
+// the jobs are not included, they are managed with the cron command or by editing the file.
+func Keys() []string {
+	keys := []string{}
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := 0; j < section.Type.NumField(); j++ {
+			keys = append(keys, tag(section)+"."+tag(section.Type.Field(j)))
+		}
+	}
+	return keys
+}
+
+// tag function returns the toml name of a field.
+func tag(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	return name
+}
+
+// field function returns the value of the field of a key.
+func (c *Config) field(key string) (reflect.Value, error) {
+	sectionName, name, ok := strings.Cut(key, ".")
+	if !ok || !slices.Contains(Keys(), key) {
+		return reflect.Value{}, errors.New("unknown key " + key + ", the keys are listed by aio config list")
+	}
+
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if tag(v.Type().Field(i)) != sectionName {
+			continue
+		}
+
+		section := v.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			if tag(section.Type().Field(j)) == name {
+				return section.Field(j), nil
+			}
+		}
+	}
+
+	return reflect.Value{}, errors.New("unknown key " + key)
+}
+
+// Value function returns the value of a key as a string, the lists are separated by commas.
+func (c Config) Value(key string) (string, error) {
+	f, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		return f.String(), nil
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(f.Int(), 10), nil
+	case reflect.Slice:
+		return strings.Join(f.Interface().([]string), ","), nil
+	default:
+		return "", errors.New("unsupported type of key " + key)
+	}
+}
+
+// Set function sets the value of a key from a string, the lists are separated by commas.
+// the value is not validated, use Validate after the changes.
+func (c *Config) Set(key, value string) error {
+	f, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(strings.TrimSpace(value))
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return errors.New("the value of " + key + " must be an integer")
+		}
+		f.SetInt(n)
+	case reflect.Slice:
+		list := []string{}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+		f.Set(reflect.ValueOf(list))
+	default:
+		return errors.New("unsupported type of key " + key)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"aio/pkg/utils/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+// home function sets a temporary home with the given config file, none if it is empty.
+func home(t *testing.T, file string) {
+	t.Helper()
+	err := fs.SetHome(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if file == "" {
+		return
+	}
+
+	path, err := File()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(file), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		key     string
+		want    string
+		source  string
+		invalid string // a part of the error, empty if the settings are valid
+	}{
+		{name: "default", key: "log.level", want: "info", source: "default"},
+		{name: "file", file: "[log]\nlevel = \"warn\"\n", key: "log.level", want: "warn", source: "file"},
+		{name: "env over default", env: map[string]string{"AIO_LOG_LEVEL": "debug"}, key: "log.level", want: "debug", source: "env"},
+		{name: "env over file", file: "[log]\nlevel = \"warn\"\n", env: map[string]string{"AIO_LOG_LEVEL": "error"}, key: "log.level", want: "error", source: "env"},
+		{name: "other keys of the file kept", file: "[log]\nlevel = \"warn\"\nretention = 30\n", env: map[string]string{"AIO_LOG_LEVEL": "error"}, key: "log.retention", want: "30", source: "file"},
+		{name: "env integer", env: map[string]string{"AIO_REWARDS_TASK_XP": " 25 "}, key: "rewards.task_xp", want: "25", source: "env"},
+		{name: "env list", env: map[string]string{"AIO_REMINDERS_LEAD_TIMES": "2h, 30m,"}, key: "reminders.lead_times", want: "2h,30m", source: "env"},
+		{name: "file list", file: "[reminders]\nlead_times = [\"1d\"]\n", key: "reminders.lead_times", invalid: "reminders.lead_times"},
+		{name: "invalid level in the file", file: "[log]\nlevel = \"loud\"\n", invalid: "log.level"},
+		{name: "invalid level in the env", file: "[log]\nlevel = \"warn\"\n", env: map[string]string{"AIO_LOG_LEVEL": "loud"}, invalid: "log.level"},
+		{name: "env not an integer", env: map[string]string{"AIO_LOG_MAX_SIZE": "10MB"}, invalid: "AIO_LOG_MAX_SIZE"},
+		{name: "interval too short", file: "[sync]\ninterval = \"30s\"\n", invalid: "sync.interval"},
+		{name: "lower case currency", env: map[string]string{"AIO_MONEY_CURRENCY": "eur"}, invalid: "money.currency"},
+		{name: "not a currency code", file: "[money]\ncurrency = \"EURO\"\n", invalid: "money.currency"},
+		{name: "negative penalty", file: "[money]\noverspend_hp = -1\n", invalid: "money.overspend_hp"},
+		{name: "hex color", file: "[colors]\nbright = \"#f80\"\n", key: "colors.bright", want: "#f80", source: "file"},
+		{name: "invalid color", env: map[string]string{"AIO_COLORS_ERROR": "256"}, invalid: "colors.error"},
+		{name: "unknown key", file: "[log]\ncolour = \"red\"\n", invalid: "unknown key"},
+		{name: "not toml", file: "[log\n", invalid: "invalid config file"},
+		{name: "job without name", file: "[[jobs]]\nschedule = \"@daily\"\n", invalid: "no name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home(t, tt.file)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			c, err := Load()
+			if tt.invalid != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.invalid) {
+					t.Fatalf("Load() error = %v, want an error about %s", err, tt.invalid)
+				}
+
+				// the defaults are returned with the error
+				if level := c.Log.Level; level != Default().Log.Level {
+					t.Errorf("log.level = %s with an invalid config, want the default", level)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := c.Value(tt.key); err != nil || got != tt.want {
+				t.Errorf("Value(%s) = %q, %v, want %q", tt.key, got, err, tt.want)
+			}
+
+			if got := Source(tt.key); got != tt.source {
+				t.Errorf("Source(%s) = %s, want %s", tt.key, got, tt.source)
+			}
+
+			if got, _ := Get().Value(tt.key); got != tt.want {
+				t.Errorf("Get().Value(%s) = %q after Load, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	home(t, "")
+
+	// the env variables are not written to the file
+	t.Setenv("AIO_LOG_FORMAT", "json")
+	err := Update(func(c *Config) error { return c.Set("log.level", "debug") })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := File()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the values different from the defaults are written
+	file := string(data)
+	if !strings.Contains(file, `level = "debug"`) || strings.Contains(file, "format") || strings.Contains(file, "retention") {
+		t.Errorf("config file:\n%s\nwant only log.level", file)
+	}
+
+	if got := Get(); got.Log.Level != "debug" || got.Log.Format != "json" {
+		t.Errorf("log = %+v after Update, want the debug level of the file and the json format of the env", got.Log)
+	}
+
+	// an invalid value is not saved
+	err = Update(func(c *Config) error { return c.Set("sync.interval", "never") })
+	if err == nil {
+		t.Error("Update() saved an invalid sync.interval")
+	}
+
+	if err := Update(func(c *Config) error { return c.Set("log.size", "1") }); err == nil {
+		t.Error("Update() set an unknown key")
+	}
+}
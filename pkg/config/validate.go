@@ -0,0 +1,99 @@
+// config package, validation of the settings
+package config
+
+import (
+	"aio/pkg/utils/money"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hexColor matches the hex colors, e.g. #ff8800 or #f80.
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Validate function checks the settings and returns an error for the first invalid value.
+func (c Config) Validate() error {
+	if !slices.Contains([]string{"debug", "info", "warn", "error", "fatal"}, c.Log.Level) {
+		return errors.New("invalid log.level " + c.Log.Level + ", valid levels: debug, info, warn, error, fatal")
+	}
+
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		return errors.New("invalid log.format " + c.Log.Format + ", valid formats: text, json")
+	}
+
+	if c.Log.MaxSize <= 0 {
+		return errors.New("log.max_size must be a positive number of megabytes")
+	}
+
+	if c.Log.Retention <= 0 {
+		return errors.New("log.retention must be a positive number of days")
+	}
+
+	interval, err := time.ParseDuration(c.Sync.Interval)
+	if err != nil || interval < time.Minute {
+		return errors.New("invalid sync.interval " + c.Sync.Interval + ", it must be a duration of at least 1m, e.g. 5m or 1h")
+	}
+
+	if c.Sync.Host == "" {
+		return errors.New("sync.host can't be empty, e.g. git@github.com:")
+	}
+
+	if c.Rewards.TaskXP < 0 {
+		return errors.New("rewards.task_xp can't be negative")
+	}
+
+	if c.Rewards.BirthdayCoins < 0 {
+		return errors.New("rewards.birthday_coins can't be negative")
+	}
+
+	if c.Rewards.AnniversaryXP < 0 {
+		return errors.New("rewards.anniversary_xp can't be negative")
+	}
+
+	for _, l := range c.Reminders.LeadTimes {
+		d, err := time.ParseDuration(l)
+		if err != nil || d <= 0 {
+			return errors.New("invalid reminders.lead_times " + l + ", it must be a positive duration, e.g. 1h or 15m")
+		}
+	}
+
+	if _, err := money.Currency(c.Money.Currency); err != nil || strings.ToUpper(c.Money.Currency) != c.Money.Currency {
+		return errors.New("invalid money.currency " + c.Money.Currency + ", it must be a three letters ISO 4217 code, e.g. EUR or USD")
+	}
+
+	if c.Money.RenewalWarning < 0 {
+		return errors.New("money.renewal_warning can't be negative")
+	}
+
+	if c.Money.OverspendHP < 0 {
+		return errors.New("money.overspend_hp can't be negative")
+	}
+
+	if c.Money.OverspendKarma < 0 {
+		return errors.New("money.overspend_karma can't be negative")
+	}
+
+	colors := map[string]string{"colors.bright": c.Colors.Bright, "colors.error": c.Colors.Error, "colors.change": c.Colors.Change}
+	for key, color := range colors {
+		if n, err := strconv.Atoi(color); (err != nil || n < 0 || n > 255) && !hexColor.MatchString(color) {
+			return errors.New("invalid " + key + " " + color + ", it must be an ANSI code (0-255) or a hex code (#ff8800)")
+		}
+	}
+
+	names := map[string]bool{}
+	for _, j := range c.Jobs {
+		if j.Name == "" {
+			return errors.New("a job in the config file has no name")
+		}
+
+		if names[j.Name] {
+			return errors.New("the job " + j.Name + " is defined twice in the config file")
+		}
+		names[j.Name] = true
+	}
+
+	return nil
+}
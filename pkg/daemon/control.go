@@ -0,0 +1,148 @@
+// daemon package, control socket functions
+package daemon
+
+import (
+	"aio/pkg/log"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// JobStatus struct represents the state of a job of the cron service.
+type JobStatus struct {
+	Name    string    `json:"name"`
+	Next    time.Time `json:"next"`
+	Prev    time.Time `json:"prev"`
+	Running bool      `json:"running"`
+}
+
+// Status struct represents the state of the cron service, returned by the control socket.
+type Status struct {
+	Pid       int         `json:"pid"`
+	StartedAt time.Time   `json:"started_at"`
+	Jobs      []JobStatus `json:"jobs"`
+}
+
+// Handler interface represents the cron service, controlled through the socket.
+type Handler interface {
+	Status() Status
+	Stop()
+}
+
+// response struct represents the response of the control socket.
+type response struct {
+	Status *Status `json:"status,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Listen function opens the control socket and serves the requests with the handler.
+// the requests are single lines: "status" returns the state of the cron service, "stop" stops it.
+// it returns a function that closes the socket.
+func Listen(h Handler) (func(), error) {
+	file, err := SocketFile()
+	if err != nil {
+		return nil, err
+	}
+
+	os.Remove(file) // remove the socket left by a crashed cron service, the pid file lock is already held
+	ln, err := net.Listen("unix", file)
+	if err != nil {
+		log.Err("failed to open the control socket", "file", file)
+		return nil, err
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				log.Err("failed to accept a control connection", "err", err)
+				continue
+			}
+			go serve(conn, h)
+		}
+	}()
+
+	return func() {
+		ln.Close()
+		os.Remove(file)
+	}, nil
+}
+
+// serve function handles a single control request.
+func serve(conn net.Conn, h Handler) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		log.Err("failed to read the control request", "err", err)
+		return
+	}
+
+	res := response{}
+	switch strings.TrimSpace(line) {
+	case "status":
+		s := h.Status()
+		res.Status = &s
+	case "stop":
+		s := h.Status()
+		res.Status = &s
+		defer h.Stop() // stop after the response is sent
+	default:
+		res.Error = "unknown request: " + strings.TrimSpace(line)
+	}
+
+	err = json.NewEncoder(conn).Encode(res)
+	if err != nil {
+		log.Err("failed to write the control response", "err", err)
+	}
+}
+
+// request function sends a request to the control socket and returns the state of the cron service.
+func request(req string) (*Status, error) {
+	file, err := SocketFile()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("unix", file, 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err = conn.Write([]byte(req + "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	res := response{}
+	err = json.NewDecoder(conn).Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+
+	return res.Status, nil
+}
+
+// GetStatus function returns the state of the running cron service.
+func GetStatus() (*Status, error) {
+	if _, ok := Running(); !ok {
+		return nil, ErrNotRunning
+	}
+
+	return request("status")
+}
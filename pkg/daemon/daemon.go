@@ -0,0 +1,228 @@
+// daemon package manages the lifecycle of the cron service.
+// the cron service writes its pid in a pid file, used as a lock to run a single instance,
+// and listens on a unix socket, used by the cli to control it.
+package daemon
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/cmd"
+	"aio/pkg/utils/fs"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRunning is returned when the cron service is already running.
+var ErrRunning = errors.New("cron service already running")
+
+// ErrNotRunning is returned when the cron service is not running.
+var ErrNotRunning = errors.New("cron service not running")
+
+// startTimeout is the time waited for the cron service to write its pid file after the start.
+const startTimeout = 3 * time.Second
+
+// stopTimeout is the time waited for the cron service to complete the running jobs and exit.
+const stopTimeout = 2 * time.Minute
+
+// PidFile function returns the path of the pid file of the cron service.
+func PidFile() (string, error) {
+	return fs.StatePath("cron.pid")
+}
+
+// SocketFile function returns the path of the control socket of the cron service.
+func SocketFile() (string, error) {
+	return fs.StatePath("cron.sock")
+}
+
+// Binary function returns the path of the cron service binary.
+func Binary() (string, error) {
+	return fs.BinPath("cron")
+}
+
+// readPid function returns the pid written in the pid file, 0 if the file does not exist.
+func readPid() (int, error) {
+	file, err := PidFile()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, errors.New("invalid pid file: " + file)
+	}
+
+	return pid, nil
+}
+
+// Running function returns the pid of the cron service and true if it is running.
+func Running() (int, bool) {
+	pid, err := readPid()
+	if err != nil || pid == 0 {
+		return 0, false
+	}
+
+	return pid, cmd.Alive(pid)
+}
+
+// Lock function writes the pid of the current process in the pid file.
+// the file is created exclusively, so only one cron service can run at the same time.
+// a pid file left by a crashed cron service is replaced.
+// it returns a function that removes the pid file, to be called when the cron service exits.
+func Lock() (func(), error) {
+	file, err := PidFile()
+	if err != nil {
+		return nil, err
+	}
+
+	for range 2 {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			f.Close()
+			if err != nil {
+				os.Remove(file)
+				return nil, err
+			}
+
+			return func() { os.Remove(file) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if pid, ok := Running(); ok {
+			return nil, errors.Join(ErrRunning, errors.New("pid "+strconv.Itoa(pid)))
+		}
+
+		log.Warn("removing stale pid file", "file", file)
+		os.Remove(file)
+	}
+
+	return nil, errors.New("failed to lock the pid file: " + file)
+}
+
+// waitFor function polls cond until it is true or the timeout expires.
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return cond()
+}
+
+// Start function starts the cron service in background, detached from the current process.
+// the cron service is started with the system sleep inhibited, if it does not start,
+// it is started again without the sleep inhibition.
+// if the cron service is installed as a system service, it is started by the service manager.
+// it returns the pid of the cron service.
+func Start() (int, error) {
+	if pid, ok := Running(); ok {
+		return pid, ErrRunning
+	}
+
+	if Installed() {
+		err := serviceStart()
+		if err != nil {
+			return 0, err
+		}
+
+		if waitFor(startTimeout, func() bool { _, ok := Running(); return ok }) {
+			pid, _ := Running()
+			log.Info("started cron service with the service manager", "pid", pid)
+			return pid, nil
+		}
+
+		return 0, errors.New("cron service did not start, check the service manager logs")
+	}
+
+	bin, err := Binary()
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.Chmod(bin, 0755)
+	if err != nil {
+		log.Err("failed to change cron binary permissions")
+		return 0, err
+	}
+
+	_, err = cmd.StartBinaryWithInhibitSystemSleep(bin)
+	if err != nil {
+		return 0, err
+	}
+
+	if waitFor(startTimeout, func() bool { _, ok := Running(); return ok }) {
+		pid, _ := Running()
+		log.Info("started cron service", "pid", pid)
+		return pid, nil
+	}
+
+	log.Warn("cron service not started with the sleep inhibition, starting it without")
+	_, err = cmd.StartDetached(bin)
+	if err != nil {
+		return 0, err
+	}
+
+	if waitFor(startTimeout, func() bool { _, ok := Running(); return ok }) {
+		pid, _ := Running()
+		log.Info("started cron service", "pid", pid)
+		return pid, nil
+	}
+
+	return 0, errors.New("cron service did not start, check the log files")
+}
+
+// Stop function stops the cron service gracefully and waits for it to exit.
+// if the cron service is installed as a system service, it is stopped by the service manager,
+// otherwise the stop is requested through the control socket.
+func Stop() error {
+	if _, ok := Running(); !ok {
+		return ErrNotRunning
+	}
+
+	if Installed() {
+		return serviceStop()
+	}
+
+	return stopProcess()
+}
+
+// stopProcess function stops the cron service process gracefully and waits for it to exit.
+// the stop is requested through the control socket, or with a SIGTERM if the socket is not available.
+func stopProcess() error {
+	pid, ok := Running()
+	if !ok {
+		return ErrNotRunning
+	}
+
+	_, err := request("stop")
+	if err != nil {
+		log.Warn("control socket not available, terminating the cron service", "err", err)
+		err = cmd.Terminate(pid)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !waitFor(stopTimeout, func() bool { return !cmd.Alive(pid) }) {
+		return errors.New("cron service did not stop in time, pid " + strconv.Itoa(pid))
+	}
+
+	log.Info("stopped cron service", "pid", pid)
+	return nil
+}
@@ -0,0 +1,74 @@
+// daemon package, migration of the data kept next to the executable
+package daemon
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/cmd"
+	"aio/pkg/utils/fs"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// stopLegacy function stops the cron service started by a previous version,
+// that keeps its pid file next to the executable, and removes its pid and socket files.
+func stopLegacy(dir string) error {
+	pidFile := filepath.Join(dir, "cron.pid")
+	data, err := os.ReadFile(pidFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err == nil && pid != os.Getpid() && cmd.Alive(pid) {
+		log.Info("stopping the cron service of the previous version", "pid", pid)
+		err = cmd.Terminate(pid)
+		if err != nil {
+			return err
+		}
+
+		if !waitFor(stopTimeout, func() bool { return !cmd.Alive(pid) }) {
+			return errors.New("cron service of the previous version did not stop in time, pid " + strconv.Itoa(pid))
+		}
+	}
+
+	os.Remove(filepath.Join(dir, "cron.sock"))
+	return os.Remove(pidFile)
+}
+
+// Migrate function moves the data kept next to the executable by the previous versions
+// to the data, state and config directories. the cron service of the previous version is stopped first,
+// it is started again from the new directories by the next command.
+// it does nothing if there is no data to migrate.
+func Migrate() error {
+	dir, ok := fs.LegacyDir()
+	if !ok {
+		return nil
+	}
+
+	err := stopLegacy(dir)
+	if err != nil {
+		log.Err("failed to stop the cron service of the previous version")
+		return err
+	}
+
+	moved, err := fs.MigrateLegacy()
+	if err != nil {
+		log.Err("failed to migrate the data of the previous version", "from", dir)
+		return err
+	}
+
+	for _, path := range moved {
+		log.Info("migrated file of the previous version", "from", dir, "to", path)
+	}
+
+	dataDir, _ := fs.DataDir()
+	log.PrintWarn("the data have been moved to the new data directory", "from", dir, "to", dataDir)
+	return nil
+}
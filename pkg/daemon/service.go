@@ -0,0 +1,366 @@
+// daemon package, system service functions
+package daemon
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/fs"
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"text/template"
+)
+
+// service names used to register the cron service in the service manager of the system
+const (
+	unitName  = "aio-cron.service" // systemd user unit
+	agentName = "com.aio.cron"     // launchd agent label
+	taskName  = "aio-cron"         // windows task scheduler task
+)
+
+// ServiceConfig struct represents the values used to generate the service files.
+type ServiceConfig struct {
+	Bin    string // path of the cron service binary
+	Dir    string // working directory of the cron service, the data directory
+	LogDir string // directory of the standard output and error files, used by launchd
+	Home   string // directory set with --data-dir or AIO_HOME, empty if the default directories are used
+}
+
+// systemdUnit is the template of the systemd user unit.
+// the cron service logs to the journal, with the priority prefixes, when AIO_LOG_JOURNAL is set.
+var systemdUnit = template.Must(template.New("unit").Parse(`[Unit]
+Description=aio cron service
+Documentation=https://github.com/Tagliapietra96/aio
+
+[Service]
+Type=simple
+ExecStart="{{.Bin}}"
+WorkingDirectory={{.Dir}}
+Environment=AIO_LOG_JOURNAL=1
+{{- if .Home}}
+Environment="AIO_HOME={{.Home}}"
+{{- end}}
+Restart=on-failure
+RestartSec=30
+KillSignal=SIGTERM
+TimeoutStopSec=120
+
+[Install]
+WantedBy=default.target
+`))
+
+// launchdAgent is the template of the launchd agent, the paths are escaped for the XML.
+var launchdAgent = template.Must(template.New("agent").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + agentName + `</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{html .Bin}}</string>
+	</array>
+	<key>WorkingDirectory</key>
+	<string>{{html .Dir}}</string>
+	{{- if .Home}}
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>AIO_HOME</key>
+		<string>{{html .Home}}</string>
+	</dict>
+	{{- end}}
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ExitTimeOut</key>
+	<integer>120</integer>
+	<key>StandardOutPath</key>
+	<string>{{html .LogDir}}/cron.out.log</string>
+	<key>StandardErrorPath</key>
+	<string>{{html .LogDir}}/cron.err.log</string>
+</dict>
+</plist>
+`))
+
+// schedulerTask is the template of the windows task scheduler task, started at logon, the paths are escaped for the XML.
+var schedulerTask = template.Must(template.New("task").Parse(`<?xml version="1.0" encoding="UTF-16"?>
+<Task version="1.2" xmlns="http://schemas.microsoft.com/windows/2004/02/mit/task">
+  <RegistrationInfo>
+    <Description>aio cron service</Description>
+  </RegistrationInfo>
+  <Triggers>
+    <LogonTrigger>
+      <Enabled>true</Enabled>
+    </LogonTrigger>
+  </Triggers>
+  <Settings>
+    <MultipleInstancesPolicy>IgnoreNew</MultipleInstancesPolicy>
+    <DisallowStartIfOnBatteries>false</DisallowStartIfOnBatteries>
+    <StopIfGoingOnBatteries>false</StopIfGoingOnBatteries>
+    <ExecutionTimeLimit>PT0S</ExecutionTimeLimit>
+    <RestartOnFailure>
+      <Interval>PT1M</Interval>
+      <Count>3</Count>
+    </RestartOnFailure>
+  </Settings>
+  <Actions>
+    <Exec>
+      <Command>{{html .Bin}}</Command>
+      {{- if .Home}}
+      <Arguments>--data-dir "{{html .Home}}"</Arguments>
+      {{- end}}
+      <WorkingDirectory>{{html .Dir}}</WorkingDirectory>
+    </Exec>
+  </Actions>
+</Task>
+`))
+
+// render function executes a service template with the config.
+func render(t *template.Template, c ServiceConfig) (string, error) {
+	buf := &bytes.Buffer{}
+	err := t.Execute(buf, c)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// SystemdUnit function returns the systemd user unit of the cron service.
+func SystemdUnit(c ServiceConfig) (string, error) {
+	return render(systemdUnit, c)
+}
+
+// LaunchdAgent function returns the launchd agent of the cron service.
+func LaunchdAgent(c ServiceConfig) (string, error) {
+	return render(launchdAgent, c)
+}
+
+// SchedulerTask function returns the windows task scheduler task of the cron service.
+func SchedulerTask(c ServiceConfig) (string, error) {
+	return render(schedulerTask, c)
+}
+
+// serviceConfig function returns the config of the cron service installed next to the aio binary,
+// running in the data directory.
+func serviceConfig() (ServiceConfig, error) {
+	bin, err := Binary()
+	if err != nil {
+		return ServiceConfig{}, err
+	}
+
+	dir, err := fs.DataDir()
+	if err != nil {
+		return ServiceConfig{}, err
+	}
+
+	logDir, err := log.Dir()
+	if err != nil {
+		return ServiceConfig{}, err
+	}
+
+	return ServiceConfig{Bin: bin, Dir: dir, LogDir: logDir, Home: fs.Home()}, nil
+}
+
+// ServiceFile function returns the path of the service file for the current system.
+func ServiceFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		config := os.Getenv("XDG_CONFIG_HOME")
+		if config == "" {
+			config = filepath.Join(home, ".config")
+		}
+		return filepath.Join(config, "systemd", "user", unitName), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", agentName+".plist"), nil
+	case "windows":
+		config, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(config, "aio", taskName+".xml"), nil
+	default:
+		return "", errors.New("unsupported operating system: " + runtime.GOOS)
+	}
+}
+
+// Installed function returns true if the cron service is installed as a system service.
+// in that case the service manager starts the cron service, not the cli.
+func Installed() bool {
+	file, err := ServiceFile()
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(file)
+	return err == nil
+}
+
+// run function runs a service manager command and logs its output on failure.
+// it is a variable, so the tests can record the commands instead of running them.
+var run = func(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		log.Err("failed to run service manager command", "command", name, "args", args, "output", string(output))
+		return errors.Join(errors.New(name+" failed: "+string(bytes.TrimSpace(output))), err)
+	}
+	return nil
+}
+
+// Install function installs the cron service as a system service, started at login:
+// a systemd user unit on linux, a launchd agent on macOS and a task scheduler task on windows.
+// the cron service started by the cli is stopped and replaced by the one managed by the system.
+// it returns the path of the service file.
+func Install() (string, error) {
+	c, err := serviceConfig()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := ServiceFile()
+	if err != nil {
+		return "", err
+	}
+
+	var content string
+	switch runtime.GOOS {
+	case "linux":
+		content, err = SystemdUnit(c)
+	case "darwin":
+		content, err = LaunchdAgent(c)
+	case "windows":
+		content, err = SchedulerTask(c)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(file, []byte(content), 0644)
+	if err != nil {
+		log.Err("failed to write the service file", "file", file)
+		return "", err
+	}
+
+	err = os.Chmod(c.Bin, 0755)
+	if err != nil {
+		log.Err("failed to change cron binary permissions")
+		return "", err
+	}
+
+	// the cron service started by the cli is replaced by the one managed by the system
+	if _, ok := Running(); ok {
+		err = stopProcess()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		err = run("systemctl", "--user", "daemon-reload")
+		if err == nil {
+			err = run("systemctl", "--user", "enable", "--now", unitName)
+		}
+	case "darwin":
+		err = run("launchctl", "load", "-w", file)
+	case "windows":
+		err = run("schtasks", "/Create", "/TN", taskName, "/XML", file, "/F")
+		if err == nil {
+			err = run("schtasks", "/Run", "/TN", taskName)
+		}
+	}
+
+	if err != nil {
+		os.Remove(file) // the service is not registered, so the cli keeps managing the cron service
+		return "", err
+	}
+
+	log.Info("cron service installed", "file", file)
+	return file, nil
+}
+
+// Uninstall function removes the cron service from the system services.
+// after that, the cron service is started again by the cli.
+func Uninstall() error {
+	file, err := ServiceFile()
+	if err != nil {
+		return err
+	}
+
+	if !Installed() {
+		return errors.New("cron service not installed")
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		err = run("systemctl", "--user", "disable", "--now", unitName)
+	case "darwin":
+		err = run("launchctl", "unload", "-w", file)
+	case "windows":
+		run("schtasks", "/End", "/TN", taskName)
+		err = run("schtasks", "/Delete", "/TN", taskName, "/F")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(file)
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "linux" {
+		err = run("systemctl", "--user", "daemon-reload")
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("cron service uninstalled", "file", file)
+	return nil
+}
+
+// serviceStart function starts the cron service with the service manager.
+func serviceStart() error {
+	switch runtime.GOOS {
+	case "linux":
+		return run("systemctl", "--user", "start", unitName)
+	case "darwin":
+		return run("launchctl", "start", agentName)
+	case "windows":
+		return run("schtasks", "/Run", "/TN", taskName)
+	}
+	return errors.New("unsupported operating system: " + runtime.GOOS)
+}
+
+// serviceStop function stops the cron service with the service manager.
+// systemd and launchd send a SIGTERM, so the running jobs are completed.
+func serviceStop() error {
+	switch runtime.GOOS {
+	case "linux":
+		return run("systemctl", "--user", "stop", unitName)
+	case "darwin":
+		return run("launchctl", "stop", agentName)
+	case "windows":
+		// the task scheduler kills the process, so the stop is requested through the control socket
+		return stopProcess()
+	}
+	return errors.New("unsupported operating system: " + runtime.GOOS)
+}
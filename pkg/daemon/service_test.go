@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestServiceFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func(ServiceConfig) (string, error)
+		xml    bool
+	}{
+		{name: "systemd", render: SystemdUnit},
+		{name: "launchd", render: LaunchdAgent, xml: true},
+		{name: "task scheduler", render: SchedulerTask, xml: true},
+	}
+
+	configs := []struct {
+		name string
+		c    ServiceConfig
+		want []string
+	}{
+		{name: "default directories", c: ServiceConfig{Bin: "/opt/aio/cron", Dir: "/home/jay/.local/share/aio", LogDir: "/home/jay/.local/state/aio/logs"}, want: []string{"/opt/aio/cron", "/home/jay/.local/share/aio"}},
+		{name: "data dir", c: ServiceConfig{Bin: "/opt/aio/cron", Dir: "/data/aio", LogDir: "/data/aio/logs", Home: "/data/aio"}, want: []string{"/opt/aio/cron", "/data/aio"}},
+		{name: "paths with xml characters", c: ServiceConfig{Bin: "/Users/Tom & Jerry/aio/cron", Dir: "/Users/Tom & Jerry/aio/<data>", LogDir: "/tmp", Home: "/Users/Tom & Jerry/aio/<data>"}, want: []string{"cron", "aio"}},
+	}
+
+	for _, tt := range tests {
+		for _, cfg := range configs {
+			t.Run(tt.name+" "+cfg.name, func(t *testing.T) {
+				content, err := tt.render(cfg.c)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, want := range cfg.want {
+					if !strings.Contains(content, want) {
+						t.Errorf("the service file does not contain %q:\n%s", want, content)
+					}
+				}
+
+				// the data directory is passed to the cron service only when it is set
+				if home := strings.Contains(content, "AIO_HOME") || strings.Contains(content, "--data-dir"); home != (cfg.c.Home != "") {
+					t.Errorf("the service file sets the data directory %v, want %v:\n%s", home, cfg.c.Home != "", content)
+				}
+
+				if !tt.xml {
+					return
+				}
+
+				// the file is a valid XML document, with the paths unchanged
+				d := xml.NewDecoder(strings.NewReader(content))
+				d.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+				text := ""
+				for {
+					token, err := d.Token()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+
+					if err != nil {
+						t.Fatalf("invalid XML: %v\n%s", err, content)
+					}
+
+					if data, ok := token.(xml.CharData); ok {
+						text += string(data)
+					}
+				}
+
+				if !strings.Contains(text, cfg.c.Bin) || !strings.Contains(text, cfg.c.Dir) {
+					t.Errorf("the XML text does not contain the paths %q and %q", cfg.c.Bin, cfg.c.Dir)
+				}
+			})
+		}
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the service file of systemd is tested on linux")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	commands := []string{}
+	fail := false
+	defer func(r func(string, ...string) error) { run = r }(run)
+	run = func(name string, args ...string) error {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		if fail {
+			return errors.New(name + " failed")
+		}
+		return nil
+	}
+
+	file, err := ServiceFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "systemd", "user", unitName); file != want {
+		t.Fatalf("ServiceFile() = %s, want %s", file, want)
+	}
+
+	if Installed() || Uninstall() == nil || len(commands) > 0 {
+		t.Fatalf("the service is not installed, commands %q", commands)
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(file, []byte("[Unit]\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a failed command of the service manager keeps the service file
+	fail = true
+	if err := Uninstall(); err == nil || !Installed() {
+		t.Fatalf("Uninstall() = %v with a failed command, installed %v", err, Installed())
+	}
+
+	fail, commands = false, []string{}
+	err = Uninstall()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"systemctl --user disable --now " + unitName, "systemctl --user daemon-reload"}
+	if !slices.Equal(commands, want) || Installed() {
+		t.Errorf("commands %q, installed %v, want %q and not installed", commands, Installed(), want)
+	}
+}
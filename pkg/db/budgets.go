@@ -0,0 +1,265 @@
+// db package category budgets functions
+package db
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/log"
+	"aio/pkg/utils/tm"
+	"slices"
+	"time"
+)
+
+// monthOf function returns the first day of the month of t.
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// Available function returns the amount that can be spent in the month, the budget and the amount carried to it.
+func (e Envelope) Available() int64 {
+	return e.Amount + e.Carried
+}
+
+// Remaining function returns the amount of the envelope not yet spent, negative if it is overspent.
+func (e Envelope) Remaining() int64 {
+	return e.Available() - e.Spent
+}
+
+// next function returns the amount carried from the month of the envelope to the next one,
+// the amount not spent with the rollover, the overspent amount is never carried.
+func (e Envelope) next() int64 {
+	if !e.Rollover {
+		return 0
+	}
+	return max(e.Remaining(), 0)
+}
+
+// Budgets function returns the budgets of the categories of the current profile.
+func Budgets() ([]Budget, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("budgets_list", id)
+	if err != nil {
+		log.Err("failed to get the budgets")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	budgets := []Budget{}
+	for rows.Next() {
+		var b Budget
+		var startsAt string
+		err = rows.Scan(&b.ID, &b.Category, &b.Amount, &b.Rollover, &b.Carried, &startsAt)
+		if err != nil {
+			log.Err("failed to scan the budget")
+			return nil, err
+		}
+
+		b.StartsAt, err = tm.DBParse(startsAt)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+
+	return budgets, rows.Err()
+}
+
+// BudgetSet function sets the monthly budget of a category of the current profile, in minor units of the currency
+// of the character, from the month of now. the budget of the category is replaced, the amount carried to the month
+// by its previous months is kept.
+func BudgetSet(category string, amount int64, rollover bool, now time.Time) error {
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	month := monthOf(now)
+	carried := int64(0)
+	report, err := BudgetReportOf(month)
+	if err != nil {
+		return err
+	}
+
+	if i := slices.IndexFunc(report.Envelopes, func(e Envelope) bool { return e.Category == category }); i >= 0 {
+		carried = report.Envelopes[i].Carried
+	}
+
+	err = do("budgets_set", id, category, amount, rollover, carried, tm.DBFormat(month))
+	if err != nil {
+		log.Err("failed to set the budget", "category", category)
+		return err
+	}
+
+	return nil
+}
+
+// BudgetDelete function deletes the budget of a category of the current profile, its penalties are kept.
+func BudgetDelete(category string) error {
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("budgets_delete", id, category)
+	if err != nil {
+		log.Err("failed to delete the budget", "category", category)
+		return err
+	}
+
+	return nil
+}
+
+// BudgetReportOf function returns the envelopes of the budgets of the current profile in the month of t,
+// with the spending of the month in their category and the amount carried from the previous months.
+// the amounts are in the currency of the character, every transaction is converted with the exchange rate of its date.
+// the budgets started after the month are not in the report.
+func BudgetReportOf(t time.Time) (BudgetReport, error) {
+	month := monthOf(t)
+	report := BudgetReport{Month: month, Envelopes: []Envelope{}}
+	c, err := CharGet()
+	if err != nil {
+		return report, err
+	}
+
+	report.Currency, report.MonthBudget = c.Currency, c.MonthBudget
+	budgets, err := Budgets()
+	if err != nil {
+		return report, err
+	}
+
+	// the transactions from the start of the first budget, the months before are needed by the rollover
+	from := month
+	for _, b := range budgets {
+		if b.StartsAt.Before(from) {
+			from = b.StartsAt
+		}
+	}
+
+	_, transactions, err := transactionsBetween(from, month.AddDate(0, 1, 0))
+	if err != nil {
+		return report, err
+	}
+
+	// the spending per category and month, the expenses minus the refunds
+	type key struct {
+		category string
+		month    string
+	}
+
+	spent := map[key]int64{}
+	for _, t := range transactions {
+		spent[key{t.Category, tm.DBFormat(monthOf(t.Date))}] -= t.Amount
+	}
+
+	budgeted := map[string]bool{}
+	for _, b := range budgets {
+		if b.StartsAt.After(month) {
+			continue
+		}
+
+		budgeted[b.Category] = true
+		e := Envelope{Category: b.Category, Amount: b.Amount, Rollover: b.Rollover, Carried: b.Carried}
+		for m := b.StartsAt; ; m = m.AddDate(0, 1, 0) {
+			e.Month = m
+			e.Spent = spent[key{b.Category, tm.DBFormat(m)}]
+			if !m.Before(month) {
+				break
+			}
+			e.Carried = e.next()
+		}
+		report.Envelopes = append(report.Envelopes, e)
+	}
+
+	for k, amount := range spent {
+		if k.month != tm.DBFormat(month) || amount <= 0 {
+			continue
+		}
+
+		report.Spent += amount
+		if !budgeted[k.category] {
+			report.Unbudgeted += amount
+		}
+	}
+
+	return report, nil
+}
+
+// BudgetPenalize function applies the penalties of the budgets overspent by the current profile in the month of now
+// and in the previous one, a budget is penalized once per month with the hp and karma of the config file.
+// it returns the penalties applied.
+func BudgetPenalize(now time.Time) ([]Penalty, error) {
+	penalties := []Penalty{}
+	cfg := config.Get().Money
+	if cfg.OverspendHP == 0 && cfg.OverspendKarma == 0 {
+		return penalties, nil
+	}
+
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	// the previous month is checked for the transactions imported after its end
+	for _, month := range []time.Time{monthOf(now).AddDate(0, -1, 0), monthOf(now)} {
+		report, err := BudgetReportOf(month)
+		if err != nil {
+			return nil, err
+		}
+
+		done, err := penalized(id, month)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range report.Envelopes {
+			if e.Remaining() >= 0 || done[e.Category] {
+				continue
+			}
+
+			overspent := -e.Remaining()
+			err = do("budget_penalties_create", id, e.Category, tm.DBFormat(month), overspent, cfg.OverspendHP, cfg.OverspendKarma, cfg.OverspendHP, cfg.OverspendKarma, id)
+			if err != nil {
+				log.Err("failed to apply the budget penalty", "category", e.Category)
+				return nil, err
+			}
+
+			penalties = append(penalties, Penalty{
+				Category:  e.Category,
+				Month:     month,
+				Overspent: overspent,
+				Currency:  report.Currency,
+				HP:        cfg.OverspendHP,
+				Karma:     cfg.OverspendKarma,
+			})
+		}
+	}
+
+	return penalties, nil
+}
+
+// penalized function returns the categories already penalized for a month.
+func penalized(id int64, month time.Time) (map[string]bool, error) {
+	rows, err := gets("budget_penalties_list", id, tm.DBFormat(month))
+	if err != nil {
+		log.Err("failed to get the budget penalties")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	done := map[string]bool{}
+	for rows.Next() {
+		var category string
+		if err = rows.Scan(&category); err != nil {
+			log.Err("failed to scan the budget penalty")
+			return nil, err
+		}
+		done[category] = true
+	}
+
+	return done, rows.Err()
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvelope(t *testing.T) {
+	tests := []struct {
+		name      string
+		e         Envelope
+		available int64
+		remaining int64
+		next      int64
+	}{
+		{name: "not spent", e: Envelope{Amount: 10000, Rollover: true}, available: 10000, remaining: 10000, next: 10000},
+		{name: "partly spent", e: Envelope{Amount: 10000, Rollover: true, Carried: 2500, Spent: 6000}, available: 12500, remaining: 6500, next: 6500},
+		{name: "without rollover", e: Envelope{Amount: 10000, Carried: 0, Spent: 6000}, available: 10000, remaining: 4000},
+		{name: "overspent is not carried", e: Envelope{Amount: 10000, Rollover: true, Carried: 500, Spent: 12000}, available: 10500, remaining: -1500},
+		{name: "refunds", e: Envelope{Amount: 10000, Rollover: true, Spent: -2000}, available: 10000, remaining: 12000, next: 12000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.Available(); got != tt.available {
+				t.Errorf("Available() = %d, want %d", got, tt.available)
+			}
+
+			if got := tt.e.Remaining(); got != tt.remaining {
+				t.Errorf("Remaining() = %d, want %d", got, tt.remaining)
+			}
+
+			if got := tt.e.next(); got != tt.next {
+				t.Errorf("next() = %d, want %d", got, tt.next)
+			}
+		})
+	}
+}
+
+func TestBudgetReportOf(t *testing.T) {
+	setup(t)
+
+	month := func(m time.Month) time.Time {
+		return time.Date(2026, m, 1, 0, 0, 0, 0, time.Local)
+	}
+
+	for pattern, category := range map[string]string{"Coffee": "food", "Cinema": "fun", "Gift": "misc"} {
+		err := CategoryRuleCreate(pattern, category)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// food rolls over from august, fun does not
+	err := BudgetSet("food", 10000, true, month(time.August))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = BudgetSet("fun", 5000, false, month(time.August))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spend := func(m time.Month, day int, description string, amount int64) Transaction {
+		return Transaction{Date: time.Date(2026, m, day, 0, 0, 0, 0, time.Local), Amount: amount, Description: description}
+	}
+
+	_, err = TransactionsImport([]Transaction{
+		spend(time.August, 3, "Coffee", -6000),
+		spend(time.August, 4, "Cinema", -1000),
+		spend(time.September, 5, "Coffee", -15000),
+		spend(time.October, 6, "Coffee", -2000),
+		spend(time.October, 7, "Coffee refund", 500),
+		spend(time.October, 8, "Cinema", -6000),
+		spend(time.October, 9, "Gift", -3000),
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		carried, spent, remaining int64
+	}
+
+	tests := []struct {
+		name       string
+		month      time.Time
+		envelopes  map[string]want
+		spent      int64
+		unbudgeted int64
+	}{
+		{name: "before the budgets", month: month(time.July)},
+		{
+			name:      "first month",
+			month:     month(time.August),
+			envelopes: map[string]want{"food": {spent: 6000, remaining: 4000}, "fun": {spent: 1000, remaining: 4000}},
+			spent:     7000,
+		},
+		{
+			name:      "rolled over and overspent",
+			month:     month(time.September).AddDate(0, 0, 14),
+			envelopes: map[string]want{"food": {carried: 4000, spent: 15000, remaining: -1000}, "fun": {remaining: 5000}},
+			spent:     15000,
+		},
+		{
+			name:       "overspent not carried",
+			month:      month(time.October),
+			envelopes:  map[string]want{"food": {spent: 1500, remaining: 8500}, "fun": {spent: 6000, remaining: -1000}},
+			spent:      10500,
+			unbudgeted: 3000,
+		},
+		{
+			name:      "carried to the next month",
+			month:     month(time.November),
+			envelopes: map[string]want{"food": {carried: 8500, remaining: 18500}, "fun": {remaining: 5000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := BudgetReportOf(tt.month)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !report.Month.Equal(monthOf(tt.month)) || report.Currency != "EUR" {
+				t.Errorf("month %s in %s, want %s in EUR", report.Month, report.Currency, monthOf(tt.month))
+			}
+
+			if len(report.Envelopes) != len(tt.envelopes) {
+				t.Fatalf("%d envelopes, want %d", len(report.Envelopes), len(tt.envelopes))
+			}
+
+			for _, e := range report.Envelopes {
+				w := tt.envelopes[e.Category]
+				if e.Carried != w.carried || e.Spent != w.spent || e.Remaining() != w.remaining {
+					t.Errorf("%s: carried %d, spent %d, remaining %d, want %d, %d, %d", e.Category, e.Carried, e.Spent, e.Remaining(), w.carried, w.spent, w.remaining)
+				}
+			}
+
+			if report.Spent != tt.spent || report.Unbudgeted != tt.unbudgeted {
+				t.Errorf("spent %d, unbudgeted %d, want %d, %d", report.Spent, report.Unbudgeted, tt.spent, tt.unbudgeted)
+			}
+		})
+	}
+}
@@ -2,28 +2,46 @@
 package db
 
 import (
+	"aio/pkg/inputs"
 	"aio/pkg/log"
+	"aio/pkg/utils/money"
 	"aio/pkg/utils/tm"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
 )
 
-// NewCharacterMgr function creates a new CharacterMgr object.
-// It returns a pointer to the new CharacterMgr object.
+// CharGet function returns the character of the current profile.
+// It returns a pointer to the character.
 func CharGet() (*Character, error) {
-	var birth, created, updated string
-	c := &Character{}
-	row, err := get("characters_get")
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := get("characters_get", id)
 	if err != nil {
 		log.Err("failed to get the character")
 		return nil, err
 	}
 
-	err = row.Scan(
+	return scanChar(row)
+}
+
+// scanChar function scans a row of the characters_get query into a character.
+// it is shared by the functions that read the character from the current database or from a snapshot.
+func scanChar(row *sql.Row) (*Character, error) {
+	var birth, created, updated string
+	c := &Character{}
+	err := row.Scan(
 		&c.FirstName,
 		&c.LastName,
 		&c.NickName,
 		&birth,
 		&c.MonthBudget,
 		&c.Balance,
+		&c.Currency,
 		&c.Coins,
 		&c.XP,
 		&c.NextLevelXP,
@@ -33,13 +51,13 @@ func CharGet() (*Character, error) {
 		&c.HP,
 		&c.MaxHP,
 		&c.Karma,
-		created,
-		updated,
+		&created,
+		&updated,
 	)
 
 	if err != nil {
 		log.Err("failed to scan the character")
-		return nil, err
+		return nil, wrapChar(err)
 	}
 
 	c.BirthDate, err = tm.DBParse(birth)
@@ -76,7 +94,12 @@ func (c *Character) Death() error {
 	c.Karma = c.Karma - 10
 	c.Coins = 0
 
-	err := do("characters_death")
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("characters_death", id)
 	if err != nil {
 		log.Err("failed to kill the character")
 		return err
@@ -84,3 +107,170 @@ func (c *Character) Death() error {
 
 	return nil
 }
+
+// CharEdit function changes the values chosen by the user of the character of the current profile:
+// first name, last name, nickname, birth date, currency and budget.
+// a new currency converts the balance, the monthly budget and the budgets of the categories with the exchange rate
+// of today, or of the latest day before it, a budget given with the new currency is kept as it is.
+// it returns ErrNoRate if the rates of the currencies have not been imported, the currency is not changed.
+// the values of the seed that are not empty are validated and saved, the others are kept.
+// with an empty seed the user is asked for every value with a form that shows the current ones.
+// it returns false if nothing has changed. the changes are recorded in the audit table by the database.
+func CharEdit(seed Seed) (bool, error) {
+	id, err := current()
+	if err != nil {
+		return false, err
+	}
+
+	c, err := CharGet()
+	if err != nil {
+		return false, err
+	}
+
+	old := map[string]string{
+		"AIO_FIRST_NAME": c.FirstName,
+		"AIO_LAST_NAME":  c.LastName,
+		"AIO_NICKNAME":   c.NickName,
+		"AIO_BIRTH_DATE": c.BirthDate.Format("02 Jan 2006"),
+		"AIO_CURRENCY":   c.Currency,
+		"AIO_BUDGET":     money.Format(c.MonthBudget, c.Currency),
+	}
+
+	values := map[string]string{
+		"AIO_FIRST_NAME": seed.FirstName,
+		"AIO_LAST_NAME":  seed.LastName,
+		"AIO_NICKNAME":   seed.NickName,
+		"AIO_BIRTH_DATE": seed.BirthDate,
+		"AIO_CURRENCY":   seed.Currency,
+		"AIO_BUDGET":     seed.Budget,
+	}
+
+	// the fields of the onboarding, with the current values as defaults
+	fields := make([]inputs.Field, len(onboardingFields))
+	empty := true
+	for i, field := range onboardingFields {
+		field.Default = old[field.Key]
+		if field.Key == "AIO_NICKNAME" {
+			field.Validate = func(s string) error {
+				if s == c.NickName {
+					return nil
+				}
+				return newNickname(s)
+			}
+		}
+
+		fields[i] = field
+		empty = empty && values[field.Key] == ""
+	}
+
+	if empty {
+		answers, err := inputs.RunForm(inputs.Form{Title: "Edit your character", Fields: fields})
+		if errors.Is(err, inputs.ErrNoInput) {
+			return false, errors.Join(errors.New("no value to change, pass them with the flags"), err)
+		}
+
+		if err != nil {
+			return false, err
+		}
+		values = answers
+	}
+
+	for _, field := range fields {
+		value := values[field.Key]
+		if value == "" || value == old[field.Key] {
+			values[field.Key] = old[field.Key]
+			continue
+		}
+
+		err := field.Validate(value)
+		if err == nil && field.Check != nil {
+			err = field.Check(value, values) // the budget is checked with the new currency, or the current one
+		}
+
+		if err != nil {
+			return false, ValueError{Key: field.Key, Err: err}
+		}
+	}
+
+	currency, err := money.Currency(values["AIO_CURRENCY"])
+	if err != nil {
+		return false, ValueError{Key: "AIO_CURRENCY", Err: err}
+	}
+
+	birth, err := tm.DBReformat(values["AIO_BIRTH_DATE"])
+	if err != nil {
+		log.Err("failed to reformat birth date")
+		return false, err
+	}
+
+	db, err := getDb()
+	if err != nil {
+		log.Err("failed to open database")
+		return false, err
+	}
+
+	defer db.Close()
+
+	q, err := loadQuery("characters_update")
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Err("failed to start transaction")
+		return false, wrap(err)
+	}
+
+	defer tx.Rollback()
+
+	// the rate from the old currency to the new one is read in the transaction of the update, 1 if the currency is not changed
+	rate, err := newConverter(tx).rate(c.Currency, currency, time.Now())
+	if err != nil {
+		log.Err("failed to get the exchange rate", "from", c.Currency, "to", currency)
+		return false, err
+	}
+
+	// the budget not changed by the user is converted, the current one is written in the old currency
+	budget := money.Convert(c.MonthBudget, c.Currency, currency, rate)
+	if values["AIO_BUDGET"] != old["AIO_BUDGET"] {
+		budget, err = parseBudget(values["AIO_BUDGET"], currency)
+		if err != nil {
+			log.Err("failed to parse budget")
+			return false, err
+		}
+	}
+
+	// the values are compared in the form saved in the database, e.g. 1800 and 1800.00 are the same budget
+	fn, ln, nn := values["AIO_FIRST_NAME"], values["AIO_LAST_NAME"], values["AIO_NICKNAME"]
+	if fn == c.FirstName && ln == c.LastName && nn == c.NickName && birth == tm.DBFormat(c.BirthDate) && currency == c.Currency && budget == c.MonthBudget {
+		return false, nil
+	}
+
+	before, err := statsIn(tx, id)
+	if err != nil {
+		return false, err
+	}
+
+	// the balance and the budgets of the categories are converted by the database, with the rate between their minor units,
+	// e.g. 1234 JPY at 0.0067 are 8.27 USD, 1234 minor units times 0.67
+	factor := rate * math.Pow10(money.Exponent(currency)-money.Exponent(c.Currency))
+	_, err = tx.Exec(q, fn, ln, nn, birth, budget, factor, currency, id, factor, factor, id)
+	if err != nil {
+		log.Err("failed to update the character")
+		return false, wrap(err)
+	}
+
+	err = record(tx, "characters_update", id, before)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Err("failed to commit transaction")
+		return false, wrap(err)
+	}
+
+	return true, nil
+}
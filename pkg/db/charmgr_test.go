@@ -0,0 +1,93 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCharEditCurrency(t *testing.T) {
+	setup(t)
+
+	now := time.Now()
+	_, err := RatesImport([]Rate{{Date: now.AddDate(0, 0, -3), Base: "EUR", Quote: "JPY", Rate: 160}, {Date: now.AddDate(0, 0, -1), Base: "EUR", Quote: "JPY", Rate: 162.74}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = BudgetSet("groceries", 40000, true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := CharGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without a rate the currency is not changed
+	_, err = CharEdit(Seed{Currency: "CHF"})
+	if !errors.Is(err, ErrNoRate) {
+		t.Fatalf("CharEdit to CHF error = %v, want ErrNoRate", err)
+	}
+
+	// a budget is checked with the minor units of the new currency, the yen has none
+	var ve ValueError
+	_, err = CharEdit(Seed{Currency: "JPY", Budget: "1800.50"})
+	if !errors.As(err, &ve) || ve.Key != "AIO_BUDGET" {
+		t.Fatalf("CharEdit to JPY with 1800.50 error = %v, want an invalid AIO_BUDGET", err)
+	}
+
+	if c, err := CharGet(); err != nil || c.Currency != "EUR" {
+		t.Fatalf("CharGet() = %+v, %v, want the character in EUR", c, err)
+	}
+
+	// the amounts are converted with the latest rate, a budget given with the currency is kept
+	tests := []struct {
+		name     string
+		seed     Seed
+		currency string
+		budget   int64
+		category int64
+	}{
+		{name: "to JPY", seed: Seed{Currency: "JPY"}, currency: "JPY", budget: 244110, category: 65096},
+		{name: "back to EUR with a budget", seed: Seed{Currency: "EUR", Budget: "1800"}, currency: "EUR", budget: 180000, category: 40000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := CharEdit(tt.seed)
+			if err != nil || !changed {
+				t.Fatalf("CharEdit = %v, %v, want true", changed, err)
+			}
+
+			c, err := CharGet()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.Currency != tt.currency || c.MonthBudget != tt.budget {
+				t.Errorf("currency %s, budget %d, want %s, %d", c.Currency, c.MonthBudget, tt.currency, tt.budget)
+			}
+
+			budgets, err := Budgets()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(budgets) != 1 || budgets[0].Amount != tt.category {
+				t.Errorf("budgets %+v, want groceries of %d", budgets, tt.category)
+			}
+		})
+	}
+
+	after, err := CharGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the balance went to JPY and back, with the rounding of the yen
+	if diff := after.Balance - before.Balance; diff < -1 || diff > 1 {
+		t.Errorf("balance %d after the conversions, want about %d", after.Balance, before.Balance)
+	}
+}
@@ -3,13 +3,11 @@ package db
 
 import (
 	"aio/pkg/git"
-	"aio/pkg/inputs"
 	"aio/pkg/log"
 	"aio/pkg/utils/fs"
-	"aio/pkg/utils/num"
-	"aio/pkg/utils/tm"
 	"database/sql"
 	"embed"
+	"errors"
 	"os"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -40,8 +38,8 @@ func getDb() (*sql.DB, error) {
 		return nil, err
 	}
 
-	// open the database
-	db, err := sql.Open("sqlite3", dbfile)
+	// open the database, with the foreign keys enforced, so the rows of a deleted character are deleted too
+	db, err := sql.Open("sqlite3", dbfile+"?_foreign_keys=on")
 	if err != nil {
 		log.Err("failed to open database")
 		return nil, err
@@ -53,13 +51,21 @@ func getDb() (*sql.DB, error) {
 // do function executes a query on the database.
 // the execution is done in a transaction to ensure the integrity of the data.
 // if the transaction fails, it rolls back the changes.
+// if the query is a journaled action, the change is recorded in the journal in the same transaction.
 // every step is logged in case of errors and stop the execution
 func do(query string, args ...any) error {
+	_, err := doCount(query, args...)
+	return err
+}
+
+// doCount function executes a query on the database like do, and returns the number of rows changed
+// by its last statement.
+func doCount(query string, args ...any) (int64, error) {
 	// open the database
 	db, err := getDb()
 	if err != nil {
 		log.Err("failed to open database")
-		return err
+		return 0, err
 	}
 
 	defer db.Close()
@@ -68,33 +74,63 @@ func do(query string, args ...any) error {
 	q, err := loadQuery(query)
 	if err != nil {
 		log.Err("failed to load query")
-		return err
+		return 0, err
 	}
 
 	// start a transaction
 	tx, err := db.Begin()
 	if err != nil {
-		tx.Rollback()
 		log.Err("failed to start transaction")
-		return err
+		return 0, wrap(err)
+	}
+
+	// get the character stats before the change, if the query is recorded in the journal
+	_, journaled := actions[query]
+	var before stats
+	var id int64
+	if journaled {
+		// before the first character is created there is no profile, the stats are empty
+		id, err = current()
+		if err != nil && !errors.Is(err, ErrNoCharacter) && !errors.Is(err, ErrNoProfile) {
+			tx.Rollback()
+			log.Err("failed to get the current profile")
+			return 0, err
+		}
+
+		before, err = statsIn(tx, id)
+		if err != nil {
+			tx.Rollback()
+			log.Err("failed to get the character stats")
+			return 0, err
+		}
 	}
 
 	// execute the query
-	_, err = tx.Exec(q, args...)
+	res, err := tx.Exec(q, args...)
 	if err != nil {
 		tx.Rollback()
 		log.Err("failed to execute query")
-		return err
+		return 0, wrap(err)
+	}
+
+	// record the change in the journal, unless the query did not change anything
+	n, _ := res.RowsAffected()
+	if journaled && n > 0 {
+		err = record(tx, query, id, before)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
 	}
 
 	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
 		log.Err("failed to commit transaction")
-		return err
+		return 0, wrap(err)
 	}
 
-	return nil
+	return n, nil
 }
 
 // gets function executes a query on the database and returns the result.
@@ -122,7 +158,7 @@ func gets(query string, args ...any) (*sql.Rows, error) {
 	rows, err := db.Query(q, args...)
 	if err != nil {
 		log.Err("failed to execute query")
-		return nil, err
+		return nil, wrap(err)
 	}
 
 	return rows, nil
@@ -154,11 +190,13 @@ func get(query string, args ...any) (*sql.Row, error) {
 	return row, nil
 }
 
-// Init function initializes the database.
+// InitSchema function initializes the database without the character.
 // the funciton initialize also git for the db versioning
 // it is used to create the database file and tables if they do not exist.
 // it loads the main sql file that contains the queries to create the tables, indexes, and triggers.
-func Init() error {
+// the schema of a database created by a previous version is migrated first.
+// it is used by the commands that work on the data without a character, like the import.
+func InitSchema() error {
 	git.Init()
 
 	log.Deb("initializing database...")
@@ -188,6 +226,13 @@ func Init() error {
 		git.InitialCommit()
 	}
 
+	// upgrade the schema of a database created by a previous version
+	err = migrate()
+	if err != nil {
+		log.Err("failed to migrate the database")
+		return err
+	}
+
 	// create the tables, indexes, and triggers
 	err = do("tables")
 	if err != nil {
@@ -196,6 +241,17 @@ func Init() error {
 	}
 
 	log.Info("database initialized successfully!")
+	return nil
+}
+
+// Init function initializes the database and the character.
+// if the database has no character, it is created with the values of the seed.
+// the daily login of the character is created, and the yearly events of today are celebrated.
+func Init(seed Seed) error {
+	err := InitSchema()
+	if err != nil {
+		return err
+	}
 
 	// check if there are characters in the database
 	var exists bool
@@ -209,68 +265,30 @@ func Init() error {
 	err = row.Scan(&exists)
 	if err != nil {
 		log.Err("failed to check if characters exist")
-		return err
+		return wrap(err)
 	}
 
 	// if characters do not exist, create the initial character
 	if !exists {
 		log.Warn("no characters found in the database")
 		log.Deb("creating initial character...")
-		log.PrintS("Welcome to AIO - Your Life, Gamified!", log.TitleStyle)
-		log.Print(`
-Turn your tasks, goals, and habits into an adventure.
-Track progress, manage your finances, boost productivity, and level up in all aspects of life.
-Ready to make self-improvement fun? Your journey starts now!
-`)
-		log.Print("Welcome, traveler! Before we begin your adventure, we need to know your name.")
-		log.Print("What is your first name, brave soul?")
-		fn := inputs.RunInput("Jhon")
-
-		log.Print("A strong name indeed! Now, please tell us your family name, the one that will echo through the halls of history.")
-		log.Print("What is your last name, worthy adventurer?")
-		ln := inputs.RunInput("Smith")
-
-		log.Print("Every hero has a title that the bards will sing of! Choose a nickname, one that will strike fear into your foes or inspire your allies.")
-		log.Print("What shall your unique nickname be?")
-		nn := inputs.RunInput("The Reaper")
-
-		log.Print("Even legends have a beginning. We need to know when your story began.")
-		log.Print("Please provide your date of birth in the form of 02 Jan 2006.")
-		log.Print("When were you born, chosen one?")
-		dob := inputs.RunInputWithValidation("02 Jan 2006", tm.ValidateDate)
-
-		log.Print("Every great adventurer must wisely manage their resources, not just in battle, but also in life.")
-		log.Print("Set your monthly budget this will guide how you manage your gold throughout your journey!")
-		log.Print(("How much gold will you allocate each month for your expenses? (Enter a numeric value)"))
-		b := inputs.RunInputWithValidation("1500.00", num.Validate)
-
-		budget, err := num.ParseFloat(b)
-		if err != nil {
-			log.Err("failed to parse budget")
-			return err
-		}
-
-		birth, err := tm.DBReformat(dob)
+		_, err = createCharacter(seed)
 		if err != nil {
-			log.Err("failed to reformat birth date")
-			return err
-		}
-
-		err = do("characters_create", fn, ln, nn, birth, budget)
-		if err != nil {
-			log.Err("failed to create character")
 			return err
 		}
+	}
 
-		log.Print("🎉 Your character has been created! 🎉")
-		log.Print("Welcome, %s %s, also known as %s!", fn, ln, nn)
-		log.Print("Now, go forth and conquer the challenges ahead!")
-		log.Print("\nTo view an help text use the command 'aio --help', or 'aio -h'.\n")
+	// the profile of the commands, chosen with SetProfile or the active one
+	id, err := resolve()
+	if err != nil {
+		log.Err("failed to get the current profile")
+		return err
 	}
-	log.Info("character initialized successfully!")
+	setCharacter(id)
+	log.Info("character initialized successfully!", "id", id)
 
 	// check if there are daily logins in the database for today
-	row, err = get("daily_logins_today_exists")
+	row, err = get("daily_logins_today_exists", id)
 	if err != nil {
 		log.Err("failed to check if daily logins exist")
 		return err
@@ -279,14 +297,14 @@ Ready to make self-improvement fun? Your journey starts now!
 	err = row.Scan(&exists)
 	if err != nil {
 		log.Err("failed to check if daily logins exist")
-		return err
+		return wrap(err)
 	}
 
 	// if daily logins do not exist, create the daily login and update the character stats
 	if !exists {
 		log.Warn("no daily logins found for today")
 		log.Deb("creating daily login...")
-		err = do("characters_daily_login")
+		err = do("characters_daily_login", id, id)
 		if err != nil {
 			log.Err("failed to create daily login")
 			return err
@@ -294,6 +312,23 @@ Ready to make self-improvement fun? Your journey starts now!
 		log.Info("daily login created successfully!")
 	}
 
+	// celebrate the birthday and the other yearly events of today, their banner is shown once
+	events, err := EventsBanner()
+	if err != nil {
+		log.Err("failed to celebrate the events")
+		return err
+	}
+
+	for _, e := range events {
+		char, err := CharGet()
+		if err != nil {
+			return err
+		}
+
+		log.Info("event celebrated", "kind", e.Kind, "years", e.Years)
+		log.PrintS("🎉 %s 🎉\n\n%s, %s!", log.BannerStyle, e.Title(), char.NickName, e.Gift())
+	}
+
 	log.Info("database initialized successfully!")
 	return nil
 }
@@ -0,0 +1,22 @@
+package db
+
+import (
+	"aio/pkg/utils/fs"
+	"testing"
+)
+
+// setup function initializes a database with a character in a temporary directory, for the tests of the queries.
+// the currency of the character is EUR.
+func setup(t *testing.T) {
+	t.Helper()
+	err := fs.SetHome(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetProfile("")
+	err = Init(Seed{FirstName: "Jane", LastName: "Smith", NickName: "Jay", BirthDate: "02 Jan 2000", Currency: "EUR", Budget: "1500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,69 @@
+// db package errors
+package db
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrNoCharacter is returned when the database has no character yet.
+var ErrNoCharacter = errors.New("no character found, run aio to create one")
+
+// ErrNoProfile is returned when the chosen profile does not exist.
+var ErrNoProfile = errors.New("no such profile, the profiles are listed by aio profile list")
+
+// ErrNoScope is returned when the cron service reads the current profile outside ForEachProfile.
+var ErrNoScope = errors.New("the current profile is read outside ForEachProfile in the cron service")
+
+// ErrProfileInUse is returned when the profile to delete is the one in use.
+var ErrProfileInUse = errors.New("the profile is in use, switch to another profile before deleting it")
+
+// ErrNoRate is returned when an amount can't be converted to another currency, its exchange rates have not been imported.
+var ErrNoRate = errors.New("missing exchange rate, import the rates with aio money rates import")
+
+// ErrNoTask is returned when the task does not exist, or it belongs to another profile.
+var ErrNoTask = errors.New("no such task, the tasks are listed by aio task list")
+
+// ErrTaskCompleted is returned when the task to complete has already been completed.
+var ErrTaskCompleted = errors.New("task already completed")
+
+// ErrLocked is returned when the database is locked by another process, e.g. the cron service.
+var ErrLocked = errors.New("database locked by another process, try again later")
+
+// ValueError struct represents an invalid value of a character passed by the user, e.g. with a flag.
+type ValueError struct {
+	Key string // the environment variable of the value, e.g. AIO_BIRTH_DATE, or the flag that gave it
+	Err error
+}
+
+// Error function returns the message of the value error.
+func (e ValueError) Error() string {
+	return "invalid value of " + e.Key + ": " + e.Err.Error()
+}
+
+// Unwrap function returns the wrapped error.
+func (e ValueError) Unwrap() error {
+	return e.Err
+}
+
+// wrap function wraps the sqlite errors with the db errors they represent,
+// so the callers can check them with errors.Is.
+func wrap(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked) {
+		return errors.Join(ErrLocked, err)
+	}
+
+	return err
+}
+
+// wrapChar function wraps the errors of a character query, a missing row means there is no character.
+func wrapChar(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Join(ErrNoCharacter, err)
+	}
+
+	return wrap(err)
+}
@@ -0,0 +1,206 @@
+// db package yearly events functions
+package db
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/log"
+	"aio/pkg/utils/str"
+	"fmt"
+	"time"
+)
+
+// event kinds
+const (
+	EventBirthday    = "birthday"    // the birthday of the character
+	EventAnniversary = "anniversary" // the anniversary of the creation of the character
+)
+
+// Event struct represents a yearly event of the character, celebrated with a gift.
+type Event struct {
+	ID    int
+	Kind  string
+	Years int // the age of the character, or the years since its creation
+	Coins int // the coins gifted
+	XP    int // the experience points gifted
+}
+
+// yearly struct represents a kind of yearly event: the date of the character it repeats and its gift.
+type yearly struct {
+	kind  string
+	first int                                    // the first year celebrated, the year 0 of the creation is not an anniversary
+	date  func(c *Character) time.Time           // the date of the first occurrence
+	gift  func(r config.Rewards) (coins, xp int) // the gift of the event, from the rewards of the config file
+}
+
+// yearlyEvents are the kinds of yearly events celebrated, a new kind of anniversary is added here.
+var yearlyEvents = []yearly{
+	{
+		kind: EventBirthday,
+		date: func(c *Character) time.Time { return c.BirthDate },
+		gift: func(r config.Rewards) (int, int) { return r.BirthdayCoins, 0 },
+	},
+	{
+		kind:  EventAnniversary,
+		first: 1,
+		date:  func(c *Character) time.Time { return c.CreatedAt },
+		gift:  func(r config.Rewards) (int, int) { return 0, r.AnniversaryXP },
+	},
+}
+
+// anniversary function returns the date of the anniversary of date in the year of t.
+// the 29 February falls on the 28 February in the years that are not leap years.
+func anniversary(date, t time.Time) time.Time {
+	a := time.Date(t.Year(), date.Month(), date.Day(), 0, 0, 0, 0, t.Location())
+	if a.Month() != date.Month() {
+		a = a.AddDate(0, 0, -a.Day())
+	}
+	return a
+}
+
+// yearsAt function returns the number of complete years between date and t.
+func yearsAt(date, t time.Time) int {
+	years := t.Year() - date.Year()
+	if t.Before(anniversary(date, t)) {
+		years--
+	}
+	return years
+}
+
+// occurs function returns true if the anniversary of date falls on the day of t.
+func occurs(date, t time.Time) bool {
+	a := anniversary(date, t)
+	return a.YearDay() == t.YearDay()
+}
+
+// Age function returns the age of the character at t.
+func (c *Character) Age(t time.Time) int {
+	return yearsAt(c.BirthDate, t)
+}
+
+// NextBirthday function returns the number of days from t to the next birthday of the character, 0 on the birthday.
+func (c *Character) NextBirthday(t time.Time) int {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	next := anniversary(c.BirthDate, day)
+	if next.Before(day) {
+		next = anniversary(c.BirthDate, day.AddDate(1, 0, 0))
+	}
+	return int(next.Sub(day).Hours()+12) / 24 // rounded, a day can last 23 or 25 hours
+}
+
+// Title function returns the title of the event, e.g. "Happy 30th birthday!".
+func (e Event) Title() string {
+	if e.Kind == EventBirthday {
+		return fmt.Sprintf("Happy %s birthday!", str.Ordinal(e.Years))
+	}
+	return fmt.Sprintf("Happy %s anniversary of your adventure!", str.Ordinal(e.Years))
+}
+
+// Gift function returns the description of the gift of the event, e.g. "you received 100 coins".
+func (e Event) Gift() string {
+	switch {
+	case e.Coins > 0 && e.XP > 0:
+		return fmt.Sprintf("you received %d coins and %d XP", e.Coins, e.XP)
+	case e.Coins > 0:
+		return fmt.Sprintf("you received %d coins", e.Coins)
+	case e.XP > 0:
+		return fmt.Sprintf("you received %d XP", e.XP)
+	default:
+		return "enjoy your day"
+	}
+}
+
+// celebrate function creates the yearly events of the character of the current profile that fall on the day of t,
+// gifting the character. an event already celebrated is not created again.
+func celebrate(t time.Time) error {
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	c, err := CharGet()
+	if err != nil {
+		return err
+	}
+
+	rewards := config.Get().Rewards
+	for _, y := range yearlyEvents {
+		date := y.date(c)
+		years := yearsAt(date, t)
+		if !occurs(date, t) || years < y.first {
+			continue
+		}
+
+		coins, xp := y.gift(rewards)
+		err = do("events_create", id, y.kind, years, coins, xp, coins, xp, id)
+		if err != nil {
+			log.Err("failed to celebrate the event", "kind", y.kind)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pendingEvents function returns the events of today of the current profile not yet shown in a way,
+// with the list query of the way, and marks them as shown with the mark query.
+func pendingEvents(list, mark string) ([]Event, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets(list, id)
+	if err != nil {
+		log.Err("failed to get the events")
+		return nil, err
+	}
+
+	events := []Event{}
+	for rows.Next() {
+		var e Event
+		err = rows.Scan(&e.ID, &e.Kind, &e.Years, &e.Coins, &e.XP)
+		if err != nil {
+			rows.Close()
+			log.Err("failed to scan the event")
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range events {
+		err = do(mark, e.ID)
+		if err != nil {
+			log.Err("failed to mark the event as shown", "id", e.ID)
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// EventsBanner function celebrates the yearly events of today and returns the ones whose banner has not been shown yet.
+// it is used by the daily login, the banner of an event is shown once.
+func EventsBanner() ([]Event, error) {
+	err := celebrate(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return pendingEvents("events_unshown", "events_shown")
+}
+
+// EventsNotify function celebrates the yearly events of today and returns the ones not yet notified on the desktop.
+// it is used by the cron service, the notification of an event is shown once.
+func EventsNotify() ([]Event, error) {
+	err := celebrate(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return pendingEvents("events_unnotified", "events_notified")
+}
@@ -0,0 +1,305 @@
+// db package change journal functions
+package db
+
+import (
+	"aio/pkg/git"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// action struct represents the description of a journaled action.
+// one is used when the action has been done once, many is a format string used with the number of times.
+type action struct {
+	one  string
+	many string
+}
+
+// actions maps the mutating queries recorded in the change journal to their description.
+// the queries that are not listed here (like the tables creation) are not recorded.
+var actions = map[string]action{
+	"characters_create":             {"created the character", "created %d characters"},
+	"characters_daily_login":        {"daily login", "%d daily logins"},
+	"characters_death":              {"the character died", "the character died %d times"},
+	"characters_delete":             {"deleted a profile", "deleted %d profiles"},
+	"characters_update":             {"edited the character", "edited the character %d times"},
+	"budgets_set":                   {"set a budget", "set %d budgets"},
+	"budgets_delete":                {"deleted a budget", "deleted %d budgets"},
+	"budget_penalties_create":       {"overspent a budget", "overspent %d budgets"},
+	"category_rules_create":         {"added a category rule", "added %d category rules"},
+	"category_rules_delete":         {"deleted a category rule", "deleted %d category rules"},
+	"events_create":                 {"celebrated an event", "celebrated %d events"},
+	"exchange_rates_import":         {"imported exchange rates", "imported exchange rates %d times"},
+	"import":                        {"imported data", "imported data %d times"},
+	"notifications_create":          {"showed a reminder", "showed %d reminders"},
+	"notifications_refire":          {"showed a snoozed reminder", "showed %d snoozed reminders"},
+	"notifications_snooze":          {"snoozed a reminder", "snoozed %d reminders"},
+	"recurring_transactions_create": {"added a recurring transaction", "added %d recurring transactions"},
+	"recurring_transactions_delete": {"deleted a recurring transaction", "deleted %d recurring transactions"},
+	"recurring_transactions_record": {"recorded the recurring transactions", "recorded the recurring transactions %d times"},
+	"tasks_create":                  {"added a task", "added %d tasks"},
+	"tasks_complete":                {"completed a task", "completed %d tasks"},
+	"transactions_import":           {"imported a bank statement", "imported %d bank statements"},
+	"habits_create":                 {"added a habit", "added %d habits"},
+}
+
+// stats struct represents the character stats tracked by the change journal.
+// the balance is in minor units of the currency of the character.
+type stats struct {
+	xp       int
+	coins    int
+	balance  int64
+	currency string
+	hp       int
+}
+
+// statsIn function returns the stats of the character with the given id inside a transaction.
+// if there is no character yet, it returns empty stats.
+func statsIn(tx *sql.Tx, id int64) (stats, error) {
+	s := stats{}
+	q, err := loadQuery("characters_stats")
+	if err != nil {
+		log.Err("failed to load query")
+		return s, err
+	}
+
+	err = tx.QueryRow(q, id).Scan(&s.xp, &s.coins, &s.balance, &s.currency, &s.hp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, nil
+	}
+
+	return s, err
+}
+
+// record function adds an entry to the change journal inside a transaction.
+// the entry stores the variation of the stats of the character between before and the current state.
+func record(tx *sql.Tx, action string, id int64, before stats) error {
+	after, err := statsIn(tx, id)
+	if err != nil {
+		log.Err("failed to get the character stats")
+		return err
+	}
+
+	q, err := loadQuery("journal_create")
+	if err != nil {
+		log.Err("failed to load query")
+		return err
+	}
+
+	// a deleted character has no currency, its balance is summarised in the one it had
+	currency := after.currency
+	if currency == "" {
+		currency = before.currency
+	}
+
+	_, err = tx.Exec(q, action, after.xp-before.xp, after.coins-before.coins, after.balance-before.balance, currency, after.hp-before.hp)
+	if err != nil {
+		log.Err("failed to record the change in the journal", "action", action)
+		return err
+	}
+
+	return nil
+}
+
+// signed function formats an integer with its sign.
+func signed(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprint(n)
+}
+
+// journalAck function returns a summary of the changes not yet committed, and marks them as committed
+// in the same transaction: the database committed with the summary has its changes already acknowledged.
+// the summary is used as commit message, e.g. "completed 3 tasks, +30 XP, spent 42.50 EUR".
+// it returns the ids of the first and the last change acknowledged, to restore them with journalRestore if the commit fails.
+// it returns an empty string and 0 if there are no pending changes.
+func journalAck() (string, int64, int64, error) {
+	db, err := getDb()
+	if err != nil {
+		log.Err("failed to open database")
+		return "", 0, 0, err
+	}
+
+	defer db.Close()
+
+	queries := map[string]string{}
+	for _, name := range []string{"journal_first", "journal_last", "journal_pending", "journal_balances", "journal_commit"} {
+		queries[name], err = loadQuery(name)
+		if err != nil {
+			log.Err("failed to load query")
+			return "", 0, 0, err
+		}
+	}
+
+	// the changes are read and acknowledged in a transaction, so the summary and the acknowledged changes agree
+	tx, err := db.Begin()
+	if err != nil {
+		log.Err("failed to start transaction")
+		return "", 0, 0, err
+	}
+
+	defer tx.Rollback()
+
+	var first, last int64
+	err = tx.QueryRow(queries["journal_last"]).Scan(&last)
+	if err != nil {
+		log.Err("failed to get the pending changes")
+		return "", 0, 0, err
+	}
+
+	if last == 0 {
+		return "", 0, 0, nil
+	}
+
+	err = tx.QueryRow(queries["journal_first"]).Scan(&first)
+	if err != nil {
+		log.Err("failed to get the pending changes")
+		return "", 0, 0, err
+	}
+
+	rows, err := tx.Query(queries["journal_pending"], last)
+	if err != nil {
+		log.Err("failed to get the pending changes")
+		return "", 0, 0, err
+	}
+
+	parts := []string{}
+	total := stats{}
+	for rows.Next() {
+		var name string
+		var count int
+		var s stats
+		err = rows.Scan(&name, &count, &s.xp, &s.coins, &s.hp)
+		if err != nil {
+			rows.Close()
+			log.Err("failed to scan the pending changes")
+			return "", 0, 0, err
+		}
+
+		desc := name
+		if a, ok := actions[name]; ok {
+			desc = a.one
+			if count > 1 {
+				desc = fmt.Sprintf(a.many, count)
+			}
+		}
+
+		parts = append(parts, desc)
+		total.xp += s.xp
+		total.coins += s.coins
+		total.hp += s.hp
+	}
+
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		log.Err("failed to read the pending changes")
+		return "", 0, 0, err
+	}
+
+	if total.xp != 0 {
+		parts = append(parts, signed(total.xp)+" XP")
+	}
+
+	if total.hp != 0 {
+		parts = append(parts, signed(total.hp)+" HP")
+	}
+
+	if total.coins != 0 {
+		parts = append(parts, signed(total.coins)+" coins")
+	}
+
+	rows, err = tx.Query(queries["journal_balances"], last)
+	if err != nil {
+		log.Err("failed to get the pending changes")
+		return "", 0, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var currency string
+		var balance int64
+		err = rows.Scan(&currency, &balance)
+		if err != nil {
+			log.Err("failed to scan the pending changes")
+			return "", 0, 0, err
+		}
+
+		if balance < 0 {
+			parts = append(parts, "spent "+money.Format(-balance, currency)+" "+currency)
+		} else {
+			parts = append(parts, "earned "+money.Format(balance, currency)+" "+currency)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Err("failed to read the pending changes")
+		return "", 0, 0, err
+	}
+
+	_, err = tx.Exec(queries["journal_commit"], last)
+	if err != nil {
+		log.Err("failed to mark the changes as committed")
+		return "", 0, 0, wrap(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Err("failed to commit transaction")
+		return "", 0, 0, wrap(err)
+	}
+
+	return strings.Join(parts, ", "), first, last, nil
+}
+
+// journalRestore function marks the changes from first to last as pending again, after a failed commit,
+// so they are summarised in the message of the next commit.
+func journalRestore(first, last int64) error {
+	db, err := getDb()
+	if err != nil {
+		log.Err("failed to open database")
+		return err
+	}
+
+	defer db.Close()
+
+	q, err := loadQuery("journal_restore")
+	if err != nil {
+		log.Err("failed to load query")
+		return err
+	}
+
+	_, err = db.Exec(q, first, last)
+	if err != nil {
+		log.Err("failed to mark the changes as pending")
+		return wrap(err)
+	}
+
+	return nil
+}
+
+// JournalCommit function commits the database with the summary of the pending changes appended to the message.
+// the changes are acknowledged in the database before the commit, so the commit contains its own acknowledgement,
+// and marked as pending again if the commit fails: a failed commit leaves them for the next one.
+func JournalCommit(message string) error {
+	summary, first, last, err := journalAck()
+	if err != nil {
+		log.Err("failed to summarise the changes")
+		return err
+	}
+
+	if summary != "" {
+		message = strings.TrimPrefix(message+", "+summary, ", ")
+	}
+
+	err = git.Commit(message)
+	if err != nil && last > 0 {
+		return errors.Join(err, journalRestore(first, last))
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"aio/pkg/utils/cmd"
+	"strings"
+	"testing"
+)
+
+// gitOutput function runs git in the data directory and returns its trimmed output.
+func gitOutput(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := cmd.Output("git", args...)
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// pending function returns the number of changes of the journal not yet committed.
+func pending(t *testing.T) int {
+	t.Helper()
+	row, err := get("journal_last")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last int64
+	err = row.Scan(&last)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if last == 0 {
+		return 0
+	}
+
+	n := 0
+	rows, err := gets("journal_pending", last)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var action string
+		var count, xp, coins, hp int
+		err = rows.Scan(&action, &count, &xp, &coins, &hp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n += count
+	}
+	return n
+}
+
+func TestJournalCommit(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Jane")
+	t.Setenv("GIT_AUTHOR_EMAIL", "jane@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Jane")
+	t.Setenv("GIT_COMMITTER_EMAIL", "jane@example.com")
+	setup(t)
+
+	err := TaskCreate("Pay the rent", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the acknowledgement of the changes is in the commit, nothing is left to commit after it
+	err = JournalCommit("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message := gitOutput(t, "log", "-1", "--pretty=%s"); !strings.Contains(message, "added a task") {
+		t.Errorf("commit message %q, want the added task", message)
+	}
+
+	if n := pending(t); n != 0 {
+		t.Errorf("%d pending changes after the commit, want 0", n)
+	}
+
+	if status := gitOutput(t, "status", "--porcelain", "--", "data.db"); status != "" {
+		t.Errorf("the database changed after the commit: %s", status)
+	}
+
+	// without changes nothing is committed, the last commit is not rewritten
+	head := gitOutput(t, "rev-parse", "HEAD")
+	err = JournalCommit("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if now := gitOutput(t, "rev-parse", "HEAD"); now != head {
+		t.Errorf("HEAD changed from %s to %s without changes", head, now)
+	}
+
+	// a failed commit leaves the changes pending for the next one
+	err = TaskCreate("Call the bank", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GIT_DIR", t.TempDir())
+	err = JournalCommit("")
+	if err == nil {
+		t.Fatal("JournalCommit without a repository succeeded, want an error")
+	}
+
+	if n := pending(t); n != 1 {
+		t.Errorf("%d pending changes after the failed commit, want 1", n)
+	}
+}
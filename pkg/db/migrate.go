@@ -0,0 +1,168 @@
+// db package schema migrations
+package db
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// migrations are the queries that upgrade the schema of a database created by a previous version, in order.
+// the version of the schema is the user_version of the database, the number of migrations applied:
+// a new database is created by the tables query with the last schema, so it starts at the last version.
+var migrations = []string{
+	"migrate_001_profiles",
+	"migrate_002_money",
+	"migrate_003_journal",
+}
+
+// moneyVersion is the version of the schema where the amounts became integer minor units of their currency.
+const moneyVersion = 2
+
+// journalVersion is the version of the schema where the balances of the change journal became integer minor units.
+const journalVersion = 3
+
+// moneyColumns are the columns with amounts of money of the tables, decimals in the versions before moneySince.
+var moneyColumns = map[string][]string{
+	"characters":             {"budget", "balance"},
+	"transactions":           {"amount"},
+	"recurring_transactions": {"amount"},
+	"journal":                {"balance"},
+}
+
+// moneySince function returns the version of the schema where the amounts of a table became integer minor units.
+func moneySince(table string) int {
+	if table == "journal" {
+		return journalVersion
+	}
+	return moneyVersion
+}
+
+// legacyMoney function returns the currency of the amounts of the versions before moneyVersion,
+// money.currency in the config, and the number of its minor units in a unit.
+func legacyMoney() (string, float64) {
+	currency := config.Get().Money.Currency
+	return currency, math.Pow10(money.Exponent(currency))
+}
+
+// migrationArgs function returns the parameters of a migration, in the order of the placeholders of its queries.
+func migrationArgs(name string) []any {
+	switch name {
+	case "migrate_002_money":
+		currency, units := legacyMoney()
+		return []any{currency, units, units, units, units}
+	case "migrate_003_journal":
+		currency, units := legacyMoney()
+		return []any{units, currency}
+	default:
+		return nil
+	}
+}
+
+// migrate function applies the migrations not yet applied to the database.
+// every migration runs in its own transaction with the update of the version,
+// so a failed migration leaves the database at the previous version.
+func migrate() error {
+	db, err := getDb()
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	var version int
+	err = db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		log.Err("failed to get the schema version")
+		return wrap(err)
+	}
+
+	var exists bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'characters')").Scan(&exists)
+	if err != nil {
+		log.Err("failed to check the schema")
+		return wrap(err)
+	}
+
+	// a new database has nothing to migrate
+	if !exists {
+		version = len(migrations)
+	}
+
+	for ; version < len(migrations); version++ {
+		name := migrations[version]
+		log.Info("migrating the database", "migration", name)
+		q, err := loadQuery(name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			log.Err("failed to start transaction")
+			return wrap(err)
+		}
+
+		_, err = tx.Exec(q, migrationArgs(name)...)
+		if err != nil {
+			tx.Rollback()
+			log.Err("failed to migrate the database", "migration", name)
+			return wrap(err)
+		}
+
+		// the pragma does not accept parameters, the version is a number
+		_, err = tx.Exec("PRAGMA user_version = " + strconv.Itoa(version+1))
+		if err != nil {
+			tx.Rollback()
+			log.Err("failed to set the schema version")
+			return wrap(err)
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			tx.Rollback()
+			log.Err("failed to commit transaction")
+			return wrap(err)
+		}
+	}
+
+	_, err = db.Exec("PRAGMA user_version = " + strconv.Itoa(version))
+	if err != nil {
+		log.Err("failed to set the schema version")
+		return wrap(err)
+	}
+
+	return nil
+}
+
+// Upgrade function upgrades the rows of a table exported by a previous version of the schema to the current one:
+// the amounts of the versions before moneySince are converted to minor units of their currency, money.currency in the config.
+func Upgrade(t Table, version int) Table {
+	columns, ok := moneyColumns[t.Name]
+	if !ok || version >= moneySince(t.Name) || len(t.Columns) == 0 {
+		return t
+	}
+
+	currency, units := legacyMoney()
+	rows := make([][]any, len(t.Rows))
+	for i, row := range t.Rows {
+		rows[i] = append(slices.Clone(row), currency)
+		for j, c := range t.Columns {
+			if !slices.Contains(columns, c) {
+				continue
+			}
+
+			switch v := row[j].(type) {
+			case int64:
+				rows[i][j] = v * int64(units)
+			case float64:
+				rows[i][j] = int64(math.Round(v * units))
+			}
+		}
+	}
+
+	return Table{Name: t.Name, Columns: append(slices.Clone(t.Columns), "currency"), Rows: rows}
+}
@@ -0,0 +1,226 @@
+// db package notifications functions
+package db
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/tm"
+	"database/sql"
+	"time"
+)
+
+// NotificationFind function returns the id of the notification of a reminder, 0 if it has not been shown yet.
+func NotificationFind(kind string, ref int, due time.Time, lead time.Duration) (int, error) {
+	var id int
+	row, err := get("notifications_find", kind, ref, tm.DBFormat(due), lead.String())
+	if err != nil {
+		log.Err("failed to find the notification")
+		return 0, err
+	}
+
+	err = row.Scan(&id)
+	if err != nil {
+		log.Err("failed to scan the notification id")
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// NotificationCreate function records a reminder shown to the user and returns its id.
+// if the reminder has already been recorded, it is ignored.
+func NotificationCreate(kind string, ref int, title string, due time.Time, lead time.Duration) (int, error) {
+	id, err := current()
+	if err != nil {
+		return 0, err
+	}
+
+	err = do("notifications_create", id, kind, ref, title, tm.DBFormat(due), lead.String())
+	if err != nil {
+		log.Err("failed to create the notification")
+		return 0, err
+	}
+
+	return NotificationFind(kind, ref, due, lead)
+}
+
+// NotificationShow function records the reminder with every lead time reached and shows it with show,
+// that gets the id of the notification of lead, in the same transaction: if show fails nothing is recorded,
+// the reminder is shown again by the next run. the lead times already recorded are ignored.
+func NotificationShow(kind string, ref int, title string, due time.Time, leads []time.Duration, lead time.Duration, show func(id int) error) error {
+	char, err := current()
+	if err != nil {
+		return err
+	}
+
+	db, err := getDb()
+	if err != nil {
+		log.Err("failed to open database")
+		return err
+	}
+
+	defer db.Close()
+
+	queries := map[string]string{}
+	for _, name := range []string{"notifications_create", "notifications_find"} {
+		queries[name], err = loadQuery(name)
+		if err != nil {
+			log.Err("failed to load query")
+			return err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Err("failed to start transaction")
+		return wrap(err)
+	}
+
+	defer tx.Rollback()
+
+	before, err := statsIn(tx, char)
+	if err != nil {
+		return err
+	}
+
+	created := int64(0)
+	for _, l := range leads {
+		res, err := tx.Exec(queries["notifications_create"], char, kind, ref, title, tm.DBFormat(due), l.String())
+		if err != nil {
+			log.Err("failed to create the notification")
+			return wrap(err)
+		}
+
+		n, _ := res.RowsAffected()
+		created += n
+	}
+
+	var id int
+	err = tx.QueryRow(queries["notifications_find"], kind, ref, tm.DBFormat(due), lead.String()).Scan(&id)
+	if err != nil {
+		log.Err("failed to find the notification")
+		return wrap(err)
+	}
+
+	err = show(id)
+	if err != nil {
+		return err
+	}
+
+	if created > 0 {
+		err = record(tx, "notifications_create", char, before)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Err("failed to commit transaction")
+		return wrap(err)
+	}
+
+	return nil
+}
+
+// NotificationRefire function records that a snoozed notification has been shown again at t.
+func NotificationRefire(id int, t time.Time) error {
+	err := do("notifications_refire", tm.DBFormat(t), id)
+	if err != nil {
+		log.Err("failed to update the notification", "id", id)
+		return err
+	}
+
+	return nil
+}
+
+// NotificationSnooze function snoozes a notification of the current profile until the given time.
+func NotificationSnooze(id int, until time.Time) error {
+	char, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("notifications_snooze", tm.DBFormat(until), id, char)
+	if err != nil {
+		log.Err("failed to snooze the notification", "id", id)
+		return err
+	}
+
+	return nil
+}
+
+// scanNotifications function scans the rows of a notifications query.
+func scanNotifications(rows *sql.Rows) ([]Notification, error) {
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		var n Notification
+		var due, lead, fired string
+		var snoozed sql.NullString
+		err := rows.Scan(&n.ID, &n.Kind, &n.RefID, &n.Title, &due, &lead, &fired, &snoozed)
+		if err != nil {
+			log.Err("failed to scan the notification")
+			return nil, err
+		}
+
+		n.DueAt, err = tm.DBParse(due)
+		if err != nil {
+			return nil, err
+		}
+
+		n.Lead, err = time.ParseDuration(lead)
+		if err != nil {
+			return nil, err
+		}
+
+		n.FiredAt, err = tm.DBParse(fired)
+		if err != nil {
+			return nil, err
+		}
+
+		if snoozed.Valid {
+			s, err := tm.DBParse(snoozed.String)
+			if err != nil {
+				return nil, err
+			}
+			n.SnoozedUntil = &s
+		}
+
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// NotificationsSnoozedDue function returns the snoozed notifications of the current profile that must be shown again at t.
+func NotificationsSnoozedDue(t time.Time) ([]Notification, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("notifications_snoozed_due", id, tm.DBFormat(t))
+	if err != nil {
+		log.Err("failed to get the snoozed notifications")
+		return nil, err
+	}
+
+	return scanNotifications(rows)
+}
+
+// Notifications function returns the last notifications shown to the current profile.
+func Notifications(limit int) ([]Notification, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("notifications_list", id, limit)
+	if err != nil {
+		log.Err("failed to get the notifications")
+		return nil, err
+	}
+
+	return scanNotifications(rows)
+}
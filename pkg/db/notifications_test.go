@@ -0,0 +1,70 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNotificationShow(t *testing.T) {
+	setup(t)
+
+	due := time.Date(2026, time.October, 20, 18, 0, 0, 0, time.Local)
+	leads := []time.Duration{time.Hour, 15 * time.Minute}
+	before := pending(t)
+
+	// a reminder not shown is not recorded, nor journaled
+	err := NotificationShow("task", 1, "Pay the rent", due, leads, 15*time.Minute, func(int) error {
+		return errors.New("no notification daemon")
+	})
+
+	if err == nil {
+		t.Fatal("the error of show is not returned")
+	}
+
+	for _, l := range leads {
+		id, err := NotificationFind("task", 1, due, l)
+		if err != nil || id != 0 {
+			t.Errorf("NotificationFind(%s) = %d, %v after a failed show, want 0", l, id, err)
+		}
+	}
+
+	if n := pending(t); n != before {
+		t.Errorf("%d pending changes after a failed show, want %d", n, before)
+	}
+
+	// a reminder shown is recorded for every lead, show gets the id of the smallest one
+	shown := 0
+	err = NotificationShow("task", 1, "Pay the rent", due, leads, 15*time.Minute, func(id int) error {
+		shown = id
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := NotificationFind("task", 1, due, 15*time.Minute)
+	if err != nil || id == 0 || id != shown {
+		t.Errorf("NotificationFind() = %d, %v, want the id shown %d", id, err, shown)
+	}
+
+	id, err = NotificationFind("task", 1, due, time.Hour)
+	if err != nil || id == 0 {
+		t.Errorf("NotificationFind(1h) = %d, %v, want the notification recorded", id, err)
+	}
+
+	if n := pending(t); n != before+1 {
+		t.Errorf("%d pending changes after the reminder shown, want %d", n, before+1)
+	}
+
+	// the leads already recorded are not journaled again
+	err = NotificationShow("task", 1, "Pay the rent", due, leads, 15*time.Minute, func(int) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := pending(t); n != before+1 {
+		t.Errorf("%d pending changes after the reminder shown again, want %d", n, before+1)
+	}
+}
@@ -0,0 +1,238 @@
+// db package onboarding functions
+package db
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/inputs"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"aio/pkg/utils/num"
+	"aio/pkg/utils/tm"
+	"database/sql"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Seed struct contains the values of the initial character, used by Init when the database has no character.
+// an empty value is read from its environment variable, and if it is empty too the user is asked for it.
+// it also contains the new values of the character edited with CharEdit, there the variables are not read.
+type Seed struct {
+	FirstName string // AIO_FIRST_NAME
+	LastName  string // AIO_LAST_NAME
+	NickName  string // AIO_NICKNAME
+	BirthDate string // AIO_BIRTH_DATE, in the form of 02 Jan 2006
+	Currency  string // AIO_CURRENCY, the base currency, e.g. EUR
+	Budget    string // AIO_BUDGET, the monthly budget in the base currency
+}
+
+// onboardingFields is the form of the onboarding, the keys of the fields are the environment variables of the values.
+var onboardingFields = []inputs.Field{
+	{
+		Key:         "AIO_FIRST_NAME",
+		Description: "Welcome, traveler! Before we begin your adventure, we need to know your name.",
+		Title:       "What is your first name, brave soul?",
+		Placeholder: "Jhon",
+		Validate:    inputs.NotEmpty,
+	},
+	{
+		Key:         "AIO_LAST_NAME",
+		Description: "A strong name indeed! Now, please tell us your family name, the one that will echo through the halls of history.",
+		Title:       "What is your last name, worthy adventurer?",
+		Placeholder: "Smith",
+		Validate:    inputs.NotEmpty,
+	},
+	{
+		Key:         "AIO_NICKNAME",
+		Description: "Every hero has a title that the bards will sing of! Choose a nickname, one that will strike fear into your foes or inspire your allies.",
+		Title:       "What shall your unique nickname be?",
+		Placeholder: "The Reaper",
+		Validate:    newNickname,
+	},
+	{
+		Key:         "AIO_BIRTH_DATE",
+		Description: "Even legends have a beginning. We need to know when your story began.\nPlease provide your date of birth in the form of 02 Jan 2006.",
+		Title:       "When were you born, chosen one?",
+		Placeholder: "02 Jan 2006",
+		Validate:    tm.ValidateDate,
+	},
+	{
+		Key:         "AIO_CURRENCY",
+		Description: "Gold comes in many forms across the realms. Choose the currency of your treasury,\nthe amounts in other currencies will be converted to it.",
+		Title:       "Which currency do you count your gold in?",
+		Placeholder: "EUR",
+		Validate:    validateCurrency,
+	},
+	{
+		Key:         "AIO_BUDGET",
+		Description: "Every great adventurer must wisely manage their resources, not just in battle, but also in life.\nSet your monthly budget this will guide how you manage your gold throughout your journey!",
+		Title:       "How much gold will you allocate each month for your expenses?",
+		Default:     "1500.00",
+		Validate:    num.Validate,
+		Check:       validateBudget,
+	},
+}
+
+// newNickname function validates the nickname of a new character, it is the name of its profile so it must be unique.
+func newNickname(s string) error {
+	err := inputs.NotEmpty(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = characterID(s)
+	if err == nil {
+		return errors.New("the nickname " + s + " is already used by another profile")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	return err
+}
+
+// validateCurrency function validates the base currency of a character, a three letters ISO 4217 code.
+func validateCurrency(s string) error {
+	_, err := money.Currency(s)
+	return err
+}
+
+// validateBudget function validates the monthly budget of a character in the currency chosen before it,
+// it can't have more decimals than the currency, e.g. none for JPY.
+func validateBudget(s string, values map[string]string) error {
+	currency, err := money.Currency(values["AIO_CURRENCY"])
+	if err != nil {
+		return err
+	}
+
+	_, err = parseBudget(s, currency)
+	var ve ValueError
+	if errors.As(err, &ve) {
+		return ve.Err
+	}
+	return err
+}
+
+// parseBudget function parses the monthly budget of a character in minor units of its currency.
+func parseBudget(budget, currency string) (int64, error) {
+	n, err := num.ParseFloat(budget)
+	if err != nil {
+		return 0, err
+	}
+
+	// the budget is written in the form of the number validation, e.g. 1500 or 1500.5
+	minor, err := money.Parse(strconv.FormatFloat(n, 'f', -1, 64), currency)
+	if err != nil {
+		return 0, ValueError{Key: "AIO_BUDGET", Err: err}
+	}
+	return minor, nil
+}
+
+// createCharacter function creates a character, the initial one or the one of a new profile, and returns its nickname.
+// the values of the seed, or of their environment variables, are validated and used as they are,
+// the missing ones are asked to the user with the onboarding form, or set to their default without a terminal.
+func createCharacter(seed Seed) (string, error) {
+	values := map[string]string{
+		"AIO_FIRST_NAME": seed.FirstName,
+		"AIO_LAST_NAME":  seed.LastName,
+		"AIO_NICKNAME":   seed.NickName,
+		"AIO_BIRTH_DATE": seed.BirthDate,
+		"AIO_CURRENCY":   seed.Currency,
+		"AIO_BUDGET":     seed.Budget,
+	}
+
+	missing := []inputs.Field{}
+	for _, field := range onboardingFields {
+		if field.Key == "AIO_CURRENCY" {
+			field.Default = config.Get().Money.Currency
+		}
+
+		value := values[field.Key]
+		if value == "" {
+			value = os.Getenv(field.Key)
+		}
+
+		if value == "" && !inputs.Interactive() {
+			value = field.Default // without a terminal the default is used, if the field has one
+		}
+
+		if value == "" {
+			missing = append(missing, field)
+			continue
+		}
+
+		err := field.Validate(value)
+		if err != nil {
+			return "", ValueError{Key: field.Key, Err: err}
+		}
+		values[field.Key] = value
+	}
+
+	if len(missing) > 0 {
+		if inputs.Interactive() {
+			log.PrintS("Welcome to AIO - Your Life, Gamified!", log.TitleStyle)
+			log.Print(`
+Turn your tasks, goals, and habits into an adventure.
+Track progress, manage your finances, boost productivity, and level up in all aspects of life.
+Ready to make self-improvement fun? Your journey starts now!
+`)
+		}
+
+		// the values already given are known by the form, e.g. the currency of the budget
+		known := map[string]string{}
+		for key, value := range values {
+			if value != "" {
+				known[key] = value
+			}
+		}
+
+		answers, err := inputs.RunForm(inputs.Form{Title: "Create your character", Fields: missing, Values: known})
+		if errors.Is(err, inputs.ErrNoInput) {
+			keys := make([]string, len(missing))
+			for i, field := range missing {
+				keys[i] = field.Key
+			}
+			return "", errors.Join(errors.New("missing "+strings.Join(keys, ", ")+" for the character"), err)
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		for key, value := range answers {
+			values[key] = value
+		}
+	}
+
+	currency, err := money.Currency(values["AIO_CURRENCY"])
+	if err != nil {
+		return "", ValueError{Key: "AIO_CURRENCY", Err: err}
+	}
+
+	budget, err := parseBudget(values["AIO_BUDGET"], currency)
+	if err != nil {
+		log.Err("failed to parse budget")
+		return "", err
+	}
+
+	birth, err := tm.DBReformat(values["AIO_BIRTH_DATE"])
+	if err != nil {
+		log.Err("failed to reformat birth date")
+		return "", err
+	}
+
+	fn, ln, nn := values["AIO_FIRST_NAME"], values["AIO_LAST_NAME"], values["AIO_NICKNAME"]
+	err = do("characters_create", fn, ln, nn, birth, budget, currency)
+	if err != nil {
+		log.Err("failed to create character")
+		return "", err
+	}
+
+	log.Print("🎉 Your character has been created! 🎉")
+	log.Print("Welcome, %s %s, also known as %s!", fn, ln, nn)
+	log.Print("Now, go forth and conquer the challenges ahead!")
+	log.Print("\nTo view an help text use the command 'aio --help', or 'aio -h'.\n")
+	return nn, nil
+}
@@ -0,0 +1,26 @@
+package db
+
+import "testing"
+
+func TestValidateBudget(t *testing.T) {
+	tests := []struct {
+		budget   string
+		currency string
+		invalid  bool
+	}{
+		{budget: "1500", currency: "EUR"},
+		{budget: "1500.50", currency: "EUR"},
+		{budget: "1500.505", currency: "EUR", invalid: true},
+		{budget: "1500", currency: "JPY"},
+		{budget: "1500.00", currency: "jpy"},
+		{budget: "1500.5", currency: "JPY", invalid: true},
+		{budget: "1500.5", currency: "", invalid: true},
+	}
+
+	for _, tt := range tests {
+		err := validateBudget(tt.budget, map[string]string{"AIO_CURRENCY": tt.currency})
+		if (err != nil) != tt.invalid {
+			t.Errorf("validateBudget(%q, %q) = %v, want an error %v", tt.budget, tt.currency, err, tt.invalid)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// db package profiles functions
+package db
+
+import (
+	"aio/pkg/log"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Profile struct represents a profile, the character of a member of the household.
+type Profile struct {
+	ID        int64
+	NickName  string
+	FirstName string
+	LastName  string
+	Level     int
+	Active    bool // the profile used by the commands
+}
+
+// profile is the nickname of the profile chosen with SetProfile, e.g. with the --profile flag.
+var profile string
+
+// character is the id of the character of the current profile, resolved by Init.
+// it is 0 in the processes that do not call Init, like the cron service: the profile is resolved at every query,
+// except inside ForEachProfile, that runs the jobs of the cron service for every profile in turn.
+var character int64
+
+// scoped is set by Scope in the cron service: the current profile is read only inside ForEachProfile.
+var scoped bool
+
+// mu guards character and scoped, the jobs of the cron service run at the same time.
+// each makes the runs of ForEachProfile wait for each other, so a job does not change the profile of another one.
+var (
+	mu   sync.Mutex
+	each sync.Mutex
+)
+
+// SetProfile function chooses the profile used by the commands, by the nickname of its character.
+// with an empty nickname the active profile is used, the one chosen with ProfileSwitch.
+func SetProfile(nickname string) {
+	profile = nickname
+	setCharacter(0)
+}
+
+// setCharacter function sets the id of the character of the current profile, 0 resolves it at every query.
+func setCharacter(id int64) {
+	mu.Lock()
+	defer mu.Unlock()
+	character = id
+}
+
+// Scope function makes the current profile readable only inside ForEachProfile, that holds each for its runs.
+// the cron service calls it at start: a job reading the profile outside ForEachProfile fails with ErrNoScope,
+// instead of reading the active profile, or the profile of the run of another job.
+func Scope() {
+	mu.Lock()
+	defer mu.Unlock()
+	scoped = true
+}
+
+// current function returns the id of the character of the current profile.
+func current() (int64, error) {
+	mu.Lock()
+	id, s := character, scoped
+	mu.Unlock()
+	if id != 0 {
+		return id, nil
+	}
+
+	if s {
+		return 0, ErrNoScope
+	}
+
+	return resolve()
+}
+
+// ForEachProfile function runs fn once for every profile, with its character as the current one,
+// so the cron service reminds, records and penalizes every member of the household, not only the active profile.
+// the current profile is restored after, a failure of fn for a profile does not stop the other ones,
+// the errors are returned together.
+func ForEachProfile(fn func(p Profile) error) error {
+	each.Lock()
+	defer each.Unlock()
+
+	profiles, err := listProfiles(0)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	previous := character
+	mu.Unlock()
+	defer setCharacter(previous)
+
+	errs := []error{}
+	for _, p := range profiles {
+		setCharacter(p.ID)
+		err = fn(p)
+		if err != nil {
+			log.Err("failed to run for the profile", "profile", p.NickName)
+			errs = append(errs, errors.Join(errors.New("profile "+p.NickName), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// resolve function returns the id of the character of the profile chosen with SetProfile,
+// or of the active profile, or of the first character created if there is no active profile.
+func resolve() (int64, error) {
+	if profile != "" {
+		id, err := characterID(profile)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.Join(ErrNoProfile, errors.New("profile "+profile+" not found"))
+		}
+		return id, err
+	}
+
+	active, err := settingGet("profile")
+	if err != nil {
+		return 0, err
+	}
+
+	if active != "" {
+		var exists bool
+		row, err := get("characters_exists_id", active)
+		if err != nil {
+			return 0, err
+		}
+
+		err = row.Scan(&exists)
+		if err != nil {
+			log.Err("failed to check the active profile")
+			return 0, wrap(err)
+		}
+
+		// the active profile may have been deleted on another device, the first one is used
+		if exists {
+			return strconv.ParseInt(active, 10, 64)
+		}
+	}
+
+	var id int64
+	row, err := get("characters_first")
+	if err != nil {
+		return 0, err
+	}
+
+	err = row.Scan(&id)
+	if err != nil {
+		return 0, wrapChar(err)
+	}
+
+	return id, nil
+}
+
+// characterID function returns the id of the character with the given nickname.
+func characterID(nickname string) (int64, error) {
+	var id int64
+	row, err := get("characters_find", nickname)
+	if err != nil {
+		return 0, err
+	}
+
+	err = row.Scan(&id)
+	if err != nil {
+		return 0, wrap(err)
+	}
+
+	return id, nil
+}
+
+// Profiles function returns the profiles, the active one is marked.
+func Profiles() ([]Profile, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	return listProfiles(id)
+}
+
+// listProfiles function returns the profiles, the one of the character with the given id is marked as active.
+func listProfiles(active int64) ([]Profile, error) {
+	rows, err := gets("characters_list")
+	if err != nil {
+		log.Err("failed to get the profiles")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	profiles := []Profile{}
+	for rows.Next() {
+		var p Profile
+		err = rows.Scan(&p.ID, &p.NickName, &p.FirstName, &p.LastName, &p.Level)
+		if err != nil {
+			log.Err("failed to scan the profile")
+			return nil, err
+		}
+
+		p.Active = p.ID == active
+		profiles = append(profiles, p)
+	}
+
+	return profiles, rows.Err()
+}
+
+// ProfileCreate function creates a new profile with a new character, the values of the seed are used like in Init.
+// it returns the nickname of the new character.
+func ProfileCreate(seed Seed) (string, error) {
+	return createCharacter(seed)
+}
+
+// ProfileSwitch function makes the profile with the given nickname the active one.
+// the active profile is kept in the state database, so every device has its own.
+func ProfileSwitch(nickname string) error {
+	id, err := characterID(nickname)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Join(ErrNoProfile, errors.New("profile "+nickname+" not found"))
+	}
+
+	if err != nil {
+		log.Err("failed to find the profile", "profile", nickname)
+		return err
+	}
+
+	err = settingSet("profile", strconv.FormatInt(id, 10))
+	if err != nil {
+		log.Err("failed to switch the profile", "profile", nickname)
+		return err
+	}
+
+	SetProfile("")
+	setCharacter(id)
+	return nil
+}
+
+// ProfileDelete function deletes the profile with the given nickname, with every row of its character.
+// the current profile can't be deleted, switch to another one first.
+func ProfileDelete(nickname string) error {
+	id, err := characterID(nickname)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Join(ErrNoProfile, errors.New("profile "+nickname+" not found"))
+	}
+
+	if err != nil {
+		log.Err("failed to find the profile", "profile", nickname)
+		return err
+	}
+
+	cur, err := current()
+	if err != nil {
+		return err
+	}
+
+	if id == cur {
+		return errors.Join(ErrProfileInUse, errors.New("profile "+nickname))
+	}
+
+	err = do("characters_delete", id)
+	if err != nil {
+		log.Err("failed to delete the profile", "profile", nickname)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestForEachProfile(t *testing.T) {
+	setup(t)
+
+	_, err := ProfileCreate(Seed{FirstName: "John", LastName: "Smith", NickName: "Jo", BirthDate: "03 Mar 1998", Currency: "JPY", Budget: "50000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every profile is the current one in turn, the failure of one does not stop the others
+	seen := []string{}
+	err = ForEachProfile(func(p Profile) error {
+		c, err := CharGet()
+		if err != nil {
+			return err
+		}
+
+		if c.NickName != p.NickName {
+			t.Errorf("the current character is %s in the run of %s", c.NickName, p.NickName)
+		}
+
+		seen = append(seen, c.NickName+" "+c.Currency)
+		if p.NickName == "Jay" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	if err == nil {
+		t.Error("the error of a profile is not returned")
+	}
+
+	if want := []string{"Jay EUR", "Jo JPY"}; !slices.Equal(seen, want) {
+		t.Errorf("profiles %q, want %q", seen, want)
+	}
+
+	// the current profile is restored
+	c, err := CharGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.NickName != "Jay" {
+		t.Errorf("the current character after the runs is %s, want Jay", c.NickName)
+	}
+}
+
+func TestScope(t *testing.T) {
+	setup(t)
+
+	// like the cron service, that does not call Init
+	setCharacter(0)
+	Scope()
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		scoped = false
+	})
+
+	_, err := CharGet()
+	if !errors.Is(err, ErrNoScope) {
+		t.Errorf("the current profile read outside ForEachProfile: got %v, want ErrNoScope", err)
+	}
+
+	err = ForEachProfile(func(p Profile) error {
+		c, err := CharGet()
+		if err != nil {
+			return err
+		}
+
+		if c.NickName != p.NickName {
+			t.Errorf("the current character is %s in the run of %s", c.NickName, p.NickName)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = CharGet()
+	if !errors.Is(err, ErrNoScope) {
+		t.Errorf("the current profile read after ForEachProfile: got %v, want ErrNoScope", err)
+	}
+}
@@ -0,0 +1,13 @@
+-- File: budget_penalties_create.sql
+-- Purpose: Penalise a character for a budget overspent in a month, unless the overspending has already been penalised.
+
+-- Create the penalty
+INSERT OR IGNORE INTO budget_penalties (character_id, category, month, overspent, hp, karma)
+VALUES(?, ?, ?, ?, ?, ?);
+
+-- Damage the character, the health points don't go below zero
+UPDATE characters
+SET hp = MAX(hp - ?, 0),
+    karma = karma - ?
+WHERE id = ?
+AND changes() > 0;
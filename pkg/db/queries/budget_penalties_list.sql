@@ -0,0 +1,6 @@
+-- File: budget_penalties_list.sql
+-- Purpose: Get the categories of the budgets of a character already penalised in a month.
+SELECT category
+FROM budget_penalties
+WHERE character_id = ?
+AND month = ?;
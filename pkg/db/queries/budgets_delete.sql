@@ -0,0 +1,5 @@
+-- File: budgets_delete.sql
+-- Purpose: Delete the budget of a category of a character, its penalties are kept.
+DELETE FROM budgets
+WHERE character_id = ?
+AND category = ?;
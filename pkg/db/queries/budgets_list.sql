@@ -0,0 +1,6 @@
+-- File: budgets_list.sql
+-- Purpose: Get the budgets of the categories of a character.
+SELECT id, category, amount, rollover, carried, starts_at
+FROM budgets
+WHERE character_id = ?
+ORDER BY category;
@@ -0,0 +1,9 @@
+-- File: budgets_set.sql
+-- Purpose: Set the monthly budget of a category of a character, the budget of the category is replaced.
+INSERT INTO budgets (character_id, category, amount, rollover, carried, starts_at)
+VALUES(?, ?, ?, ?, ?, ?)
+ON CONFLICT (character_id, category) DO UPDATE
+SET amount = excluded.amount,
+    rollover = excluded.rollover,
+    carried = excluded.carried,
+    starts_at = excluded.starts_at;
@@ -0,0 +1,15 @@
+-- File: category_rules_create.sql
+-- Purpose: Create a category rule, or change the category of the rule with the same pattern,
+-- and categorise the transactions not yet categorised that match it.
+
+-- Create the rule
+INSERT INTO category_rules (character_id, pattern, category)
+VALUES(?, ?, ?)
+ON CONFLICT (character_id, pattern) DO UPDATE SET category = excluded.category;
+
+-- Categorise the matching transactions
+UPDATE transactions
+SET category = ?
+WHERE character_id = ?
+AND category IS NULL
+AND instr(lower(description || ' ' || payee), lower(?)) > 0;
@@ -0,0 +1,5 @@
+-- File: category_rules_delete.sql
+-- Purpose: Delete a category rule of a character, the categorised transactions keep their category.
+DELETE FROM category_rules
+WHERE id = ?
+AND character_id = ?;
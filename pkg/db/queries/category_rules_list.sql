@@ -0,0 +1,6 @@
+-- File: category_rules_list.sql
+-- Purpose: Get the category rules of a character.
+SELECT id, pattern, category
+FROM category_rules
+WHERE character_id = ?
+ORDER BY category, pattern;
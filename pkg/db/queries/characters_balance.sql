@@ -0,0 +1,6 @@
+-- File: characters_balance.sql
+-- Purpose: Add an amount to the balance of a character, e.g. the sum of the imported transactions,
+-- in minor units of the currency of the character.
+UPDATE characters
+SET balance = balance + ?
+WHERE id = ?;
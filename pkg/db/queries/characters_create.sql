@@ -1,4 +1,4 @@
 -- File: characters_create.sql
 -- Purpose: Create a new character in the database.
-INSERT INTO characters (firstname, lastname, nickname, birthday, budget)
-VALUES(?, ?, ?, ?, ?);
\ No newline at end of file
+INSERT INTO characters (firstname, lastname, nickname, birthday, budget, currency)
+VALUES(?, ?, ?, ?, ?, ?);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- File: characters_currency.sql
+-- Purpose: Get the base currency of a character, of its budget, of its balance and of its reports.
+SELECT currency
+FROM characters
+WHERE id = ?;
@@ -1,5 +1,6 @@
 -- File: characters_daily_login.sql
 -- Purpose: Increments character stats and create a new daily login in the database.
+-- the id of the character is passed twice, once for every statement.
 
 -- Increment character stats
 UPDATE characters
@@ -9,7 +10,8 @@ SET hp = CASE
         ELSE max_hp
     END,
     pp = max_pp
-WHERE id = 1;
+WHERE id = ?;
 
 -- Create a new daily login
-INSERT INTO daily_logins DEFAULT VALUES;
\ No newline at end of file
+INSERT INTO daily_logins (character_id)
+VALUES(?);
\ No newline at end of file
@@ -10,4 +10,4 @@ SET hp = 100,
     level = 1,
     xp = 0,
     next_level_xp = 50
-WHERE id = 1;
+WHERE id = ?;
@@ -0,0 +1,4 @@
+-- File: characters_delete.sql
+-- Purpose: Delete a character, its tasks, habits, notifications and daily logins are deleted by the foreign keys.
+DELETE FROM characters
+WHERE id = ?;
@@ -0,0 +1,3 @@
+-- File: characters_exists_id.sql
+-- Purpose: Check if the character with the given id exists.
+SELECT EXISTS(SELECT 1 FROM characters WHERE id = ?);
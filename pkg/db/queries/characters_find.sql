@@ -0,0 +1,5 @@
+-- File: characters_find.sql
+-- Purpose: Get the id of the character with the given nickname, the name of its profile.
+SELECT id
+FROM characters
+WHERE nickname = ?;
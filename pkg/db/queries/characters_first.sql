@@ -0,0 +1,6 @@
+-- File: characters_first.sql
+-- Purpose: Get the id of the first character created, the profile used when no other one is chosen.
+SELECT id
+FROM characters
+ORDER BY id
+LIMIT 1;
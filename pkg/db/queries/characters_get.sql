@@ -7,6 +7,7 @@ nickname,
 birthday,
 budget,
 balance,
+currency,
 coins,
 xp,
 next_level_xp,
@@ -19,4 +20,4 @@ karma,
 created_at,
 updated_at
 FROM characters
-WHERE id = 1;
\ No newline at end of file
+WHERE id = ?;
\ No newline at end of file
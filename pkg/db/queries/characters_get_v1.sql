@@ -0,0 +1,23 @@
+-- File: characters_get_v1.sql
+-- Purpose: Get the character from a database before the currencies, e.g. an old snapshot, with its amounts in minor units.
+SELECT
+firstname,
+lastname,
+nickname,
+birthday,
+CAST(ROUND(budget * ?) AS INTEGER),
+CAST(ROUND(balance * ?) AS INTEGER),
+? AS currency,
+coins,
+xp,
+next_level_xp,
+level,
+pp,
+max_pp,
+hp,
+max_hp,
+karma,
+created_at,
+updated_at
+FROM characters
+WHERE id = ?;
@@ -0,0 +1,5 @@
+-- File: characters_list.sql
+-- Purpose: Get the characters of the profiles.
+SELECT id, nickname, firstname, lastname, level
+FROM characters
+ORDER BY id;
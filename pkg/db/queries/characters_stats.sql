@@ -0,0 +1,5 @@
+-- File: characters_stats.sql
+-- Purpose: Get the character stats tracked by the change journal.
+SELECT xp, coins, balance, currency, hp
+FROM characters
+WHERE id = ?;
@@ -0,0 +1,19 @@
+-- File: characters_update.sql
+-- Purpose: Change the values chosen by the user of a character.
+-- a new currency converts the balance and the budgets of the categories, the factor parameters are the exchange rate
+-- between the minor units of the currencies, the result is rounded half away from zero like money.Convert.
+-- the changes are recorded in the audit table and the updated_at field is refreshed by the triggers of the characters table.
+UPDATE characters
+SET firstname = ?,
+    lastname = ?,
+    nickname = ?,
+    birthday = ?,
+    budget = ?,
+    balance = CAST(ROUND(balance * ?) AS INTEGER),
+    currency = ?
+WHERE id = ?;
+
+UPDATE budgets
+SET amount = CAST(ROUND(amount * ?) AS INTEGER),
+    carried = CAST(ROUND(carried * ?) AS INTEGER)
+WHERE character_id = ?;
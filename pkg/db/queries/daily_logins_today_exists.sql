@@ -1,7 +1,8 @@
 -- File: daily_logins_today_exists.sql
--- Purpose: Check if there are any daily logins in the database for today.
+-- Purpose: Check if the character has a daily login in the database for today.
 SELECT EXISTS(
 SELECT 1 
 FROM daily_logins 
-WHERE DATE(created_at) = DATE('now', 'localtime')
+WHERE character_id = ?
+AND DATE(created_at) = DATE('now', 'localtime')
 );
\ No newline at end of file
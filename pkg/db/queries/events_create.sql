@@ -0,0 +1,13 @@
+-- File: events_create.sql
+-- Purpose: Celebrate a yearly event of a character and gift it, unless the event has already been celebrated.
+
+-- Create the event
+INSERT OR IGNORE INTO events (character_id, kind, years, coins, xp)
+VALUES(?, ?, ?, ?, ?);
+
+-- Gift the character
+UPDATE characters
+SET coins = coins + ?,
+    xp = xp + ?
+WHERE id = ?
+AND changes() > 0;
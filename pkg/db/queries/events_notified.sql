@@ -0,0 +1,5 @@
+-- File: events_notified.sql
+-- Purpose: Record that the desktop notification of an event has been shown.
+UPDATE events
+SET notified_at = datetime('now', 'localtime')
+WHERE id = ?;
@@ -0,0 +1,5 @@
+-- File: events_shown.sql
+-- Purpose: Record that the banner of an event has been shown.
+UPDATE events
+SET shown_at = datetime('now', 'localtime')
+WHERE id = ?;
@@ -0,0 +1,8 @@
+-- File: events_unnotified.sql
+-- Purpose: Get the events of a character celebrated today whose desktop notification has not been shown yet.
+SELECT id, kind, years, coins, xp
+FROM events
+WHERE character_id = ?
+AND notified_at IS NULL
+AND DATE(created_at) = DATE('now', 'localtime')
+ORDER BY id;
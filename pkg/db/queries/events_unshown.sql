@@ -0,0 +1,8 @@
+-- File: events_unshown.sql
+-- Purpose: Get the events of a character celebrated today whose banner has not been shown yet.
+SELECT id, kind, years, coins, xp
+FROM events
+WHERE character_id = ?
+AND shown_at IS NULL
+AND DATE(created_at) = DATE('now', 'localtime')
+ORDER BY id;
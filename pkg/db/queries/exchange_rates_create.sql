@@ -0,0 +1,7 @@
+-- File: exchange_rates_create.sql
+-- Purpose: Import an exchange rate, the rate already imported for the same date and currencies is replaced.
+INSERT INTO exchange_rates (date, base, quote, rate)
+VALUES(?, ?, ?, ?)
+ON CONFLICT (date, base, quote) DO UPDATE
+SET rate = excluded.rate
+WHERE rate IS NOT excluded.rate;
@@ -0,0 +1,19 @@
+-- File: exchange_rates_find.sql
+-- Purpose: Get the exchange rate from a currency to another on a date, the rate of the latest date until it,
+-- from the rates of the pair or, inverted, from the rates of the inverse pair.
+SELECT rate
+FROM (
+    SELECT date, rate, 0 AS inverse
+    FROM exchange_rates
+    WHERE base = ?
+    AND quote = ?
+    AND date <= ?
+    UNION ALL
+    SELECT date, 1.0 / rate, 1 AS inverse
+    FROM exchange_rates
+    WHERE base = ?
+    AND quote = ?
+    AND date <= ?
+)
+ORDER BY date DESC, inverse
+LIMIT 1;
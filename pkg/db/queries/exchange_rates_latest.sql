@@ -0,0 +1,16 @@
+-- File: exchange_rates_latest.sql
+-- Purpose: Get the latest exchange rate of every pair of currencies, with the number of imported dates.
+SELECT r.date, r.base, r.quote, r.rate, (
+    SELECT COUNT(*)
+    FROM exchange_rates
+    WHERE base = r.base
+    AND quote = r.quote
+)
+FROM exchange_rates r
+WHERE r.date = (
+    SELECT MAX(date)
+    FROM exchange_rates
+    WHERE base = r.base
+    AND quote = r.quote
+)
+ORDER BY r.base, r.quote;
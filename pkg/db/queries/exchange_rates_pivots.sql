@@ -0,0 +1,11 @@
+-- File: exchange_rates_pivots.sql
+-- Purpose: Get the currencies with exchange rates to two currencies, used to convert them through a cross rate,
+-- e.g. USD to GBP through the rates of EUR to USD and EUR to GBP.
+SELECT CASE WHEN base = ? THEN quote ELSE base END
+FROM exchange_rates
+WHERE base = ? OR quote = ?
+INTERSECT
+SELECT CASE WHEN base = ? THEN quote ELSE base END
+FROM exchange_rates
+WHERE base = ? OR quote = ?
+ORDER BY 1;
@@ -0,0 +1,4 @@
+-- File: habits_create.sql
+-- Purpose: Create a new habit in the database.
+INSERT INTO habits (character_id, title, days, at)
+VALUES(?, ?, ?, ?);
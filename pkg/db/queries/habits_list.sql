@@ -0,0 +1,6 @@
+-- File: habits_list.sql
+-- Purpose: Get all the habits of a character.
+SELECT id, title, days, at
+FROM habits
+WHERE character_id = ?
+ORDER BY at, id;
@@ -0,0 +1,4 @@
+-- File: job_runs_create.sql
+-- Purpose: Record the start of a job run.
+INSERT INTO job_runs (job, trigger, status, error, started_at, ended_at)
+VALUES (?, ?, ?, ?, datetime('now', 'localtime'), CASE WHEN ? = 'running' THEN NULL ELSE datetime('now', 'localtime') END);
@@ -0,0 +1,5 @@
+-- File: job_runs_end.sql
+-- Purpose: Record the end of a job run.
+UPDATE job_runs
+SET status = ?, error = ?, ended_at = datetime('now', 'localtime')
+WHERE id = ?;
@@ -0,0 +1,5 @@
+-- File: job_runs_interrupt.sql
+-- Purpose: Mark the runs left running by a cron service that did not stop gracefully.
+UPDATE job_runs
+SET status = 'interrupted', ended_at = datetime('now', 'localtime')
+WHERE status = 'running';
@@ -0,0 +1,7 @@
+-- File: job_runs_list.sql
+-- Purpose: Get the last runs of a job, or of every job if the name is empty.
+SELECT id, job, trigger, status, error, started_at, ended_at
+FROM job_runs
+WHERE ? = '' OR job = ?
+ORDER BY started_at DESC, id DESC
+LIMIT ?;
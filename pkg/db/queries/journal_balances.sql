@@ -0,0 +1,9 @@
+-- File: journal_balances.sql
+-- Purpose: Sum the variations of the balance of the changes not yet committed, up to the last one, per currency.
+SELECT currency, SUM(balance)
+FROM journal
+WHERE committed = 0
+AND id <= ?
+GROUP BY currency
+HAVING SUM(balance) != 0
+ORDER BY currency;
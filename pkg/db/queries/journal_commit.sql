@@ -0,0 +1,6 @@
+-- File: journal_commit.sql
+-- Purpose: Mark the pending changes of the journal as committed, up to the last one summarised in the commit message.
+UPDATE journal
+SET committed = 1
+WHERE committed = 0
+AND id <= ?;
@@ -0,0 +1,4 @@
+-- File: journal_create.sql
+-- Purpose: Record a change made to the database in the change journal.
+INSERT INTO journal (action, xp, coins, balance, currency, hp)
+VALUES(?, ?, ?, ?, ?, ?);
@@ -0,0 +1,5 @@
+-- File: journal_first.sql
+-- Purpose: Get the id of the first change not yet committed to the repository, 0 if there are none.
+SELECT COALESCE(MIN(id), 0)
+FROM journal
+WHERE committed = 0;
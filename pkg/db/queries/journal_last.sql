@@ -0,0 +1,5 @@
+-- File: journal_last.sql
+-- Purpose: Get the id of the last change not yet committed to the repository, 0 if there are none.
+SELECT COALESCE(MAX(id), 0)
+FROM journal
+WHERE committed = 0;
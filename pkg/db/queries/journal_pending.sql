@@ -0,0 +1,13 @@
+-- File: journal_pending.sql
+-- Purpose: Summarise the changes not yet committed to the repository, up to the last one, grouped by action.
+SELECT
+action,
+COUNT(*),
+SUM(xp),
+SUM(coins),
+SUM(hp)
+FROM journal
+WHERE committed = 0
+AND id <= ?
+GROUP BY action
+ORDER BY MIN(id);
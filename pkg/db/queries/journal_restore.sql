@@ -0,0 +1,5 @@
+-- File: journal_restore.sql
+-- Purpose: Mark the changes acknowledged before a failed commit as pending again, from the first to the last one.
+UPDATE journal
+SET committed = 0
+WHERE id BETWEEN ? AND ?;
@@ -0,0 +1,19 @@
+-- File: migrate_001_profiles.sql
+-- Purpose: Scope the daily logins by character, for the profiles.
+-- the table is rebuilt, because sqlite can't add a column with a foreign key and a default to an existing table.
+-- the existing rows belong to the first character, the only one of the previous versions.
+-- the indexes are created again by tables.sql.
+
+CREATE TABLE daily_logins_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL DEFAULT 1 REFERENCES characters (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')),
+    UNIQUE (character_id, created_at)
+);
+
+INSERT INTO daily_logins_new (id, character_id, created_at)
+SELECT id, (SELECT MIN(id) FROM characters), created_at
+FROM daily_logins;
+
+DROP TABLE daily_logins;
+ALTER TABLE daily_logins_new RENAME TO daily_logins;
@@ -0,0 +1,107 @@
+-- File: migrate_002_money.sql
+-- Purpose: Store the amounts of money as integer minor units of their currency, and give a currency to every amount.
+-- the amounts of the previous versions are in the currency of money.currency in the config, the first parameter,
+-- they are multiplied by the number of minor units of a unit of that currency, the other parameters.
+-- the characters table can't be rebuilt, it is referenced by the other tables with a cascade on delete,
+-- so its columns are replaced; the other tables are rebuilt.
+-- the indexes and the triggers are created again by tables.sql.
+
+-- the tables added after the first release may not exist yet, they are created with their previous columns
+CREATE TABLE IF NOT EXISTS transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+    date TEXT NOT NULL,
+    amount REAL NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    payee TEXT NOT NULL DEFAULT '',
+    account TEXT NOT NULL DEFAULT '',
+    category TEXT,
+    source TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')),
+    UNIQUE (character_id, hash)
+);
+
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    amount REAL NOT NULL,
+    payee TEXT NOT NULL DEFAULT '',
+    account TEXT NOT NULL DEFAULT '',
+    category TEXT,
+    schedule TEXT NOT NULL,
+    starts_at TEXT NOT NULL,
+    ends_at TEXT,
+    next_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime'))
+);
+
+-- the triggers and the indexes refer to the replaced columns, the updates of the migration are not audited
+DROP TRIGGER IF EXISTS characters_audit_trigger;
+DROP TRIGGER IF EXISTS characters_updated_at_trigger;
+DROP INDEX IF EXISTS characters_budget_index;
+DROP INDEX IF EXISTS characters_balance_index;
+
+ALTER TABLE characters ADD COLUMN currency TEXT NOT NULL DEFAULT 'EUR';
+UPDATE characters SET currency = ?;
+
+ALTER TABLE characters RENAME COLUMN budget TO budget_real;
+ALTER TABLE characters RENAME COLUMN balance TO balance_real;
+ALTER TABLE characters ADD COLUMN budget INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN balance INTEGER NOT NULL DEFAULT 0;
+
+UPDATE characters
+SET budget = CAST(ROUND(budget_real * ?) AS INTEGER),
+    balance = CAST(ROUND(balance_real * ?) AS INTEGER);
+
+ALTER TABLE characters DROP COLUMN budget_real;
+ALTER TABLE characters DROP COLUMN balance_real;
+
+CREATE TABLE transactions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+    date TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    payee TEXT NOT NULL DEFAULT '',
+    account TEXT NOT NULL DEFAULT '',
+    category TEXT,
+    source TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')),
+    UNIQUE (character_id, hash)
+);
+
+INSERT INTO transactions_new (id, character_id, date, amount, currency, description, payee, account, category, source, hash, created_at)
+SELECT t.id, t.character_id, t.date, CAST(ROUND(t.amount * ?) AS INTEGER), c.currency, t.description, t.payee, t.account, t.category, t.source, t.hash, t.created_at
+FROM transactions t
+JOIN characters c ON c.id = t.character_id;
+
+DROP TABLE transactions;
+ALTER TABLE transactions_new RENAME TO transactions;
+
+CREATE TABLE recurring_transactions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    payee TEXT NOT NULL DEFAULT '',
+    account TEXT NOT NULL DEFAULT '',
+    category TEXT,
+    schedule TEXT NOT NULL,
+    starts_at TEXT NOT NULL,
+    ends_at TEXT,
+    next_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime'))
+);
+
+INSERT INTO recurring_transactions_new (id, character_id, description, amount, currency, payee, account, category, schedule, starts_at, ends_at, next_at, created_at)
+SELECT r.id, r.character_id, r.description, CAST(ROUND(r.amount * ?) AS INTEGER), c.currency, r.payee, r.account, r.category, r.schedule, r.starts_at, r.ends_at, r.next_at, r.created_at
+FROM recurring_transactions r
+JOIN characters c ON c.id = r.character_id;
+
+DROP TABLE recurring_transactions;
+ALTER TABLE recurring_transactions_new RENAME TO recurring_transactions;
@@ -0,0 +1,25 @@
+-- File: migrate_003_journal.sql
+-- Purpose: Store the variations of the balance in the change journal as integer minor units, with their currency.
+-- the journal does not know the character of its changes, so the balances of the previous versions are taken
+-- in the currency of money.currency in the config, the second parameter, and multiplied by the number of its minor
+-- units in a unit, the first parameter. only the changes not yet committed are summarised again.
+-- the indexes are created again by tables.sql.
+
+CREATE TABLE journal_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    xp INTEGER NOT NULL DEFAULT 0,
+    coins INTEGER NOT NULL DEFAULT 0,
+    balance INTEGER NOT NULL DEFAULT 0,
+    currency TEXT NOT NULL DEFAULT '',
+    hp INTEGER NOT NULL DEFAULT 0,
+    committed INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime'))
+);
+
+INSERT INTO journal_new (id, action, xp, coins, balance, currency, hp, committed, created_at)
+SELECT id, action, xp, coins, CAST(ROUND(balance * ?) AS INTEGER), ?, hp, committed, created_at
+FROM journal;
+
+DROP TABLE journal;
+ALTER TABLE journal_new RENAME TO journal;
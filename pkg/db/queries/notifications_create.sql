@@ -0,0 +1,4 @@
+-- File: notifications_create.sql
+-- Purpose: Record a reminder shown to the user, the reminders already shown are ignored.
+INSERT OR IGNORE INTO notifications (character_id, kind, ref_id, title, due_at, lead)
+VALUES(?, ?, ?, ?, ?, ?);
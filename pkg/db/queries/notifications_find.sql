@@ -0,0 +1,6 @@
+-- File: notifications_find.sql
+-- Purpose: Get the id of the notification of a reminder, 0 if it has not been shown yet.
+SELECT COALESCE(
+(SELECT id FROM notifications WHERE kind = ? AND ref_id = ? AND due_at = ? AND lead = ?),
+0
+);
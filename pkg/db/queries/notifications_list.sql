@@ -0,0 +1,7 @@
+-- File: notifications_list.sql
+-- Purpose: Get the last notifications shown to the user.
+SELECT id, kind, ref_id, title, due_at, lead, fired_at, snoozed_until
+FROM notifications
+WHERE character_id = ?
+ORDER BY fired_at DESC, id DESC
+LIMIT ?;
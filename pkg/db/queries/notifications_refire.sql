@@ -0,0 +1,6 @@
+-- File: notifications_refire.sql
+-- Purpose: Record that a snoozed notification has been shown again.
+UPDATE notifications
+SET fired_at = ?,
+    snoozed_until = NULL
+WHERE id = ?;
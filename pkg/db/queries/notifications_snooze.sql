@@ -0,0 +1,6 @@
+-- File: notifications_snooze.sql
+-- Purpose: Snooze a notification until the given time.
+UPDATE notifications
+SET snoozed_until = ?
+WHERE id = ?
+AND character_id = ?;
@@ -0,0 +1,8 @@
+-- File: notifications_snoozed_due.sql
+-- Purpose: Get the snoozed notifications that must be shown again.
+SELECT id, kind, ref_id, title, due_at, lead, fired_at, snoozed_until
+FROM notifications
+WHERE character_id = ?
+AND snoozed_until IS NOT NULL
+AND snoozed_until <= ?
+ORDER BY snoozed_until;
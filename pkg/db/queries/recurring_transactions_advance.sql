@@ -0,0 +1,5 @@
+-- File: recurring_transactions_advance.sql
+-- Purpose: Set the date of the next occurrence of a recurring transaction, after its due occurrences are recorded.
+UPDATE recurring_transactions
+SET next_at = ?
+WHERE id = ?;
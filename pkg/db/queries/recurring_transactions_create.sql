@@ -0,0 +1,4 @@
+-- File: recurring_transactions_create.sql
+-- Purpose: Create a recurring transaction of a character.
+INSERT INTO recurring_transactions (character_id, description, amount, currency, payee, account, category, schedule, starts_at, ends_at, next_at)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
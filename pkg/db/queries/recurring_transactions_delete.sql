@@ -0,0 +1,5 @@
+-- File: recurring_transactions_delete.sql
+-- Purpose: Delete a recurring transaction of a character, its recorded occurrences stay in the ledger.
+DELETE FROM recurring_transactions
+WHERE id = ?
+AND character_id = ?;
@@ -0,0 +1,8 @@
+-- File: recurring_transactions_due.sql
+-- Purpose: Get the recurring transactions of a character with an occurrence due until a time, before the end of their schedule.
+SELECT id, description, amount, currency, payee, account, COALESCE(category, ''), schedule, starts_at, ends_at, next_at
+FROM recurring_transactions
+WHERE character_id = ?
+AND next_at <= ?
+AND (ends_at IS NULL OR next_at <= ends_at)
+ORDER BY next_at, id;
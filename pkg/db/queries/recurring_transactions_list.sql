@@ -0,0 +1,6 @@
+-- File: recurring_transactions_list.sql
+-- Purpose: Get the recurring transactions of a character, the next one first.
+SELECT id, description, amount, currency, payee, account, COALESCE(category, ''), schedule, starts_at, ends_at, next_at
+FROM recurring_transactions
+WHERE character_id = ?
+ORDER BY next_at, id;
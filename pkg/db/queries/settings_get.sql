@@ -0,0 +1,6 @@
+-- File: settings_get.sql
+-- Purpose: Get a setting of the state database, an empty string if it is not set.
+SELECT COALESCE(
+(SELECT value FROM settings WHERE key = ?),
+''
+);
@@ -0,0 +1,5 @@
+-- File: settings_set.sql
+-- Purpose: Set a setting of the state database.
+INSERT INTO settings (key, value)
+VALUES(?, ?)
+ON CONFLICT (key) DO UPDATE SET value = excluded.value;
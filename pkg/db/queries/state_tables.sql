@@ -0,0 +1,44 @@
+-- File: state_tables.sql
+-- Purpose: Create the tables of the state database.
+-- the state database (state.db) keeps the runtime data of the cron service,
+-- it is not versioned, so writing to it does not create new commits.
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- job_runs table
+--
+
+-- the job_runs table is used to store the runs of the cron jobs
+-- a run is created with the "running" status when the job starts, and updated when it ends
+-- a run skipped because the previous one was still running is stored with the "skipped" status
+CREATE TABLE IF NOT EXISTS job_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job TEXT NOT NULL, -- the name of the job
+    trigger TEXT NOT NULL, -- what started the run, "schedule" or "manual"
+    status TEXT NOT NULL, -- "running", "ok", "failed", "skipped" or "interrupted"
+    error TEXT, -- the error returned by the job, null if the job succeeded
+    started_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- the start time of the run
+    ended_at TEXT -- the end time of the run, null while the job is running
+);
+
+-- job_runs table indexes
+CREATE INDEX IF NOT EXISTS job_runs_job_index ON job_runs (job, started_at);
+CREATE INDEX IF NOT EXISTS job_runs_status_index ON job_runs (status);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- settings table
+--
+
+-- the settings table is used to store the local choices of the user, that must not be shared with the other devices
+-- e.g. the active profile, the "profile" key holds the id of its character
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY, -- the name of the setting
+    value TEXT NOT NULL -- the value of the setting
+);
@@ -17,14 +17,19 @@
 
 -- the characters table is used to store the user character information
 -- the character represents the user in the application, making the experience more engaging
+-- every character is a profile: the other tables refer to the character their rows belong to,
+-- so the members of a household can share the same installation
+-- the rows of the versions before the profiles belong to the first character, the default of their character_id
+-- the amounts of money are integer minor units of their currency, e.g. cents, so their sums have no rounding drift
 CREATE TABLE IF NOT EXISTS characters (
     id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier for the character
     firstname TEXT NOT NULL, -- character's first name
     lastname TEXT NOT NULL, -- character's last name
-    nickname TEXT NOT NULL UNIQUE, -- character's nickname, must be unique
+    nickname TEXT NOT NULL UNIQUE, -- character's nickname, must be unique, it is the name of the profile
     birthday TEXT NOT NULL, -- character's birthday, used for birthday greetings
-    budget REAL NOT NULL DEFAULT 0.0, -- character's budget for financial management
-    balance REAL NOT NULL DEFAULT 0.0, -- character's balance for financial management
+    budget INTEGER NOT NULL DEFAULT 0, -- character's monthly budget, in minor units of its currency
+    balance INTEGER NOT NULL DEFAULT 0, -- character's balance, in minor units of its currency
+    currency TEXT NOT NULL DEFAULT 'EUR', -- character's base currency, of the budget, of the balance and of the reports
     coins INTEGER NOT NULL DEFAULT 0, -- character's coins, used for rewards
     xp INTEGER NOT NULL DEFAULT 0, -- character's experience points
     next_level_xp INTEGER NOT NULL DEFAULT 50, -- experience points needed for next level
@@ -58,6 +63,61 @@ CREATE INDEX IF NOT EXISTS characters_karma_index ON characters (karma);
 CREATE INDEX IF NOT EXISTS characters_created_at_index ON characters (created_at);
 CREATE INDEX IF NOT EXISTS characters_updated_at_index ON characters (updated_at);
 
+-- characters table triggers
+
+-- refresh the updated_at field when a character changes, unless the update sets it
+CREATE TRIGGER IF NOT EXISTS characters_updated_at_trigger
+AFTER UPDATE ON characters
+FOR EACH ROW
+WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE characters
+    SET updated_at = datetime('now', 'localtime')
+    WHERE id = NEW.id;
+END;
+
+-- record the changes of the values chosen by the user in the audit table, the stats are not recorded
+CREATE TRIGGER IF NOT EXISTS characters_audit_trigger
+AFTER UPDATE OF firstname, lastname, nickname, birthday, budget, currency ON characters
+FOR EACH ROW
+BEGIN
+    INSERT INTO audit (character_id, field, old_value, new_value)
+    SELECT NEW.id, 'firstname', OLD.firstname, NEW.firstname WHERE OLD.firstname IS NOT NEW.firstname;
+    INSERT INTO audit (character_id, field, old_value, new_value)
+    SELECT NEW.id, 'lastname', OLD.lastname, NEW.lastname WHERE OLD.lastname IS NOT NEW.lastname;
+    INSERT INTO audit (character_id, field, old_value, new_value)
+    SELECT NEW.id, 'nickname', OLD.nickname, NEW.nickname WHERE OLD.nickname IS NOT NEW.nickname;
+    INSERT INTO audit (character_id, field, old_value, new_value)
+    SELECT NEW.id, 'birthday', OLD.birthday, NEW.birthday WHERE OLD.birthday IS NOT NEW.birthday;
+    INSERT INTO audit (character_id, field, old_value, new_value)
+    SELECT NEW.id, 'budget', OLD.budget, NEW.budget WHERE OLD.budget IS NOT NEW.budget;
+    INSERT INTO audit (character_id, field, old_value, new_value)
+    SELECT NEW.id, 'currency', OLD.currency, NEW.currency WHERE OLD.currency IS NOT NEW.currency;
+END;
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- audit table
+--
+
+-- the audit table is used to store the changes of the character values chosen by the user, like the name or the budget
+-- the rows are inserted by the characters_audit_trigger, the values are stored as text
+CREATE TABLE IF NOT EXISTS audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE, -- the character that changed
+    field TEXT NOT NULL, -- the name of the changed column
+    old_value TEXT, -- the value before the change
+    new_value TEXT, -- the value after the change
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')) -- record creation timestamp
+);
+
+-- audit table indexes
+CREATE INDEX IF NOT EXISTS audit_id_index ON audit (id);
+CREATE INDEX IF NOT EXISTS audit_character_id_index ON audit (character_id);
+
 --------------------------------------------------------------------------------------
 --------------------------------------------------------------------------------------
 --------------------------------------------------------------------------------------
@@ -69,16 +129,327 @@ CREATE INDEX IF NOT EXISTS characters_updated_at_index ON characters (updated_at
 -- the daily_logins table is used to store the user dayly logins
 -- every day the user logs in the app, a dayly login is inserted in the daily_logins table
 -- every dayly login insert if the user restores the pp to the max values, and if the user has less than 75% of the max hp, the user restores the 25% of the max hp
--- the created_at field is unique for prevent duplicate dayly logins, or conflicts in the transactions
+-- the created_at field is unique for every character for prevent duplicate dayly logins, or conflicts in the transactions
 CREATE TABLE IF NOT EXISTS daily_logins (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
-    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')) UNIQUE
+    character_id INTEGER NOT NULL DEFAULT 1 REFERENCES characters (id) ON DELETE CASCADE, -- the character the login belongs to
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')),
+    UNIQUE (character_id, created_at)
 );
 
 -- daily_logins table indexes
 CREATE INDEX IF NOT EXISTS daily_logins_id_index ON daily_logins (id);
+CREATE INDEX IF NOT EXISTS daily_logins_character_id_index ON daily_logins (character_id);
 CREATE INDEX IF NOT EXISTS daily_logins_created_at_index ON daily_logins (created_at);
 
 --------------------------------------------------------------------------------------
 --------------------------------------------------------------------------------------
 --------------------------------------------------------------------------------------
+
+--
+-- journal table
+--
+
+-- the journal table is used to store the changes made to the database
+-- every time a mutating query runs, the db layer records the action and the variation of the character stats
+-- the pending changes are summarised in the message of the next commit, they are marked as committed once it succeeded
+CREATE TABLE IF NOT EXISTS journal (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL, -- the action that changed the database, the name of the query
+    xp INTEGER NOT NULL DEFAULT 0, -- variation of the character experience points
+    coins INTEGER NOT NULL DEFAULT 0, -- variation of the character coins
+    balance INTEGER NOT NULL DEFAULT 0, -- variation of the character balance, in minor units of its currency
+    currency TEXT NOT NULL DEFAULT '', -- currency of the character, ISO 4217 code
+    hp INTEGER NOT NULL DEFAULT 0, -- variation of the character health points
+    committed INTEGER NOT NULL DEFAULT 0, -- 1 if the change has been committed to the repository
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')) -- record creation timestamp
+);
+
+-- journal table indexes
+CREATE INDEX IF NOT EXISTS journal_id_index ON journal (id);
+CREATE INDEX IF NOT EXISTS journal_committed_index ON journal (committed);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- tasks table
+--
+
+-- the tasks table is used to store the user tasks
+-- a task can have a due date, the cron service reminds the user before it
+-- when the task is completed the character earns experience points
+CREATE TABLE IF NOT EXISTS tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL DEFAULT 1 REFERENCES characters (id) ON DELETE CASCADE, -- the character the task belongs to
+    title TEXT NOT NULL, -- task title
+    due_at TEXT, -- task due date, optional
+    completed_at TEXT, -- task completion timestamp, null until the task is completed
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    updated_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')) -- record update timestamp
+);
+
+-- tasks table indexes
+CREATE INDEX IF NOT EXISTS tasks_id_index ON tasks (id);
+CREATE INDEX IF NOT EXISTS tasks_character_id_index ON tasks (character_id);
+CREATE INDEX IF NOT EXISTS tasks_due_at_index ON tasks (due_at);
+CREATE INDEX IF NOT EXISTS tasks_completed_at_index ON tasks (completed_at);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- habits table
+--
+
+-- the habits table is used to store the user habits
+-- a habit is repeated on the days of its schedule at a specific time, the cron service reminds the user before it
+CREATE TABLE IF NOT EXISTS habits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL DEFAULT 1 REFERENCES characters (id) ON DELETE CASCADE, -- the character the habit belongs to
+    title TEXT NOT NULL, -- habit title
+    days TEXT NOT NULL DEFAULT 'daily', -- habit schedule, "daily" or a comma separated list of days of the week (e.g. "mon,wed,fri")
+    at TEXT NOT NULL DEFAULT '09:00', -- habit time of the day, in the format HH:MM
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    updated_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')) -- record update timestamp
+);
+
+-- habits table indexes
+CREATE INDEX IF NOT EXISTS habits_id_index ON habits (id);
+CREATE INDEX IF NOT EXISTS habits_character_id_index ON habits (character_id);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- notifications table
+--
+
+-- the notifications table is used to store the reminders shown to the user
+-- a notification is created for every lead time reached before a task due date or a habit occurrence,
+-- the unique constraint prevents the same reminder to be shown twice
+-- a snoozed notification is shown again when the snoozed_until time is reached
+CREATE TABLE IF NOT EXISTS notifications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL DEFAULT 1 REFERENCES characters (id) ON DELETE CASCADE, -- the character the notification belongs to
+    kind TEXT NOT NULL, -- the kind of the reminded item, "task" or "habit"
+    ref_id INTEGER NOT NULL, -- the id of the reminded item
+    title TEXT NOT NULL, -- the title of the reminded item
+    due_at TEXT NOT NULL, -- the due date of the reminded item
+    lead TEXT NOT NULL, -- the lead time of the reminder (e.g. "15m0s")
+    fired_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- the last time the notification was shown
+    snoozed_until TEXT, -- the time the notification will be shown again, null if not snoozed
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    UNIQUE (kind, ref_id, due_at, lead)
+);
+
+-- notifications table indexes
+CREATE INDEX IF NOT EXISTS notifications_id_index ON notifications (id);
+CREATE INDEX IF NOT EXISTS notifications_character_id_index ON notifications (character_id);
+CREATE INDEX IF NOT EXISTS notifications_snoozed_until_index ON notifications (snoozed_until);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- events table
+--
+
+-- the events table is used to store the yearly events of the characters, like the birthday or the anniversary of the creation
+-- an event is created the first time it is detected on its day, by the daily login or by the cron service,
+-- and the character receives its gift once, the unique constraint prevents the same event to be celebrated twice
+-- the event is shown as a banner in the console and as a desktop notification, once for every way
+CREATE TABLE IF NOT EXISTS events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE, -- the character of the event
+    kind TEXT NOT NULL, -- the kind of the event, "birthday" or "anniversary"
+    years INTEGER NOT NULL, -- the age of the character, or the years since its creation
+    coins INTEGER NOT NULL DEFAULT 0, -- the coins gifted to the character
+    xp INTEGER NOT NULL DEFAULT 0, -- the experience points gifted to the character
+    shown_at TEXT, -- the time the banner was shown in the console, null until it is shown
+    notified_at TEXT, -- the time the desktop notification was shown, null until it is shown
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    UNIQUE (character_id, kind, years)
+);
+
+-- events table indexes
+CREATE INDEX IF NOT EXISTS events_id_index ON events (id);
+CREATE INDEX IF NOT EXISTS events_character_id_index ON events (character_id);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- transactions table
+--
+
+-- the transactions table is the finance ledger of the characters, an income has a positive amount, an expense a negative one
+-- the transactions are imported from the bank statements, every transaction changes the balance of its character once,
+-- converted to the currency of the character with the exchange rate of its date:
+-- the hash identifies a transaction of a statement, so the transactions of overlapping statements are not imported twice
+-- the category is chosen by the category_rules when the transaction is imported, null if no rule matches
+CREATE TABLE IF NOT EXISTS transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE, -- the character of the transaction
+    date TEXT NOT NULL, -- the booking date of the transaction
+    amount INTEGER NOT NULL, -- the amount in minor units of its currency, positive for an income and negative for an expense
+    currency TEXT NOT NULL, -- the currency of the amount, e.g. EUR
+    description TEXT NOT NULL DEFAULT '', -- the description of the transaction, e.g. the remittance information
+    payee TEXT NOT NULL DEFAULT '', -- the counterparty of the transaction
+    account TEXT NOT NULL DEFAULT '', -- the bank account of the statement, e.g. the IBAN
+    category TEXT, -- the category of the transaction, null if not categorised
+    source TEXT NOT NULL, -- the format of the imported statement, "csv", "ofx" or "camt", or "recurring" for a recurring transaction
+    hash TEXT NOT NULL, -- the hash of the transaction, to detect the duplicates
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    UNIQUE (character_id, hash)
+);
+
+-- transactions table indexes
+CREATE INDEX IF NOT EXISTS transactions_id_index ON transactions (id);
+CREATE INDEX IF NOT EXISTS transactions_character_id_index ON transactions (character_id);
+CREATE INDEX IF NOT EXISTS transactions_date_index ON transactions (date);
+CREATE INDEX IF NOT EXISTS transactions_category_index ON transactions (category);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- category_rules table
+--
+
+-- the category_rules table is used to categorise the imported transactions
+-- a rule matches the transactions whose description or payee contains its pattern, ignoring the case,
+-- when more rules match a transaction the longest pattern wins, it is the most specific one
+CREATE TABLE IF NOT EXISTS category_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE, -- the character of the rule
+    pattern TEXT NOT NULL, -- the text searched in the description and in the payee of the transactions
+    category TEXT NOT NULL, -- the category given to the matching transactions
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    UNIQUE (character_id, pattern)
+);
+
+-- category_rules table indexes
+CREATE INDEX IF NOT EXISTS category_rules_id_index ON category_rules (id);
+CREATE INDEX IF NOT EXISTS category_rules_character_id_index ON category_rules (character_id);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- recurring_transactions table
+--
+
+-- the recurring_transactions table keeps the transactions repeated on a schedule, like the subscriptions and the salary
+-- the schedule is written in words, e.g. "every month on the 1st" or "mondays", and counted from the start date
+-- the cron service records the due occurrences in the transactions table, next_at is the date of the next occurrence
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE, -- the character of the recurring transaction
+    description TEXT NOT NULL, -- the description of the transactions, e.g. the name of the subscription
+    amount INTEGER NOT NULL, -- the amount of every occurrence in minor units of its currency, positive for an income and negative for an expense
+    currency TEXT NOT NULL, -- the currency of the amount, e.g. EUR
+    payee TEXT NOT NULL DEFAULT '', -- the counterparty of the transactions
+    account TEXT NOT NULL DEFAULT '', -- the bank account of the transactions
+    category TEXT, -- the category of the transactions, null to categorise them with the category rules
+    schedule TEXT NOT NULL, -- the schedule of the occurrences, e.g. "every month on the 1st"
+    starts_at TEXT NOT NULL, -- the date of the start of the schedule
+    ends_at TEXT, -- the date of the end of the schedule, null if it never ends
+    next_at TEXT NOT NULL, -- the date of the next occurrence not yet recorded
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')) -- record creation timestamp
+);
+
+-- recurring_transactions table indexes
+CREATE INDEX IF NOT EXISTS recurring_transactions_id_index ON recurring_transactions (id);
+CREATE INDEX IF NOT EXISTS recurring_transactions_character_id_index ON recurring_transactions (character_id);
+CREATE INDEX IF NOT EXISTS recurring_transactions_next_at_index ON recurring_transactions (next_at);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- exchange_rates table
+--
+
+-- the exchange_rates table keeps the exchange rates between the currencies, imported from a csv file, without network calls
+-- a rate is the number of units of the quote currency for one unit of the base currency, on a date
+-- an amount is converted with the rate of its date, or with the last rate before it
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date TEXT NOT NULL, -- the date of the rate
+    base TEXT NOT NULL, -- the currency converted, e.g. EUR
+    quote TEXT NOT NULL, -- the currency of the result, e.g. USD
+    rate REAL NOT NULL, -- the units of the quote currency for one unit of the base currency
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    UNIQUE (date, base, quote)
+);
+
+-- exchange_rates table indexes
+CREATE INDEX IF NOT EXISTS exchange_rates_id_index ON exchange_rates (id);
+CREATE INDEX IF NOT EXISTS exchange_rates_pair_index ON exchange_rates (base, quote, date);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- budgets table
+--
+
+-- the budgets table keeps the monthly budgets of the categories of the transactions, the envelopes of the character
+-- with the rollover, the amount not spent in a month is carried to the next one, the overspent amount is not carried
+-- carried is the amount carried to the first month, the budget changed in a month starts again from that month
+CREATE TABLE IF NOT EXISTS budgets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE, -- the character of the budget
+    category TEXT NOT NULL, -- the category of the transactions of the budget
+    amount INTEGER NOT NULL, -- the amount of every month in minor units of the currency of the character
+    rollover INTEGER NOT NULL DEFAULT 1, -- 1 if the amount not spent in a month is carried to the next one
+    carried INTEGER NOT NULL DEFAULT 0, -- the amount carried to the first month, in minor units of the currency of the character
+    starts_at TEXT NOT NULL, -- the first day of the first month of the budget
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    UNIQUE (character_id, category)
+);
+
+-- budgets table indexes
+CREATE INDEX IF NOT EXISTS budgets_id_index ON budgets (id);
+CREATE INDEX IF NOT EXISTS budgets_character_id_index ON budgets (character_id);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+
+--
+-- budget_penalties table
+--
+
+-- the budget_penalties table keeps the penalties of the overspent budgets, the game side of the finance ledger
+-- a budget overspent in a month damages the health points and lowers the karma of the character once,
+-- the unique constraint prevents the same overspending to be penalised twice
+CREATE TABLE IF NOT EXISTS budget_penalties (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL REFERENCES characters (id) ON DELETE CASCADE, -- the character of the penalty
+    category TEXT NOT NULL, -- the category of the overspent budget
+    month TEXT NOT NULL, -- the first day of the month of the overspending
+    overspent INTEGER NOT NULL, -- the amount spent over the budget in minor units of the currency of the character
+    hp INTEGER NOT NULL DEFAULT 0, -- the health points lost
+    karma INTEGER NOT NULL DEFAULT 0, -- the karma lost
+    created_at TEXT NOT NULL DEFAULT (datetime('now', 'localtime')), -- record creation timestamp
+    UNIQUE (character_id, category, month)
+);
+
+-- budget_penalties table indexes
+CREATE INDEX IF NOT EXISTS budget_penalties_id_index ON budget_penalties (id);
+CREATE INDEX IF NOT EXISTS budget_penalties_character_id_index ON budget_penalties (character_id);
+
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
+--------------------------------------------------------------------------------------
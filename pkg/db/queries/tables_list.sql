@@ -0,0 +1,7 @@
+-- File: tables_list.sql
+-- Purpose: List the user tables of the database.
+SELECT name
+FROM sqlite_master
+WHERE type = 'table'
+AND name NOT LIKE 'sqlite_%'
+ORDER BY name;
@@ -0,0 +1,16 @@
+-- File: tasks_complete.sql
+-- Purpose: Complete a task and reward the character with experience points.
+
+-- Complete the task
+UPDATE tasks
+SET completed_at = datetime('now', 'localtime'),
+    updated_at = datetime('now', 'localtime')
+WHERE id = ?
+AND character_id = ?
+AND completed_at IS NULL;
+
+-- Reward the character, only if the task has just been completed
+UPDATE characters
+SET xp = xp + ?
+WHERE id = ?
+AND changes() > 0;
@@ -0,0 +1,6 @@
+-- File: tasks_completed.sql
+-- Purpose: Check if a task of a character has been completed, no row if the task does not exist.
+SELECT completed_at IS NOT NULL
+FROM tasks
+WHERE id = ?
+AND character_id = ?;
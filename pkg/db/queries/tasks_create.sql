@@ -0,0 +1,4 @@
+-- File: tasks_create.sql
+-- Purpose: Create a new task in the database.
+INSERT INTO tasks (character_id, title, due_at)
+VALUES(?, ?, ?);
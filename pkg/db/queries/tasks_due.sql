@@ -0,0 +1,10 @@
+-- File: tasks_due.sql
+-- Purpose: Get the tasks not yet completed with a due date in the given range.
+SELECT id, title, due_at
+FROM tasks
+WHERE character_id = ?
+AND completed_at IS NULL
+AND due_at IS NOT NULL
+AND due_at > ?
+AND due_at <= ?
+ORDER BY due_at;
@@ -0,0 +1,7 @@
+-- File: tasks_list.sql
+-- Purpose: Get the tasks not yet completed, the ones with a due date first.
+SELECT id, title, due_at
+FROM tasks
+WHERE character_id = ?
+AND completed_at IS NULL
+ORDER BY due_at IS NULL, due_at, id;
@@ -0,0 +1,8 @@
+-- File: transactions_between.sql
+-- Purpose: Get the transactions of a character between two dates, the first included and the second excluded, for the reports.
+SELECT date, amount, currency, COALESCE(category, '')
+FROM transactions
+WHERE character_id = ?
+AND date >= ?
+AND date < ?
+ORDER BY date, id;
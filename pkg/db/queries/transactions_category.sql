@@ -0,0 +1,6 @@
+-- File: transactions_category.sql
+-- Purpose: Get the category of a transaction of a character by its hash, empty if not categorised.
+SELECT COALESCE(category, '')
+FROM transactions
+WHERE character_id = ?
+AND hash = ?;
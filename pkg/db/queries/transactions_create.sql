@@ -0,0 +1,11 @@
+-- File: transactions_create.sql
+-- Purpose: Import a transaction, unless it has already been imported, categorised by the longest matching rule.
+INSERT OR IGNORE INTO transactions (character_id, date, amount, currency, description, payee, account, source, hash, category)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, (
+    SELECT category
+    FROM category_rules
+    WHERE character_id = ?
+    AND instr(lower(? || ' ' || ?), lower(pattern)) > 0
+    ORDER BY length(pattern) DESC, id
+    LIMIT 1
+));
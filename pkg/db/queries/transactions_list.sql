@@ -0,0 +1,7 @@
+-- File: transactions_list.sql
+-- Purpose: Get the last transactions of a character, the most recent first.
+SELECT id, date, amount, currency, description, payee, account, category, source
+FROM transactions
+WHERE character_id = ?
+ORDER BY date DESC, id DESC
+LIMIT ?;
@@ -0,0 +1,12 @@
+-- File: transactions_recurring.sql
+-- Purpose: Record an occurrence of a recurring transaction, unless it has already been recorded,
+-- with the category of the recurring transaction or, without it, the category of the longest matching rule.
+INSERT OR IGNORE INTO transactions (character_id, date, amount, currency, description, payee, account, source, hash, category)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, (
+    SELECT category
+    FROM category_rules
+    WHERE character_id = ?
+    AND instr(lower(? || ' ' || ?), lower(pattern)) > 0
+    ORDER BY length(pattern) DESC, id
+    LIMIT 1
+)));
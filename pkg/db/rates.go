@@ -0,0 +1,252 @@
+// db package exchange rates functions
+package db
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"aio/pkg/utils/tm"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// querier interface is implemented by the database and by its transactions, the exchange rates are read from both.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// converter struct converts the amounts between the currencies with the exchange rates of the database,
+// the rates already found are kept, a report or an import converts many amounts of the same days.
+type converter struct {
+	q     querier
+	rates map[string]float64
+}
+
+// newConverter function returns a converter that reads the exchange rates with the given database or transaction.
+func newConverter(q querier) *converter {
+	return &converter{q: q, rates: map[string]float64{}}
+}
+
+// find function returns the rate from a currency to another on a date, direct or inverted, and false if there is none.
+func (c *converter) find(from, to, date string) (float64, bool, error) {
+	q, err := loadQuery("exchange_rates_find")
+	if err != nil {
+		return 0, false, err
+	}
+
+	var rate float64
+	err = c.q.QueryRow(q, from, to, date, to, from, date).Scan(&rate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		log.Err("failed to get the exchange rate", "from", from, "to", to)
+		return 0, false, wrap(err)
+	}
+
+	return rate, true, nil
+}
+
+// rate function returns the rate from a currency to another on a date, the rate of the latest date until it.
+// without a rate between the two currencies, it is the cross rate through a currency with rates to both.
+// it returns ErrNoRate if there is no rate.
+func (c *converter) rate(from, to string, date time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	day := tm.DBFormat(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local))
+	key := from + to + day
+	if rate, ok := c.rates[key]; ok {
+		return rate, nil
+	}
+
+	rate, ok, err := c.find(from, to, day)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		rate, ok, err = c.cross(from, to, day)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if !ok {
+		return 0, errors.Join(ErrNoRate, errors.New("no rate from "+from+" to "+to+" until "+date.Format("02 Jan 2006")))
+	}
+
+	c.rates[key] = rate
+	return rate, nil
+}
+
+// cross function returns the cross rate from a currency to another on a date, through the first currency with rates to both.
+func (c *converter) cross(from, to, day string) (float64, bool, error) {
+	q, err := loadQuery("exchange_rates_pivots")
+	if err != nil {
+		return 0, false, err
+	}
+
+	rows, err := c.q.Query(q, from, from, from, to, to, to)
+	if err != nil {
+		log.Err("failed to get the currencies of the cross rates")
+		return 0, false, wrap(err)
+	}
+
+	pivots := []string{}
+	for rows.Next() {
+		var pivot string
+		err = rows.Scan(&pivot)
+		if err != nil {
+			rows.Close()
+			return 0, false, err
+		}
+		pivots = append(pivots, pivot)
+	}
+
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, false, err
+	}
+
+	for _, pivot := range pivots {
+		first, ok, err := c.find(from, pivot, day)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		second, ok, err := c.find(pivot, to, day)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if ok {
+			return first * second, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// convert function converts an amount in minor units of a currency to another with the rate of a date.
+func (c *converter) convert(minor int64, from, to string, date time.Time) (int64, error) {
+	rate, err := c.rate(from, to, date)
+	if err != nil {
+		return 0, err
+	}
+
+	return money.Convert(minor, from, to, rate), nil
+}
+
+// Convert function converts an amount in minor units of a currency to another with the exchange rate of a date,
+// or of the latest date before it. it returns ErrNoRate if the rates of the currencies have not been imported.
+func Convert(minor int64, from, to string, date time.Time) (int64, error) {
+	db, err := getDb()
+	if err != nil {
+		return 0, err
+	}
+
+	defer db.Close()
+
+	return newConverter(db).convert(minor, from, to, date)
+}
+
+// RatesImport function imports exchange rates in a single transaction, a rate already imported for the same date
+// and currencies is replaced. it returns the number of rates added or changed.
+func RatesImport(rates []Rate) (int, error) {
+	id, err := current()
+	if err != nil {
+		return 0, err
+	}
+
+	db, err := getDb()
+	if err != nil {
+		return 0, err
+	}
+
+	defer db.Close()
+
+	create, err := loadQuery("exchange_rates_create")
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Err("failed to start transaction")
+		return 0, wrap(err)
+	}
+
+	defer tx.Rollback()
+
+	before, err := statsIn(tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, r := range rates {
+		res, err := tx.Exec(create, tm.DBFormat(r.Date), r.Base, r.Quote, r.Rate)
+		if err != nil {
+			log.Err("failed to import the exchange rate", "base", r.Base, "quote", r.Quote, "date", r.Date)
+			return 0, wrap(err)
+		}
+
+		n, _ := res.RowsAffected()
+		changed += int(n)
+	}
+
+	if changed == 0 {
+		return 0, nil
+	}
+
+	err = record(tx, "exchange_rates_import", id, before)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Err("failed to commit transaction")
+		return 0, wrap(err)
+	}
+
+	return changed, nil
+}
+
+// Rates function returns the latest exchange rate of every pair of currencies, with the number of imported dates.
+func Rates() ([]Rate, error) {
+	rows, err := gets("exchange_rates_latest")
+	if err != nil {
+		log.Err("failed to get the exchange rates")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	rates := []Rate{}
+	for rows.Next() {
+		var r Rate
+		var date string
+		err = rows.Scan(&date, &r.Base, &r.Quote, &r.Rate, &r.Dates)
+		if err != nil {
+			log.Err("failed to scan the exchange rate")
+			return nil, err
+		}
+
+		r.Date, err = tm.DBParse(date)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+
+	return rates, rows.Err()
+}
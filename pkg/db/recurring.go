@@ -0,0 +1,276 @@
+// db package recurring transactions functions
+package db
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/tm"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// SourceRecurring is the source of the transactions recorded from a recurring transaction.
+const SourceRecurring = "recurring"
+
+// RecurringCreate function creates a recurring transaction of the current profile.
+// the schedule is validated and its first occurrence is the first date of the schedule from the start date.
+// without a currency, the amount is in the currency of the character.
+func RecurringCreate(r Recurring) error {
+	sc, err := tm.ParseSchedule(r.Schedule)
+	if err != nil {
+		return err
+	}
+
+	if r.EndsAt != nil && r.EndsAt.Before(r.StartsAt) {
+		return errors.New("the end of the schedule is before its start")
+	}
+
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	if r.Currency == "" {
+		c, err := CharGet()
+		if err != nil {
+			return err
+		}
+		r.Currency = c.Currency
+	}
+
+	var category, endsAt any
+	if r.Category != "" {
+		category = r.Category
+	}
+
+	if r.EndsAt != nil {
+		endsAt = tm.DBFormat(*r.EndsAt)
+	}
+
+	next := sc.Next(r.StartsAt, r.StartsAt.AddDate(0, 0, -1))
+	err = do("recurring_transactions_create", id, r.Description, r.Amount, r.Currency, r.Payee, r.Account, category, r.Schedule, tm.DBFormat(r.StartsAt), endsAt, tm.DBFormat(next))
+	if err != nil {
+		log.Err("failed to create the recurring transaction", "description", r.Description)
+		return err
+	}
+
+	return nil
+}
+
+// scanRecurrings function scans the rows of a recurring transactions query.
+func scanRecurrings(rows *sql.Rows) ([]Recurring, error) {
+	defer rows.Close()
+
+	recurrings := []Recurring{}
+	for rows.Next() {
+		var r Recurring
+		var startsAt, nextAt string
+		var endsAt sql.NullString
+		err := rows.Scan(&r.ID, &r.Description, &r.Amount, &r.Currency, &r.Payee, &r.Account, &r.Category, &r.Schedule, &startsAt, &endsAt, &nextAt)
+		if err != nil {
+			log.Err("failed to scan the recurring transaction")
+			return nil, err
+		}
+
+		r.StartsAt, err = tm.DBParse(startsAt)
+		if err != nil {
+			return nil, err
+		}
+
+		r.NextAt, err = tm.DBParse(nextAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if endsAt.Valid {
+			t, err := tm.DBParse(endsAt.String)
+			if err != nil {
+				return nil, err
+			}
+			r.EndsAt = &t
+		}
+
+		recurrings = append(recurrings, r)
+	}
+
+	return recurrings, rows.Err()
+}
+
+// Recurrings function returns the recurring transactions of the current profile, the next one first.
+func Recurrings() ([]Recurring, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("recurring_transactions_list", id)
+	if err != nil {
+		log.Err("failed to get the recurring transactions")
+		return nil, err
+	}
+
+	return scanRecurrings(rows)
+}
+
+// Ended function returns true if the schedule of the recurring transaction has no more occurrences.
+func (r Recurring) Ended() bool {
+	return r.EndsAt != nil && r.NextAt.After(*r.EndsAt)
+}
+
+// RecurringDelete function deletes a recurring transaction of the current profile, its recorded occurrences stay in the ledger.
+func RecurringDelete(recurring int) error {
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("recurring_transactions_delete", recurring, id)
+	if err != nil {
+		log.Err("failed to delete the recurring transaction", "id", recurring)
+		return err
+	}
+
+	return nil
+}
+
+// RecurringRecord function records in the ledger the occurrences of the recurring transactions of the current profile
+// due until now, in a single transaction, and adds their amounts to the balance of the character,
+// converted to its currency with the exchange rate of their date.
+// the occurrences missed while the cron service was not running are recorded too, an occurrence is never recorded twice.
+// it returns the recorded transactions, with their category.
+func RecurringRecord(now time.Time) ([]Transaction, error) {
+	recorded := []Transaction{}
+	id, err := current()
+	if err != nil {
+		return recorded, err
+	}
+
+	db, err := getDb()
+	if err != nil {
+		return recorded, err
+	}
+
+	defer db.Close()
+
+	queries := map[string]string{}
+	for _, name := range []string{"recurring_transactions_due", "recurring_transactions_advance", "transactions_recurring", "transactions_category", "characters_balance"} {
+		queries[name], err = loadQuery(name)
+		if err != nil {
+			return recorded, err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Err("failed to start transaction")
+		return recorded, wrap(err)
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.Query(queries["recurring_transactions_due"], id, tm.DBFormat(now))
+	if err != nil {
+		log.Err("failed to get the due recurring transactions")
+		return recorded, wrap(err)
+	}
+
+	due, err := scanRecurrings(rows)
+	if err != nil {
+		return recorded, err
+	}
+
+	if len(due) == 0 {
+		return recorded, nil
+	}
+
+	before, err := statsIn(tx, id)
+	if err != nil {
+		return recorded, err
+	}
+
+	currency, err := currencyIn(tx, id)
+	if err != nil {
+		return recorded, err
+	}
+
+	conv := newConverter(tx)
+	total := int64(0)
+	for _, r := range due {
+		sc, err := tm.ParseSchedule(r.Schedule)
+		if err != nil {
+			log.Warn("skipping recurring transaction with an invalid schedule", "description", r.Description, "err", err)
+			continue
+		}
+
+		var category any
+		if r.Category != "" {
+			category = r.Category
+		}
+
+		for !r.NextAt.After(now) && !r.Ended() {
+			t := Transaction{
+				Date:        r.NextAt,
+				Amount:      r.Amount,
+				Currency:    r.Currency,
+				Description: r.Description,
+				Payee:       r.Payee,
+				Account:     r.Account,
+				Ref:         SourceRecurring + " " + strconv.Itoa(r.ID) + " " + r.NextAt.Format("2006-01-02"),
+				Source:      SourceRecurring,
+			}
+
+			hash := transactionHash(t, 0)
+			res, err := tx.Exec(queries["transactions_recurring"], id, tm.DBFormat(t.Date), t.Amount, t.Currency, t.Description, t.Payee, t.Account, t.Source, hash, category, id, t.Description, t.Payee)
+			if err != nil {
+				log.Err("failed to record the recurring transaction", "description", r.Description, "date", t.Date)
+				return recorded, wrap(err)
+			}
+
+			if n, _ := res.RowsAffected(); n > 0 {
+				err = tx.QueryRow(queries["transactions_category"], id, hash).Scan(&t.Category)
+				if err != nil {
+					log.Err("failed to get the category of the transaction")
+					return recorded, wrap(err)
+				}
+
+				amount, err := conv.convert(t.Amount, t.Currency, currency, t.Date)
+				if err != nil {
+					return recorded, errors.Join(errors.New("failed to convert the recurring transaction "+r.Description+" to "+currency), err)
+				}
+
+				recorded = append(recorded, t)
+				total += amount
+			}
+
+			r.NextAt = sc.Next(r.StartsAt, r.NextAt)
+		}
+
+		_, err = tx.Exec(queries["recurring_transactions_advance"], tm.DBFormat(r.NextAt), r.ID)
+		if err != nil {
+			log.Err("failed to advance the recurring transaction", "description", r.Description)
+			return recorded, wrap(err)
+		}
+	}
+
+	if len(recorded) > 0 {
+		_, err = tx.Exec(queries["characters_balance"], total, id)
+		if err != nil {
+			log.Err("failed to update the balance")
+			return recorded, wrap(err)
+		}
+
+		err = record(tx, "recurring_transactions_record", id, before)
+		if err != nil {
+			return recorded, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Err("failed to commit transaction")
+		return recorded, wrap(err)
+	}
+
+	return recorded, nil
+}
@@ -0,0 +1,298 @@
+// db package snapshot functions
+package db
+
+import (
+	"aio/pkg/log"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// openSnapshot function opens a database snapshot in read only mode.
+// it is used to read a previous version of the database without changing it.
+func openSnapshot(file string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+file+"?mode=ro")
+	if err != nil {
+		log.Err("failed to open snapshot", "file", file)
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// versionOf function returns the version of the schema of a database, the number of migrations applied.
+func versionOf(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		log.Err("failed to get the schema version")
+		return 0, wrap(err)
+	}
+
+	return version, nil
+}
+
+// tablesIn function returns the names of the user tables of a database.
+func tablesIn(db *sql.DB) ([]string, error) {
+	q, err := loadQuery("tables_list")
+	if err != nil {
+		log.Err("failed to load query")
+		return nil, err
+	}
+
+	rows, err := db.Query(q)
+	if err != nil {
+		log.Err("failed to list tables")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			log.Err("failed to scan table name")
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+
+	return tables, rows.Err()
+}
+
+// countsIn function returns the number of rows of every user table of a database.
+func countsIn(db *sql.DB) (map[string]int, error) {
+	tables, err := tablesIn(db)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, t := range tables {
+		var n int
+		// table names come from sqlite_master, so they are safe to be quoted here
+		err := db.QueryRow(`SELECT COUNT(*) FROM "` + t + `"`).Scan(&n)
+		if err != nil {
+			log.Err("failed to count table rows", "table", t)
+			return nil, err
+		}
+		counts[t] = n
+	}
+
+	return counts, nil
+}
+
+// CharGetAt function returns the character of the current profile stored in a database snapshot.
+// It is used to preview the character stats of a previous version of the database.
+func CharGetAt(file string) (*Character, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openSnapshot(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	version, err := versionOf(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// the snapshots before the currencies have the amounts as decimals, they are read as minor units
+	args := []any{id}
+	name := "characters_get"
+	if version < moneyVersion {
+		currency, units := legacyMoney()
+		name, args = "characters_get_v1", []any{units, units, currency, id}
+	}
+
+	q, err := loadQuery(name)
+	if err != nil {
+		log.Err("failed to load query")
+		return nil, err
+	}
+
+	return scanChar(db.QueryRow(q, args...))
+}
+
+// Counts function returns the number of rows of every table of the current database.
+func Counts() (map[string]int, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+	return countsIn(db)
+}
+
+// CountsAt function returns the number of rows of every table of a database snapshot.
+func CountsAt(file string) (map[string]int, error) {
+	db, err := openSnapshot(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+	return countsIn(db)
+}
+
+// RestoreTable function replaces the content of a table with the content of the same table in a snapshot.
+// only the columns that exist in both versions of the table are restored,
+// the other columns keep their default values.
+// the replacement is done in a transaction, so the table is never left half restored.
+// the rows of the other tables referring to the table are kept, the table is not restored if they refer to a missing row.
+// the tables with amounts of money can't be restored from a snapshot before their amounts became minor units.
+func RestoreTable(file, table string) error {
+	snapshot, err := openSnapshot(file)
+	if err != nil {
+		return err
+	}
+
+	version, err := versionOf(snapshot)
+	snapshot.Close()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := moneyColumns[table]; ok && version < moneySince(table) {
+		return errors.New("table " + table + " can't be restored from this version, its amounts are not in minor units")
+	}
+
+	db, err := getDb()
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	// the attached database is bound to the connection, so every statement must use the same one
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		log.Err("failed to get a database connection")
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "ATTACH DATABASE ? AS snapshot", "file:"+file+"?mode=ro")
+	if err != nil {
+		log.Err("failed to attach snapshot", "file", file)
+		return err
+	}
+
+	defer conn.ExecContext(ctx, "DETACH DATABASE snapshot")
+
+	// the rows of the other tables referring to the table are kept, e.g. the tasks of the restored characters:
+	// without the foreign keys the delete does not cascade to them, they are checked after the copy.
+	// the pragma has no effect inside a transaction, so it is set on the connection before it
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	if err != nil {
+		log.Err("failed to disable the foreign keys")
+		return wrap(err)
+	}
+
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	// the table must exist in both databases
+	current, err := columnsOf(ctx, conn, "main", table)
+	if err != nil {
+		return err
+	}
+
+	previous, err := columnsOf(ctx, conn, "snapshot", table)
+	if err != nil {
+		return err
+	}
+
+	if len(current) == 0 || len(previous) == 0 {
+		return errors.New("table " + table + " does not exist in both versions of the database")
+	}
+
+	columns := []string{}
+	for _, c := range current {
+		if slices.Contains(previous, c) {
+			columns = append(columns, `"`+c+`"`)
+		}
+	}
+
+	cols := strings.Join(columns, ", ")
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		log.Err("failed to start transaction")
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM main."` + table + `"`)
+	if err != nil {
+		tx.Rollback()
+		log.Err("failed to clear table", "table", table)
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO main."` + table + `" (` + cols + `) SELECT ` + cols + ` FROM snapshot."` + table + `"`)
+	if err != nil {
+		tx.Rollback()
+		log.Err("failed to copy table", "table", table)
+		return err
+	}
+
+	// the rows referring to a row missing after the copy, e.g. the tasks of a character not in the snapshot
+	var child, parent string
+	var rowid sql.NullInt64
+	var fk int
+	err = tx.QueryRow("PRAGMA main.foreign_key_check").Scan(&child, &rowid, &parent, &fk)
+	if err == nil {
+		tx.Rollback()
+		return errors.New("table " + child + ": row " + strconv.FormatInt(rowid.Int64, 10) + " refers to a row of " + parent + " missing in this version, the table is not restored")
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		log.Err("failed to check the foreign keys")
+		return wrap(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		log.Err("failed to commit transaction")
+		return err
+	}
+
+	log.Info("table restored successfully!", "table", table)
+	return nil
+}
+
+// columnsOf function returns the column names of a table in the given schema (main or snapshot).
+// it returns an empty slice if the table does not exist.
+func columnsOf(ctx context.Context, conn *sql.Conn, schema, table string) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT name FROM pragma_table_info(?, ?)", table, schema)
+	if err != nil {
+		log.Err("failed to get table columns", "schema", schema, "table", table)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			log.Err("failed to scan column name")
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rows.Err()
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"aio/pkg/utils/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// snapshot function copies the current database to a file, like a version of the database in the repository.
+func snapshot(t *testing.T) string {
+	t.Helper()
+	file, err := fs.DBfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "snapshot.db")
+	err = os.WriteFile(dst, content, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
+func TestRestoreTable(t *testing.T) {
+	setup(t)
+
+	err := TaskCreate("Pay the rent", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := snapshot(t)
+	for _, title := range []string{"Call the bank", "Water the plants"} {
+		err = TaskCreate(title, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = RestoreTable(file, "tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := Tasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tasks) != 1 || tasks[0].Title != "Pay the rent" {
+		t.Errorf("tasks after the restore %+v, want only Pay the rent", tasks)
+	}
+
+	// a table that does not exist is not restored
+	err = RestoreTable(file, "quests")
+	if err == nil {
+		t.Error("RestoreTable of a missing table succeeded, want an error")
+	}
+}
+
+func TestRestoreTableReferences(t *testing.T) {
+	setup(t)
+
+	err := TaskCreate("Pay the rent", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := snapshot(t)
+	_, err = CharEdit(Seed{FirstName: "Janet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the tasks of the restored character are kept, the delete of the characters does not cascade
+	err = RestoreTable(file, "characters")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := CharGet()
+	if err != nil || c.FirstName != "Jane" {
+		t.Fatalf("CharGet() = %+v, %v, want Jane", c, err)
+	}
+
+	tasks, err := Tasks()
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("tasks after the restore %+v, %v, want Pay the rent", tasks, err)
+	}
+
+	// a profile created after the version has a task, its character can't be removed by the restore
+	_, err = ProfileCreate(Seed{FirstName: "John", LastName: "Smith", NickName: "Jo", BirthDate: "03 Mar 1998", Currency: "EUR", Budget: "1500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetProfile("Jo")
+	defer SetProfile("")
+	err = TaskCreate("Call the bank", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RestoreTable(file, "characters")
+	if err == nil {
+		t.Fatal("RestoreTable of the characters without Jo succeeded, want an error")
+	}
+
+	profiles, err := Profiles()
+	if err != nil || len(profiles) != 2 {
+		t.Errorf("profiles after the refused restore %+v, %v, want Jay and Jo", profiles, err)
+	}
+}
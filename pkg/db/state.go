@@ -0,0 +1,210 @@
+// db package state database functions
+package db
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/fs"
+	"aio/pkg/utils/tm"
+	"database/sql"
+	"time"
+)
+
+// job run statuses
+const (
+	RunRunning     = "running"     // the job is running
+	RunOK          = "ok"          // the job completed successfully
+	RunFailed      = "failed"      // the job returned an error
+	RunSkipped     = "skipped"     // the job was not started because the previous run was still running
+	RunInterrupted = "interrupted" // the cron service exited while the job was running
+)
+
+// getStateDb function returns a pointer to the state database, creating its tables if they do not exist.
+// the state database keeps the runtime data of the cron service, like the job runs.
+// it is a separate file from data.db, ignored by git, so the cron service does not create a commit at every run.
+func getStateDb() (*sql.DB, error) {
+	file, err := fs.StatePath("state.db")
+	if err != nil {
+		log.Err("failed to get state database file path")
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", file+"?_busy_timeout=5000")
+	if err != nil {
+		log.Err("failed to open state database")
+		return nil, err
+	}
+
+	q, err := loadQuery("state_tables")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	_, err = db.Exec(q)
+	if err != nil {
+		db.Close()
+		log.Err("failed to create state database tables")
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// stateExec function executes a query on the state database.
+func stateExec(query string, args ...any) (sql.Result, error) {
+	db, err := getStateDb()
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	q, err := loadQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.Exec(q, args...)
+	if err != nil {
+		log.Err("failed to execute query", "query", query)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// settingGet function returns a setting of the state database, an empty string if it is not set.
+func settingGet(key string) (string, error) {
+	db, err := getStateDb()
+	if err != nil {
+		return "", err
+	}
+
+	defer db.Close()
+
+	q, err := loadQuery("settings_get")
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	err = db.QueryRow(q, key).Scan(&value)
+	if err != nil {
+		log.Err("failed to get the setting", "key", key)
+		return "", err
+	}
+
+	return value, nil
+}
+
+// settingSet function sets a setting of the state database.
+func settingSet(key, value string) error {
+	_, err := stateExec("settings_set", key, value)
+	if err != nil {
+		log.Err("failed to set the setting", "key", key)
+		return err
+	}
+
+	return nil
+}
+
+// JobRunCreate function records a run of a job with the given status and returns its id.
+// a run created with the running status is completed with JobRunEnd.
+func JobRunCreate(job, trigger, status string) (int64, error) {
+	res, err := stateExec("job_runs_create", job, trigger, status, nil, status)
+	if err != nil {
+		log.Err("failed to record the job run", "job", job)
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// JobRunEnd function records the end of a job run, failed if err is not nil.
+func JobRunEnd(id int64, err error) error {
+	status, msg := RunOK, sql.NullString{}
+	if err != nil {
+		status, msg = RunFailed, sql.NullString{String: err.Error(), Valid: true}
+	}
+
+	_, err = stateExec("job_runs_end", status, msg, id)
+	if err != nil {
+		log.Err("failed to record the end of the job run", "id", id)
+		return err
+	}
+
+	return nil
+}
+
+// JobRunsInterrupt function marks as interrupted the runs left running by a previous cron service.
+// it is called when the cron service starts.
+func JobRunsInterrupt() error {
+	_, err := stateExec("job_runs_interrupt")
+	if err != nil {
+		log.Err("failed to mark the interrupted job runs")
+		return err
+	}
+
+	return nil
+}
+
+// JobRuns function returns the last runs of a job, or of every job if job is empty.
+func JobRuns(job string, limit int) ([]JobRun, error) {
+	db, err := getStateDb()
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	q, err := loadQuery("job_runs_list")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(q, job, job, limit)
+	if err != nil {
+		log.Err("failed to get the job runs")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	runs := []JobRun{}
+	for rows.Next() {
+		var r JobRun
+		var started string
+		var msg, ended sql.NullString
+		err = rows.Scan(&r.ID, &r.Job, &r.Trigger, &r.Status, &msg, &started, &ended)
+		if err != nil {
+			log.Err("failed to scan the job run")
+			return nil, err
+		}
+
+		r.Error = msg.String
+		r.StartedAt, err = tm.DBParse(started)
+		if err != nil {
+			return nil, err
+		}
+
+		if ended.Valid {
+			t, err := tm.DBParse(ended.String)
+			if err != nil {
+				return nil, err
+			}
+			r.EndedAt = &t
+		}
+
+		runs = append(runs, r)
+	}
+
+	return runs, rows.Err()
+}
+
+// Duration function returns the duration of the run, up to now if the run has not ended.
+func (r JobRun) Duration() time.Duration {
+	if r.EndedAt == nil {
+		return time.Since(r.StartedAt).Round(time.Second)
+	}
+	return r.EndedAt.Sub(r.StartedAt)
+}
@@ -0,0 +1,226 @@
+// db package tasks and habits functions
+package db
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/log"
+	"aio/pkg/utils/tm"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// TaskCreate function creates a new task, due can be nil if the task has no due date.
+func TaskCreate(title string, due *time.Time) error {
+	var dueAt any
+	if due != nil {
+		dueAt = tm.DBFormat(*due)
+	}
+
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("tasks_create", id, title, dueAt)
+	if err != nil {
+		log.Err("failed to create the task")
+		return err
+	}
+
+	return nil
+}
+
+// TaskComplete function completes a task of the current profile and rewards its character with the experience points
+// of rewards.task_xp in the config file.
+// it returns ErrNoTask if the task does not exist and ErrTaskCompleted if it has already been completed.
+func TaskComplete(id int) error {
+	char, err := current()
+	if err != nil {
+		return err
+	}
+
+	n, err := doCount("tasks_complete", id, char, config.Get().Rewards.TaskXP, char)
+	if err != nil {
+		log.Err("failed to complete the task", "id", id)
+		return err
+	}
+
+	// the character is rewarded only when the task has just been completed
+	if n > 0 {
+		return nil
+	}
+
+	row, err := get("tasks_completed", id, char)
+	if err != nil {
+		return err
+	}
+
+	var completed bool
+	err = row.Scan(&completed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoTask
+	}
+
+	if err != nil {
+		log.Err("failed to check the task", "id", id)
+		return wrap(err)
+	}
+
+	if completed {
+		return ErrTaskCompleted
+	}
+
+	return nil
+}
+
+// scanTasks function scans the rows of a tasks query.
+func scanTasks(rows *sql.Rows) ([]Task, error) {
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var t Task
+		var due sql.NullString
+		err := rows.Scan(&t.ID, &t.Title, &due)
+		if err != nil {
+			log.Err("failed to scan the task")
+			return nil, err
+		}
+
+		if due.Valid {
+			d, err := tm.DBParse(due.String)
+			if err != nil {
+				log.Err("failed to parse the task due date")
+				return nil, err
+			}
+			t.DueAt = &d
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
+
+// Tasks function returns the tasks of the current profile not yet completed.
+func Tasks() ([]Task, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("tasks_list", id)
+	if err != nil {
+		log.Err("failed to get the tasks")
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+// TasksDue function returns the tasks of the current profile not yet completed with a due date in the range (from, to].
+func TasksDue(from, to time.Time) ([]Task, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("tasks_due", id, tm.DBFormat(from), tm.DBFormat(to))
+	if err != nil {
+		log.Err("failed to get the due tasks")
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+// HabitCreate function creates a new habit.
+// days is "daily" or a comma separated list of days of the week, at is the time of the day (HH:MM).
+func HabitCreate(title, days, at string) error {
+	days = strings.ToLower(strings.ReplaceAll(days, " ", ""))
+	if days != "daily" {
+		for _, d := range strings.Split(days, ",") {
+			if _, err := tm.ParseWeekday(d); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := time.Parse("15:04", at); err != nil {
+		return errors.New("invalid time of the day, use the format HH:MM")
+	}
+
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("habits_create", id, title, days, at)
+	if err != nil {
+		log.Err("failed to create the habit")
+		return err
+	}
+
+	return nil
+}
+
+// Habits function returns all the habits of the current profile.
+func Habits() ([]Habit, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("habits_list", id)
+	if err != nil {
+		log.Err("failed to get the habits")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	habits := []Habit{}
+	for rows.Next() {
+		var h Habit
+		err := rows.Scan(&h.ID, &h.Title, &h.Days, &h.At)
+		if err != nil {
+			log.Err("failed to scan the habit")
+			return nil, err
+		}
+		habits = append(habits, h)
+	}
+
+	return habits, rows.Err()
+}
+
+// Next function returns the next occurrence of the habit after t.
+func (h Habit) Next(t time.Time) (time.Time, error) {
+	at, err := time.Parse("15:04", h.At)
+	if err != nil {
+		return time.Time{}, errors.New("invalid habit time: " + h.At)
+	}
+
+	days := map[time.Weekday]bool{}
+	if h.Days != "daily" {
+		for _, d := range strings.Split(h.Days, ",") {
+			wd, err := tm.ParseWeekday(d)
+			if err != nil {
+				return time.Time{}, err
+			}
+			days[wd] = true
+		}
+	}
+
+	// check the next 8 days, so the same day of the next week is included
+	for i := 0; i <= 7; i++ {
+		d := t.AddDate(0, 0, i)
+		next := time.Date(d.Year(), d.Month(), d.Day(), at.Hour(), at.Minute(), 0, 0, t.Location())
+		if next.After(t) && (len(days) == 0 || days[next.Weekday()]) {
+			return next, nil
+		}
+	}
+
+	return time.Time{}, errors.New("no occurrence found for the habit " + h.Title)
+}
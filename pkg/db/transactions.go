@@ -0,0 +1,398 @@
+// db package finance ledger functions
+package db
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"aio/pkg/utils/tm"
+	"cmp"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransactionsResult struct represents the result of the import of the transactions of a bank statement.
+type TransactionsResult struct {
+	Imported   []Transaction // the new transactions, with their category
+	Duplicates int           // the transactions already imported
+	Total      int64         // the sum of the amounts of the new transactions in the base currency, added to the balance
+	Currency   string        // the base currency of the character
+}
+
+// currencyIn function returns the base currency of the character with the given id.
+func currencyIn(q querier, id int64) (string, error) {
+	query, err := loadQuery("characters_currency")
+	if err != nil {
+		return "", err
+	}
+
+	var currency string
+	err = q.QueryRow(query, id).Scan(&currency)
+	if err != nil {
+		log.Err("failed to get the currency of the character")
+		return "", wrapChar(err)
+	}
+
+	return currency, nil
+}
+
+// transactionHash function returns the hash of a transaction, used to detect the transactions already imported.
+// a transaction with the id given by the bank is identified by it and by its account,
+// the other ones by their values, and both by their occurrence in the statement: two equal payments of the same day
+// are both imported, and so are two transactions with the same id in a statement, e.g. the id of a batch of payments.
+// the first occurrence of an id has the hash of the id alone, the one of the statements imported before.
+func transactionHash(t Transaction, occurrence int) string {
+	key := t.Account + "|ref|" + t.Ref
+	if t.Ref != "" && occurrence > 0 {
+		key += "|" + strconv.Itoa(occurrence)
+	}
+
+	if t.Ref == "" {
+		key = strings.Join([]string{
+			t.Account,
+			t.Date.Format("2006-01-02"),
+			money.Format(t.Amount, t.Currency),
+			strings.ToLower(strings.Join(strings.Fields(t.Description), " ")),
+			strings.ToLower(strings.Join(strings.Fields(t.Payee), " ")),
+			strconv.Itoa(occurrence),
+		}, "|")
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// TransactionsImport function imports the transactions of a bank statement for the character of the current profile,
+// in a single transaction: the transactions already imported are skipped, the new ones are categorised by the category rules
+// and their amounts are added to the balance of the character, converted to its currency with the exchange rate of their date.
+// the transactions without a currency are in the currency of the character.
+// with dryRun the import is rolled back, the result tells what would be imported.
+func TransactionsImport(transactions []Transaction, dryRun bool) (TransactionsResult, error) {
+	result := TransactionsResult{Imported: []Transaction{}}
+	id, err := current()
+	if err != nil {
+		return result, err
+	}
+
+	db, err := getDb()
+	if err != nil {
+		return result, err
+	}
+
+	defer db.Close()
+
+	create, err := loadQuery("transactions_create")
+	if err != nil {
+		return result, err
+	}
+
+	category, err := loadQuery("transactions_category")
+	if err != nil {
+		return result, err
+	}
+
+	balance, err := loadQuery("characters_balance")
+	if err != nil {
+		return result, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Err("failed to start transaction")
+		return result, wrap(err)
+	}
+
+	defer tx.Rollback()
+
+	before, err := statsIn(tx, id)
+	if err != nil {
+		return result, err
+	}
+
+	result.Currency, err = currencyIn(tx, id)
+	if err != nil {
+		return result, err
+	}
+
+	conv := newConverter(tx)
+	occurrences := map[string]int{}
+	for _, t := range transactions {
+		if t.Currency == "" {
+			t.Currency = result.Currency
+		}
+
+		// the occurrence of the transaction in the statement, counted on the hash of its first occurrence,
+		// also when it is a duplicate: the second of two equal payments is the second in every statement
+		first := transactionHash(t, 0)
+		hash := transactionHash(t, occurrences[first])
+		occurrences[first]++
+
+		res, err := tx.Exec(create, id, tm.DBFormat(t.Date), t.Amount, t.Currency, t.Description, t.Payee, t.Account, t.Source, hash, id, t.Description, t.Payee)
+		if err != nil {
+			log.Err("failed to import the transaction", "date", t.Date, "amount", t.Amount)
+			return result, wrap(err)
+		}
+
+		if n, _ := res.RowsAffected(); n == 0 {
+			result.Duplicates++
+			continue
+		}
+
+		err = tx.QueryRow(category, id, hash).Scan(&t.Category)
+		if err != nil {
+			log.Err("failed to get the category of the transaction")
+			return result, wrap(err)
+		}
+
+		amount, err := conv.convert(t.Amount, t.Currency, result.Currency, t.Date)
+		if err != nil {
+			return result, errors.Join(errors.New("failed to convert the transaction of "+t.Date.Format("02 Jan 2006")+" to "+result.Currency), err)
+		}
+
+		result.Imported = append(result.Imported, t)
+		result.Total += amount
+	}
+
+	if len(result.Imported) == 0 || dryRun {
+		return result, nil
+	}
+
+	_, err = tx.Exec(balance, result.Total, id)
+	if err != nil {
+		log.Err("failed to update the balance")
+		return result, wrap(err)
+	}
+
+	err = record(tx, "transactions_import", id, before)
+	if err != nil {
+		return result, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Err("failed to commit transaction")
+		return result, wrap(err)
+	}
+
+	return result, nil
+}
+
+// Transactions function returns the last transactions of the current profile, at most limit, the most recent first.
+func Transactions(limit int) ([]Transaction, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("transactions_list", id, limit)
+	if err != nil {
+		log.Err("failed to get the transactions")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		var date string
+		var category sql.NullString
+		err = rows.Scan(&t.ID, &date, &t.Amount, &t.Currency, &t.Description, &t.Payee, &t.Account, &category, &t.Source)
+		if err != nil {
+			log.Err("failed to scan the transaction")
+			return nil, err
+		}
+
+		t.Date, err = tm.DBParse(date)
+		if err != nil {
+			log.Err("failed to parse the transaction date")
+			return nil, err
+		}
+
+		t.Category = category.String
+		transactions = append(transactions, t)
+	}
+
+	return transactions, rows.Err()
+}
+
+// CategoryRuleCreate function creates a category rule of the current profile, the transactions whose description
+// or payee contains the pattern get the category. the transactions not yet categorised that match it are categorised too.
+// a rule with the same pattern gets the new category.
+func CategoryRuleCreate(pattern, category string) error {
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("category_rules_create", id, pattern, category, category, id, pattern)
+	if err != nil {
+		log.Err("failed to create the category rule", "pattern", pattern)
+		return err
+	}
+
+	return nil
+}
+
+// CategoryRules function returns the category rules of the current profile.
+func CategoryRules() ([]CategoryRule, error) {
+	id, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := gets("category_rules_list", id)
+	if err != nil {
+		log.Err("failed to get the category rules")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	rules := []CategoryRule{}
+	for rows.Next() {
+		var r CategoryRule
+		err = rows.Scan(&r.ID, &r.Pattern, &r.Category)
+		if err != nil {
+			log.Err("failed to scan the category rule")
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, rows.Err()
+}
+
+// CategoryRuleDelete function deletes a category rule of the current profile, the categorised transactions keep their category.
+func CategoryRuleDelete(rule int) error {
+	id, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = do("category_rules_delete", rule, id)
+	if err != nil {
+		log.Err("failed to delete the category rule", "id", rule)
+		return err
+	}
+
+	return nil
+}
+
+// transactionsBetween function returns the currency of the current profile and its transactions between two dates,
+// the first included and the second excluded, with the amounts converted to that currency with the exchange rate of their date.
+func transactionsBetween(from, to time.Time) (string, []Transaction, error) {
+	id, err := current()
+	if err != nil {
+		return "", nil, err
+	}
+
+	db, err := getDb()
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer db.Close()
+
+	currency, err := currencyIn(db, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	q, err := loadQuery("transactions_between")
+	if err != nil {
+		return "", nil, err
+	}
+
+	rows, err := db.Query(q, id, tm.DBFormat(from), tm.DBFormat(to))
+	if err != nil {
+		log.Err("failed to get the transactions")
+		return "", nil, wrap(err)
+	}
+
+	// the rows are read before the conversions, sqlite runs a query at a time on the connection
+	transactions := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		var date string
+		err = rows.Scan(&date, &t.Amount, &t.Currency, &t.Category)
+		if err != nil {
+			rows.Close()
+			log.Err("failed to scan the transaction")
+			return "", nil, err
+		}
+
+		t.Date, err = tm.DBParse(date)
+		if err != nil {
+			rows.Close()
+			return "", nil, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return "", nil, err
+	}
+
+	conv := newConverter(db)
+	for i, t := range transactions {
+		transactions[i].Amount, err = conv.convert(t.Amount, t.Currency, currency, t.Date)
+		if err != nil {
+			return "", nil, err
+		}
+		transactions[i].Currency = currency
+	}
+
+	return currency, transactions, nil
+}
+
+// TransactionsReport function returns the totals of the transactions of the current profile between two dates,
+// the first included and the second excluded, per category and overall, in the currency of the character.
+// every amount is converted with the exchange rate of its date, the categories are sorted by their expenses, the largest first.
+func TransactionsReport(from, to time.Time) (Report, error) {
+	report := Report{From: from, To: to, Categories: []CategoryTotal{}}
+	currency, transactions, err := transactionsBetween(from, to)
+	if err != nil {
+		return report, err
+	}
+
+	report.Currency = currency
+	categories := map[string]*CategoryTotal{}
+	for _, t := range transactions {
+		amount := t.Amount
+		total, ok := categories[t.Category]
+		if !ok {
+			total = &CategoryTotal{Category: t.Category}
+			categories[t.Category] = total
+		}
+
+		if amount >= 0 {
+			total.Income += amount
+			report.Income += amount
+		} else {
+			total.Expenses += amount
+			report.Expenses += amount
+		}
+		total.Count++
+		report.Count++
+	}
+
+	for _, total := range categories {
+		report.Categories = append(report.Categories, *total)
+	}
+
+	slices.SortFunc(report.Categories, func(a, b CategoryTotal) int {
+		if a.Expenses != b.Expenses {
+			return cmp.Compare(a.Expenses, b.Expenses)
+		}
+		return cmp.Compare(b.Income, a.Income)
+	})
+
+	return report, nil
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransactionHash(t *testing.T) {
+	day := time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local)
+	coffee := Transaction{Date: day, Amount: -250, Currency: "EUR", Description: "Coffee  bar", Payee: "Bar", Account: "IT60X"}
+	payment := Transaction{Date: day, Amount: -1000, Currency: "EUR", Description: "Payment", Account: "IT60X", Ref: "B123"}
+
+	tests := []struct {
+		name  string
+		a, b  Transaction
+		occA  int
+		occB  int
+		equal bool
+	}{
+		{name: "same values", a: coffee, b: coffee, equal: true},
+		{name: "spaces and case of the description", a: coffee, b: with(coffee, func(t *Transaction) { t.Description = "coffee bar " }), equal: true},
+		{name: "second occurrence", a: coffee, b: coffee, occB: 1},
+		{name: "another day", a: coffee, b: with(coffee, func(t *Transaction) { t.Date = day.AddDate(0, 0, 1) })},
+		{name: "another amount", a: coffee, b: with(coffee, func(t *Transaction) { t.Amount = -260 })},
+		{name: "another account", a: coffee, b: with(coffee, func(t *Transaction) { t.Account = "DE89X" })},
+		{name: "ref ignores the values", a: payment, b: with(payment, func(t *Transaction) { t.Description = "Card payment"; t.Amount = -990 }), equal: true},
+		{name: "another ref", a: payment, b: with(payment, func(t *Transaction) { t.Ref = "B124" })},
+		{name: "ref repeated in a statement", a: payment, b: payment, occB: 1},
+		{name: "ref of another account", a: payment, b: with(payment, func(t *Transaction) { t.Account = "DE89X" })},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := transactionHash(tt.a, tt.occA), transactionHash(tt.b, tt.occB)
+			if (a == b) != tt.equal {
+				t.Errorf("equal hashes = %v, want %v", a == b, tt.equal)
+			}
+		})
+	}
+}
+
+// with function returns a copy of a transaction changed by fn.
+func with(t Transaction, fn func(t *Transaction)) Transaction {
+	fn(&t)
+	return t
+}
+
+func TestTransactionsImport(t *testing.T) {
+	setup(t)
+	before, err := CharGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local)
+	coffee := Transaction{Date: day, Amount: -250, Description: "Coffee", Account: "IT60X"}
+	rent := Transaction{Date: day, Amount: -80000, Description: "Rent", Account: "IT60X"}
+	batch := Transaction{Date: day, Amount: -1000, Description: "Payment", Account: "IT60X", Ref: "B123"}
+
+	// the statements are imported in order in the same database
+	tests := []struct {
+		name       string
+		statement  []Transaction
+		dryRun     bool
+		imported   int
+		duplicates int
+		total      int64
+	}{
+		{name: "first statement", statement: []Transaction{coffee, rent}, imported: 2, total: -80250},
+		{name: "dry run", statement: []Transaction{coffee, coffee}, dryRun: true, imported: 1, duplicates: 1, total: -250},
+		{name: "second equal payment of the day", statement: []Transaction{coffee, coffee, rent}, imported: 1, duplicates: 2, total: -250},
+		{name: "same statement again", statement: []Transaction{coffee, coffee, rent}, duplicates: 3},
+		{name: "equal payments in another order", statement: []Transaction{rent, coffee, coffee}, duplicates: 3},
+		{name: "ref repeated in a statement", statement: []Transaction{batch, batch}, imported: 2, total: -2000},
+		{name: "ref with other values", statement: []Transaction{with(batch, func(t *Transaction) { t.Description = "Card payment" })}, duplicates: 1},
+		{name: "ref repeated once more", statement: []Transaction{batch, batch, batch}, imported: 1, duplicates: 2, total: -1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TransactionsImport(tt.statement, tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Imported) != tt.imported || result.Duplicates != tt.duplicates || result.Total != tt.total {
+				t.Errorf("imported %d, duplicates %d, total %d, want %d, %d, %d",
+					len(result.Imported), result.Duplicates, result.Total, tt.imported, tt.duplicates, tt.total)
+			}
+		})
+	}
+
+	transactions, err := Transactions(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transactions) != 6 {
+		t.Errorf("%d transactions in the ledger, want 6", len(transactions))
+	}
+
+	after, err := CharGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if after.Balance-before.Balance != -83500 {
+		t.Errorf("balance changed by %d, want -83500", after.Balance-before.Balance)
+	}
+}
@@ -0,0 +1,325 @@
+// db package export and import functions
+package db
+
+import (
+	"aio/pkg/log"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Table struct represents the rows of a table of the database, exported or to import.
+// the values are the ones of the database driver (int64, float64, string, []byte or nil),
+// the imported ones can also be strings, converted by the affinity of the columns.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+// Column struct represents a column of a table of the database.
+type Column struct {
+	Name       string
+	Type       string
+	NotNull    bool // the column can't be null
+	HasDefault bool // the column has a default value, or it is the primary key
+}
+
+// Imported struct represents the result of the import of a table.
+type Imported struct {
+	Table    string
+	Replaced int // the rows of the table before the import
+	Rows     int // the rows imported
+}
+
+// SchemaVersion function returns the version of the schema of the database, the number of migrations applied.
+// it is written in the exports, so an export can't be imported by an older version of aio.
+func SchemaVersion() (int, error) {
+	db, err := getDb()
+	if err != nil {
+		return 0, err
+	}
+
+	defer db.Close()
+	return versionOf(db)
+}
+
+// Tables function returns the names of the tables of the database.
+func Tables() ([]string, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+	return tablesIn(db)
+}
+
+// Columns function returns the columns of a table of the database, an empty slice if the table does not exist.
+func Columns(table string) ([]Column, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+	return columnsIn(db, table)
+}
+
+// columnsIn function returns the columns of a table of a database.
+func columnsIn(db *sql.DB, table string) ([]Column, error) {
+	rows, err := db.Query(`SELECT name, type, "notnull", dflt_value IS NOT NULL OR pk > 0 FROM pragma_table_info(?)`, table)
+	if err != nil {
+		log.Err("failed to get table columns", "table", table)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns := []Column{}
+	for rows.Next() {
+		var c Column
+		if err := rows.Scan(&c.Name, &c.Type, &c.NotNull, &c.HasDefault); err != nil {
+			log.Err("failed to scan column")
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+
+	return columns, rows.Err()
+}
+
+// Export function returns the rows of the tables with the given names, or of every table if names is empty.
+func Export(names []string) ([]Table, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	all, err := tablesIn(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		names = all
+	}
+
+	tables := []Table{}
+	for _, name := range names {
+		if !slices.Contains(all, name) {
+			return nil, errors.New("unknown table " + name + ", the tables are: " + strings.Join(all, ", "))
+		}
+
+		// table names come from sqlite_master, so they are safe to be quoted here
+		rows, err := db.Query(`SELECT * FROM "` + name + `" ORDER BY rowid`)
+		if err != nil {
+			log.Err("failed to read table", "table", name)
+			return nil, wrap(err)
+		}
+
+		t := Table{Name: name, Rows: [][]any{}}
+		t.Columns, err = rows.Columns()
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		for rows.Next() {
+			values := make([]any, len(t.Columns))
+			pointers := make([]any, len(values))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+
+			if err := rows.Scan(pointers...); err != nil {
+				rows.Close()
+				log.Err("failed to scan row", "table", name)
+				return nil, err
+			}
+			t.Rows = append(t.Rows, values)
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		tables = append(tables, t)
+	}
+
+	return tables, nil
+}
+
+// validate function checks that a table can be imported in the database:
+// the table must exist, its columns must exist and the required columns, without a default value, must be present.
+// a table without rows has no columns, it empties the table.
+func validate(db *sql.DB, t Table) error {
+	columns, err := columnsIn(db, t.Name)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		return errors.New("unknown table " + t.Name)
+	}
+
+	names := []string{}
+	for _, c := range columns {
+		names = append(names, c.Name)
+		if c.NotNull && !c.HasDefault && len(t.Rows) > 0 && !slices.Contains(t.Columns, c.Name) {
+			return errors.New("table " + t.Name + ": missing the required column " + c.Name)
+		}
+	}
+
+	for _, c := range t.Columns {
+		if !slices.Contains(names, c) {
+			return errors.New("table " + t.Name + ": unknown column " + c)
+		}
+	}
+
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return errors.New("table " + t.Name + ": row " + strconv.Itoa(i+1) + " has " + strconv.Itoa(len(row)) + " values, expected " + strconv.Itoa(len(t.Columns)))
+		}
+	}
+
+	return nil
+}
+
+// Import function replaces the rows of the tables with the given ones, in a single transaction.
+// the tables are validated against the schema of the database, and the foreign keys are checked after the import,
+// so the rows of a table can refer to the rows of another imported table.
+// with dryRun the import is checked and rolled back, the results tell what would change.
+func Import(tables []Table, dryRun bool) ([]Imported, error) {
+	db, err := getDb()
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	seen := map[string]bool{}
+	for _, t := range tables {
+		if seen[t.Name] {
+			return nil, errors.New("table " + t.Name + " is imported twice")
+		}
+		seen[t.Name] = true
+
+		err = validate(db, t)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := current()
+	if err != nil && !errors.Is(err, ErrNoCharacter) && !errors.Is(err, ErrNoProfile) {
+		return nil, err
+	}
+
+	// the foreign keys can be disabled only outside a transaction, on the connection of the transaction,
+	// so the rows of a replaced table are not deleted in cascade
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		log.Err("failed to get a database connection")
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		log.Err("failed to start transaction")
+		return nil, wrap(err)
+	}
+
+	defer tx.Rollback()
+
+	before, err := statsIn(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []Imported{}
+	for _, t := range tables {
+		res, err := tx.Exec(`DELETE FROM "` + t.Name + `"`)
+		if err != nil {
+			log.Err("failed to clear table", "table", t.Name)
+			return nil, wrap(err)
+		}
+
+		replaced, _ := res.RowsAffected()
+		results = append(results, Imported{Table: t.Name, Replaced: int(replaced), Rows: len(t.Rows)})
+		if len(t.Rows) == 0 {
+			continue
+		}
+
+		columns := make([]string, len(t.Columns))
+		marks := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			columns[i] = `"` + c + `"`
+			marks[i] = "?"
+		}
+
+		// the table and the columns have been validated against the schema, so they are safe to be quoted here
+		stmt, err := tx.Prepare(`INSERT INTO "` + t.Name + `" (` + strings.Join(columns, ", ") + `) VALUES (` + strings.Join(marks, ", ") + `)`)
+		if err != nil {
+			return nil, wrap(err)
+		}
+
+		for i, row := range t.Rows {
+			_, err = stmt.Exec(row...)
+			if err != nil {
+				stmt.Close()
+				return nil, errors.Join(errors.New("table "+t.Name+": failed to import row "+strconv.Itoa(i+1)), wrap(err))
+			}
+		}
+
+		stmt.Close()
+	}
+
+	// the rows referring to a missing row, e.g. the tasks of a character not imported
+	var table, parent string
+	var rowid sql.NullInt64
+	var fk int
+	err = tx.QueryRow("PRAGMA foreign_key_check").Scan(&table, &rowid, &parent, &fk)
+	if err == nil {
+		return nil, errors.New("table " + table + ": row " + strconv.FormatInt(rowid.Int64, 10) + " refers to a missing row of " + parent + ", import " + parent + " too")
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, wrap(err)
+	}
+
+	if dryRun {
+		return results, nil
+	}
+
+	err = record(tx, "import", id, before)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Err("failed to commit transaction")
+		return nil, wrap(err)
+	}
+
+	// the profile may not exist anymore, it is resolved again
+	setCharacter(0)
+	return results, nil
+}
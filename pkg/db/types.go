@@ -7,8 +7,9 @@ type Character struct {
 	FirstName   string
 	LastName    string
 	NickName    string
-	MonthBudget float64
-	Balance     float64
+	MonthBudget int64 // minor units of the currency
+	Balance     int64 // minor units of the currency
+	Currency    string
 	Coins       int
 	XP          int
 	NextLevelXP int
@@ -22,3 +23,131 @@ type Character struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+type Task struct {
+	ID    int
+	Title string
+	DueAt *time.Time
+}
+
+type Habit struct {
+	ID    int
+	Title string
+	Days  string
+	At    string
+}
+
+type Notification struct {
+	ID           int
+	Kind         string
+	RefID        int
+	Title        string
+	DueAt        time.Time
+	Lead         time.Duration
+	FiredAt      time.Time
+	SnoozedUntil *time.Time
+}
+
+type JobRun struct {
+	ID        int64
+	Job       string
+	Trigger   string
+	Status    string
+	Error     string
+	StartedAt time.Time
+	EndedAt   *time.Time
+}
+
+type Transaction struct {
+	ID          int
+	Date        time.Time
+	Amount      int64 // minor units of the currency, positive for an income, negative for an expense
+	Currency    string
+	Description string
+	Payee       string
+	Account     string
+	Ref         string // the id of the transaction given by the bank, if any, it is not stored
+	Category    string
+	Source      string
+}
+
+type Recurring struct {
+	ID          int
+	Description string
+	Amount      int64 // minor units of the currency, positive for an income, negative for an expense
+	Currency    string
+	Payee       string
+	Account     string
+	Category    string
+	Schedule    string // e.g. "every month on the 1st"
+	StartsAt    time.Time
+	EndsAt      *time.Time
+	NextAt      time.Time
+}
+
+type Rate struct {
+	Date  time.Time
+	Base  string
+	Quote string
+	Rate  float64 // units of the quote currency for one unit of the base currency
+	Dates int     // number of dates with a rate of the pair, set by Rates
+}
+
+type Report struct {
+	Currency   string // the base currency of the totals
+	From       time.Time
+	To         time.Time // excluded
+	Categories []CategoryTotal
+	Income     int64 // minor units of the base currency
+	Expenses   int64 // minor units of the base currency, negative
+	Count      int
+}
+
+type CategoryTotal struct {
+	Category string // empty for the transactions without a category
+	Income   int64
+	Expenses int64
+	Count    int
+}
+
+type Budget struct {
+	ID       int
+	Category string
+	Amount   int64 // minor units of the currency of the character, every month
+	Rollover bool  // the amount not spent in a month is carried to the next one
+	Carried  int64 // minor units of the currency of the character, carried to the first month
+	StartsAt time.Time
+}
+
+type Envelope struct {
+	Category string
+	Month    time.Time
+	Amount   int64 // minor units, the budget of the month
+	Rollover bool
+	Carried  int64 // minor units, carried from the previous months
+	Spent    int64 // minor units, the expenses of the month minus the refunds
+}
+
+type BudgetReport struct {
+	Month       time.Time
+	Currency    string
+	MonthBudget int64 // minor units, the monthly budget of the character
+	Envelopes   []Envelope
+	Spent       int64 // minor units, the expenses of the month in every category
+	Unbudgeted  int64 // minor units, the expenses of the month in the categories without a budget
+}
+
+type Penalty struct {
+	Category  string
+	Month     time.Time
+	Overspent int64 // minor units of the currency of the character
+	Currency  string
+	HP        int
+	Karma     int
+}
+
+type CategoryRule struct {
+	ID       int
+	Pattern  string
+	Category string
+}
@@ -2,6 +2,7 @@
 package git
 
 import (
+	"aio/pkg/config"
 	"aio/pkg/inputs"
 	"aio/pkg/log"
 	"aio/pkg/utils/cmd"
@@ -60,10 +61,12 @@ func hasChanges() (bool, error) {
 	return strings.TrimSpace(string(output)) != "", nil // Return true if there are changes to the database
 }
 
-// linkRepo function adds a link to a remote repository.
+// LinkRepo function adds a link to a remote repository.
 // it is used to link the database to a remote repository for versioning.
 // this action is optional and can be skipped by the user and performed later.
-func LinkRepo() error {
+// if remote is empty the user is asked for the repository name, in the form of YourUsername/repo-name.
+// the repository is on the sync.host of the config file, github by default.
+func LinkRepo(remote string) error {
 	log.Deb("checking if remote repository exists...")
 	re, err := remoteExists() // Check if a remote repository is linked to the database
 	if err != nil {
@@ -71,24 +74,33 @@ func LinkRepo() error {
 		return err
 	}
 
-	if !re {
-		log.Deb("linking remote repository...")
-		if inputs.RunConfirm("Do you want to add a link to a remote repository?") {
-			log.Print("Please enter the remote repository name:")                                 // Ask the user to enter the remote repository name
-			remote := inputs.RunInput("YourUsername/repo-name")                                   // Get the remote repository name from the user
-			output, err := cmd.Output("git", "remote", "add", "origin", "git@github.com:"+remote) // Add the remote repository
-			if err != nil {
-				log.Err("failed to add remote repository", "output", string(output))
-				return err
-			}
+	if re {
+		log.Warn("remote repository already exists")
+		return nil // Return nil if the remote repository is already linked
+	}
 
-			log.Info("remote repository added successfully!", "repository", remote)
-			return nil
+	log.Deb("linking remote repository...")
+	if remote == "" {
+		ok, err := inputs.RunConfirm("Do you want to add a link to a remote repository?")
+		if err != nil || !ok {
+			return err
+		}
+
+		log.Print("Please enter the remote repository name:")   // Ask the user to enter the remote repository name
+		remote, err = inputs.RunInput("YourUsername/repo-name") // Get the remote repository name from the user
+		if err != nil {
+			return err
 		}
 	}
 
-	log.Warn("remote repository already exists")
-	return nil // Return nil if the remote repository is already linked
+	output, err := cmd.Output("git", "remote", "add", "origin", config.Get().Sync.Host+remote) // Add the remote repository
+	if err != nil {
+		log.Err("failed to add remote repository", "output", string(output))
+		return err
+	}
+
+	log.Info("remote repository added successfully!", "repository", remote)
+	return nil
 }
 
 // InitialCommit function commits the database file.
@@ -161,7 +173,10 @@ func Init() error {
 		}
 
 		// if git is not initialized, link the repository
-		LinkRepo()
+		// without a terminal the link is skipped, it can be added later with aio -l --remote
+		if inputs.Interactive() {
+			LinkRepo("")
+		}
 
 		re, err := remoteExists() // Check if a remote repository is linked to the database
 		if err != nil {
@@ -251,7 +266,8 @@ func Pull() error {
 
 // Commit function commits the changes made to the database.
 // it is used to commit the changes made to the database to the local repository.
-func Commit() error {
+// the message should summarise the changes, if it is empty a timestamped message is used.
+func Commit(message string) error {
 	log.Deb("checking if database has been changed...")
 	ch, err := hasChanges() // Check if there are changes to the database
 	if err != nil {
@@ -274,7 +290,11 @@ func Commit() error {
 		}
 
 		// commit the changes
-		output, err = cmd.Output("git", "commit", "-m", "changes-"+time.Now().Format("20060102150405"))
+		if message == "" {
+			message = "changes-" + time.Now().Format("20060102150405")
+		}
+
+		output, err = cmd.Output("git", "commit", "-m", message)
 		if err != nil {
 			log.Err("failed to commit database file", "output", string(output))
 			return err
@@ -351,64 +371,3 @@ func Push() error {
 	log.Warn("no remote repository linked")
 	return nil
 }
-
-// Revert function reverts the database to a previous version.
-// it is used to revert the database to a previous version.
-// it gets the commit hash of the version to revert to and reverts the database to that version.
-// it also commits the changes.
-func Revert() error {
-	confirm := inputs.RunConfirm("Are you sure you want to revert the database?")
-	if !confirm {
-		return nil
-	}
-
-	log.Deb("getting commit history...")
-
-	// get the commit hash
-	output, err := cmd.Output("git", "log", "--pretty=format:%h %ad %s", "--date=short", "data.db")
-	if err != nil {
-		log.Err("failed to get commit history", "output", string(output))
-		return err
-	}
-
-	history := strings.Split(string(output), "\n")
-	log.Print("Select the version to revert to:\n")
-	choice := inputs.RunSelect(history)
-	log.Print("")
-	commitHash := strings.Split(choice, " ")[0]
-	version := strings.Split(choice, " ")[2]
-
-	log.Deb("do a back up of the database...")
-	// do a back up of the database
-	err = fs.Backup()
-	if err != nil {
-		log.Err("failed to back up the database")
-		return err
-	}
-
-	log.Deb("reverting database to " + version + "...")
-	// revert the database
-	output, err = cmd.Output("git", "checkout", commitHash, "--", "data.db")
-	if err != nil {
-		log.Err("failed to revert database", "output", string(output))
-		return err
-	}
-
-	log.Deb("committing changes...")
-	// commit the changes
-	output, err = cmd.Output("git", "add", "data.db")
-	if err != nil {
-		log.Err("failed to add database file", "output", string(output))
-		return err
-	}
-
-	// commit the changes
-	output, err = cmd.Output("git", "commit", "-m", "revert-database-to-"+version)
-	if err != nil {
-		log.Err("failed to commit database file", "output", string(output))
-		return err
-	}
-
-	log.Info("database reverted successfully!")
-	return nil
-}
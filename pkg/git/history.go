@@ -0,0 +1,170 @@
+// git package history functions
+package git
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/cmd"
+	"aio/pkg/utils/fs"
+	"aio/pkg/utils/tm"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version struct represents a version of the database stored in the local repository.
+type Version struct {
+	Hash    string // abbreviated hash of the commit, shown to the user
+	Commit  string // full hash of the commit, a ref is a prefix of it
+	Date    time.Time
+	Message string
+}
+
+// String function returns the version as a single line, used in the select prompts.
+func (v Version) String() string {
+	return v.Hash + " " + v.Date.Format("2006-01-02 15:04") + " " + v.Message
+}
+
+// History function returns the list of the database versions, from the newest to the oldest.
+// it is used to list the versions of the database that can be restored.
+func History() ([]Version, error) {
+	// %x09 is a tab, used to split the fields without conflicts with the commit message
+	output, err := cmd.Output("git", "log", "--pretty=format:%h%x09%H%x09%at%x09%s", "--", "data.db")
+	if err != nil {
+		log.Err("failed to get commit history", "output", string(output))
+		return nil, err
+	}
+
+	history := []Version{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			log.Warn("skipping malformed history line", "line", line)
+			continue
+		}
+
+		ts, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			log.Err("failed to parse commit timestamp", "line", line)
+			return nil, err
+		}
+
+		history = append(history, Version{Hash: fields[0], Commit: fields[1], Date: time.Unix(ts, 0), Message: fields[3]})
+	}
+
+	return history, nil
+}
+
+// Find function returns the version of the database identified by ref.
+// ref can be a commit hash (or its prefix) or a time string supported by tm.Parse,
+// in that case the last version saved before that time is returned.
+// a prefix of more than one version is ambiguous, it returns an error.
+func Find(ref string) (Version, error) {
+	history, err := History()
+	if err != nil {
+		return Version{}, err
+	}
+
+	return find(history, ref)
+}
+
+// find function returns the version identified by ref in the history, from the newest to the oldest version.
+func find(history []Version, ref string) (Version, error) {
+	// short refs are too ambiguous to be a hash, they are parsed as a time string
+	if len(ref) >= 4 {
+		found := []Version{}
+		for _, v := range history {
+			if strings.HasPrefix(v.Commit, strings.ToLower(ref)) {
+				found = append(found, v)
+			}
+		}
+
+		if len(found) > 1 {
+			return Version{}, errors.New("ambiguous version " + ref + ", it is the prefix of " + strconv.Itoa(len(found)) + " versions, use a longer hash")
+		}
+
+		if len(found) == 1 {
+			return found[0], nil
+		}
+	}
+
+	t, err := tm.Parse(ref)
+	if err != nil {
+		return Version{}, errors.Join(errors.New("no version found for "+ref), err)
+	}
+
+	for _, v := range history {
+		if !v.Date.After(t) {
+			return v, nil
+		}
+	}
+
+	return Version{}, errors.New("no version found before " + t.Format("2006-01-02 15:04"))
+}
+
+// Snapshot function writes the database file of a version to a temporary file.
+// it returns the path of the temporary file, the caller is responsible to remove it.
+func Snapshot(v Version) (string, error) {
+	output, err := cmd.Output("git", "show", v.Hash+":data.db")
+	if err != nil {
+		log.Err("failed to read database version", "commit", v.Hash)
+		return "", err
+	}
+
+	file, err := os.CreateTemp("", "aio-snapshot-*.db")
+	if err != nil {
+		log.Err("failed to create snapshot file")
+		return "", err
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(output)
+	if err != nil {
+		os.Remove(file.Name())
+		log.Err("failed to write snapshot file")
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// Restore function restores the whole database to a previous version.
+// it makes a back up of the current database, checks out the version and commits the changes.
+func Restore(v Version) error {
+	log.Deb("do a back up of the database...")
+	err := fs.Backup()
+	if err != nil {
+		log.Err("failed to back up the database")
+		return err
+	}
+
+	version := v.Date.Format("2006-01-02 15:04")
+	log.Deb("restoring database to " + v.Hash + " (" + version + ")...")
+	output, err := cmd.Output("git", "checkout", v.Hash, "--", "data.db")
+	if err != nil {
+		log.Err("failed to restore database", "output", string(output))
+		return err
+	}
+
+	log.Deb("committing changes...")
+	output, err = cmd.Output("git", "add", "data.db")
+	if err != nil {
+		log.Err("failed to add database file", "output", string(output))
+		return err
+	}
+
+	output, err = cmd.Output("git", "commit", "-m", "restore database to "+v.Hash+" ("+version+")")
+	if err != nil {
+		log.Err("failed to commit database file", "output", string(output))
+		return err
+	}
+
+	log.Info("database restored successfully!", "commit", v.Hash, "version", version)
+	return nil
+}
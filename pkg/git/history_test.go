@@ -0,0 +1,129 @@
+package git
+
+import (
+	"aio/pkg/utils/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFind(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 12, 0, 0, 0, time.Local) }
+	history := []Version{
+		{Hash: "4f1c2ab", Commit: "4f1c2ab90e1d7c3b5a6f8e9d0c1b2a3f4e5d6c7b", Date: day(12), Message: "completed 2 tasks"},
+		{Hash: "4f1c9d0", Commit: "4f1c9d0a1b2c3d4e5f60718293a4b5c6d7e8f901", Date: day(10), Message: "created a task"},
+		{Hash: "0a9b8c7", Commit: "0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b", Date: day(5), Message: "created the character"},
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		want string // the hash of the version found, empty if an error is expected
+	}{
+		{name: "abbreviated hash", ref: "0a9b8c7", want: "0a9b8c7"},
+		{name: "prefix", ref: "4f1c2", want: "4f1c2ab"},
+		{name: "full hash", ref: "4f1c9d0a1b2c3d4e5f60718293a4b5c6d7e8f901", want: "4f1c9d0"},
+		{name: "upper case hash", ref: "0A9B8C7", want: "0a9b8c7"},
+		{name: "ambiguous prefix", ref: "4f1c"},
+		{name: "longer than the hash", ref: "0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1bff"},
+		{name: "date", ref: "11 Oct 2026", want: "4f1c9d0"},
+		{name: "date of a version", ref: "12 Oct 2026 12:00", want: "4f1c2ab"},
+		{name: "before the first version", ref: "01 Oct 2026"},
+		{name: "neither a hash nor a date", ref: "yesterday-ish"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := find(history, tt.ref)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("find(%q) = %s, want an error", tt.ref, v.Hash)
+				}
+				return
+			}
+
+			if err != nil || v.Hash != tt.want {
+				t.Errorf("find(%q) = %s, %v, want %s", tt.ref, v.Hash, err, tt.want)
+			}
+		})
+	}
+}
+
+// commit function commits a content of the database file in the repository of the data directory, at the given date.
+func commit(t *testing.T, dir, content string, date time.Time) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(dir, "data.db"), []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"add", "data.db"}, {"commit", "-q", "-m", content}} {
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		c.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date.Format(time.RFC3339), "GIT_COMMITTER_DATE="+date.Format(time.RFC3339))
+		if output, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	err := fs.SetHome(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := fs.DataDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "Jane")
+	t.Setenv("GIT_AUTHOR_EMAIL", "jane@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Jane")
+	t.Setenv("GIT_COMMITTER_EMAIL", "jane@example.com")
+	init := exec.Command("git", "init", "-q")
+	init.Dir = dir
+	if output, err := init.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+
+	first := time.Date(2026, time.October, 10, 12, 0, 0, 0, time.Local)
+	commit(t, dir, "first version", first)
+	commit(t, dir, "second version", first.AddDate(0, 0, 2))
+
+	history, err := History()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 2 || history[0].Message != "second version" || !history[1].Date.Equal(first) {
+		t.Fatalf("History() = %+v, want the two versions from the newest", history)
+	}
+
+	// the full hash and the date find the first version, its snapshot has its content
+	for _, ref := range []string{history[1].Commit, history[1].Hash, "11 Oct 2026"} {
+		v, err := Find(ref)
+		if err != nil || v.Commit != history[1].Commit {
+			t.Fatalf("Find(%q) = %+v, %v, want the first version", ref, v, err)
+		}
+	}
+
+	file, err := Snapshot(history[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file)
+	content, err := os.ReadFile(file)
+	if err != nil || string(content) != "first version" {
+		t.Errorf("snapshot content %q, %v, want the first version", content, err)
+	}
+}
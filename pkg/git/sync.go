@@ -0,0 +1,256 @@
+// git package sync functions
+package git
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/cmd"
+	"aio/pkg/utils/fs"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sync errors, used to handle the failures of the push in different ways
+var (
+	ErrAuth     = errors.New("authentication failed")
+	ErrNetwork  = errors.New("remote repository unreachable")
+	ErrConflict = errors.New("remote repository has diverged")
+	ErrNoRemote = errors.New("no remote repository linked, link one with aio -l")
+)
+
+// backoff settings for the push retries
+const (
+	networkBackoff = 5 * time.Minute // first delay after a network error, doubled at every failure
+	authBackoff    = time.Hour       // first delay after an authentication error, doubled at every failure
+	maxBackoff     = 12 * time.Hour  // maximum delay between two attempts
+)
+
+// SyncState struct represents the state of the synchronization with the remote repository.
+// it is persisted in the sync.json file, next to the database, but it is not versioned.
+type SyncState struct {
+	LastSuccess time.Time `json:"last_success"`
+	LastAttempt time.Time `json:"last_attempt"`
+	LastError   string    `json:"last_error"`
+	ErrorKind   string    `json:"error_kind"`
+	Failures    int       `json:"failures"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// Status function returns a short description of the state.
+func (s *SyncState) Status() string {
+	switch s.ErrorKind {
+	case "":
+		return "ok"
+	case ErrConflict.Error():
+		return "conflict, manual action required"
+	default:
+		return "retrying"
+	}
+}
+
+// syncFile function returns the path of the sync state file.
+func syncFile() (string, error) {
+	return fs.StatePath("sync.json")
+}
+
+// LoadSyncState function reads the sync state from the sync state file.
+// if the file does not exist, it returns an empty state.
+func LoadSyncState() (*SyncState, error) {
+	s := &SyncState{}
+	file, err := syncFile()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+
+	if err != nil {
+		log.Err("failed to read sync state file", "file", file)
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, s)
+	if err != nil {
+		log.Err("failed to parse sync state file", "file", file)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// save function writes the sync state to the sync state file.
+func (s *SyncState) save() error {
+	file, err := syncFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(file, data, 0644)
+	if err != nil {
+		log.Err("failed to write sync state file", "file", file)
+		return err
+	}
+
+	return nil
+}
+
+// fail function records a failed push in the state and schedules the next attempt.
+// network errors are retried with an exponential backoff, authentication errors with a longer one,
+// conflicts are not retried until the user forces a new sync.
+func (s *SyncState) fail(err error) {
+	s.Failures++
+	s.LastError = err.Error()
+
+	var delay time.Duration
+	switch {
+	case errors.Is(err, ErrConflict):
+		s.ErrorKind = ErrConflict.Error()
+		s.NextAttempt = time.Time{}
+		return
+	case errors.Is(err, ErrAuth):
+		s.ErrorKind = ErrAuth.Error()
+		delay = authBackoff
+	case errors.Is(err, ErrNetwork):
+		s.ErrorKind = ErrNetwork.Error()
+		delay = networkBackoff
+	default:
+		s.ErrorKind = "unknown error"
+		delay = networkBackoff
+	}
+
+	// double the delay at every consecutive failure, up to the maximum
+	for i := 1; i < s.Failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	s.NextAttempt = s.LastAttempt.Add(min(delay, maxBackoff))
+}
+
+// classify function wraps a git error with the sync error it represents.
+// the error kind is detected from the git error output.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	output := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		output = string(exitErr.Stderr)
+	}
+
+	out := strings.ToLower(output)
+	output = strings.TrimSpace(output)
+	switch {
+	case strings.Contains(out, "permission denied"),
+		strings.Contains(out, "authentication failed"),
+		strings.Contains(out, "could not read username"),
+		strings.Contains(out, "host key verification failed"):
+		return errors.Join(ErrAuth, errors.New(output))
+	case strings.Contains(out, "could not resolve host"),
+		strings.Contains(out, "network is unreachable"),
+		strings.Contains(out, "connection timed out"),
+		strings.Contains(out, "connection refused"),
+		strings.Contains(out, "could not read from remote repository"),
+		strings.Contains(out, "unable to access"):
+		return errors.Join(ErrNetwork, errors.New(output))
+	case strings.Contains(out, "rejected"),
+		strings.Contains(out, "non-fast-forward"),
+		strings.Contains(out, "fetch first"),
+		strings.Contains(out, "conflict"):
+		return errors.Join(ErrConflict, errors.New(output))
+	}
+
+	return errors.Join(err, errors.New(output))
+}
+
+// Pending function returns the number of local commits not yet pushed to the remote repository.
+func Pending() (int, error) {
+	rng := "origin/main..HEAD"
+	if _, err := cmd.Output("git", "rev-parse", "--verify", "origin/main"); err != nil {
+		rng = "HEAD"
+	}
+
+	if !hasLocalCommits() {
+		return 0, nil
+	}
+
+	output, err := cmd.Output("git", "rev-list", "--count", rng)
+	if err != nil {
+		log.Err("failed to count unpushed commits", "output", string(output))
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		log.Err("failed to parse git rev-list output", "output", string(output))
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Sync function pushes the local commits to the remote repository, following the sync state machine.
+// if a previous push failed, the push is skipped until the next attempt time,
+// unless force is true. conflicts are retried only when forced.
+// if no remote repository is linked, the sync is skipped, a forced sync returns ErrNoRemote.
+// the state is persisted after every attempt and returned to the caller.
+func Sync(force bool) (*SyncState, error) {
+	s, err := LoadSyncState()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !force {
+		if s.ErrorKind == ErrConflict.Error() {
+			log.Warn("sync skipped, the remote repository has diverged", "err", s.LastError)
+			return s, nil
+		}
+
+		if now.Before(s.NextAttempt) {
+			log.Deb("sync skipped, waiting for the next attempt", "next", s.NextAttempt)
+			return s, nil
+		}
+	}
+
+	re, err := remoteExists()
+	if err != nil {
+		return s, err
+	}
+
+	if !re {
+		log.Deb("sync skipped, no remote repository linked")
+		if force {
+			return s, ErrNoRemote
+		}
+		return s, nil
+	}
+
+	s.LastAttempt = now
+	err = classify(Push())
+	if err != nil {
+		s.fail(err)
+		log.Err("failed to sync with the remote repository", "kind", s.ErrorKind, "failures", s.Failures, "next", s.NextAttempt)
+		return s, errors.Join(err, s.save())
+	}
+
+	s.LastSuccess = now
+	s.LastError = ""
+	s.ErrorKind = ""
+	s.Failures = 0
+	s.NextAttempt = time.Time{}
+	return s, s.save()
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   error // nil if the error is not a sync error
+	}{
+		{name: "ssh key refused", stderr: "git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", want: ErrAuth},
+		{name: "https credentials", stderr: "remote: Invalid username or password.\nfatal: Authentication failed for 'https://github.com/jay/aio-data.git/'", want: ErrAuth},
+		{name: "no credentials", stderr: "fatal: could not read Username for 'https://github.com': terminal prompts disabled", want: ErrAuth},
+		{name: "unknown host key", stderr: "Host key verification failed.\nfatal: Could not read from remote repository.", want: ErrAuth},
+		{name: "no dns", stderr: "ssh: Could not resolve hostname github.com: Temporary failure in name resolution", want: ErrNetwork},
+		{name: "offline", stderr: "fatal: unable to access 'https://github.com/jay/aio-data.git/': Could not resolve host: github.com", want: ErrNetwork},
+		{name: "timeout", stderr: "ssh: connect to host github.com port 22: Connection timed out", want: ErrNetwork},
+		{name: "refused", stderr: "ssh: connect to host github.com port 22: Connection refused", want: ErrNetwork},
+		{name: "remote ahead", stderr: " ! [rejected]        main -> main (fetch first)\nerror: failed to push some refs", want: ErrConflict},
+		{name: "diverged", stderr: " ! [rejected]        main -> main (non-fast-forward)", want: ErrConflict},
+		{name: "merge conflict", stderr: "CONFLICT (content): Merge conflict in data.db", want: ErrConflict},
+		{name: "other error", stderr: "fatal: not a git repository (or any of the parent directories): .git"},
+	}
+
+	kinds := []error{ErrAuth, ErrNetwork, ErrConflict}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the output of git is read from the stderr of the exit error, like the errors of cmd.Output
+			_, err := exec.Command("sh", "-c", `printf '%s' "$0" >&2; exit 128`, tt.stderr).Output()
+			if err == nil {
+				t.Fatal("the command did not fail")
+			}
+
+			got := classify(err)
+			for _, e := range kinds {
+				if errors.Is(got, e) != (e == tt.want) {
+					t.Errorf("classify() = %v, is %v %v, want %v", got, e, errors.Is(got, e), tt.want)
+				}
+			}
+		})
+	}
+
+	if classify(nil) != nil {
+		t.Error("classify(nil) is not nil")
+	}
+}
+
+func TestFail(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		delays []time.Duration // the delays before the next attempt after every consecutive failure, 0 if not retried
+	}{
+		{name: "network", err: ErrNetwork, delays: []time.Duration{5 * time.Minute, 10 * time.Minute, 20 * time.Minute, 40 * time.Minute, 80 * time.Minute}},
+		{name: "authentication", err: ErrAuth, delays: []time.Duration{time.Hour, 2 * time.Hour, 4 * time.Hour, 8 * time.Hour, 12 * time.Hour, 12 * time.Hour}},
+		{name: "unknown", err: errors.New("git crashed"), delays: []time.Duration{5 * time.Minute, 10 * time.Minute}},
+		{name: "conflict", err: ErrConflict, delays: []time.Duration{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SyncState{}
+			attempt := time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)
+			for i, want := range tt.delays {
+				s.LastAttempt = attempt
+				s.fail(tt.err)
+				if s.Failures != i+1 {
+					t.Errorf("failures = %d, want %d", s.Failures, i+1)
+				}
+
+				if want == 0 {
+					if !s.NextAttempt.IsZero() {
+						t.Errorf("failure %d: next attempt at %s, want no retry", i+1, s.NextAttempt)
+					}
+					continue
+				}
+
+				if got := s.NextAttempt.Sub(attempt); got != want {
+					t.Errorf("failure %d: next attempt after %s, want %s", i+1, got, want)
+				}
+				attempt = s.NextAttempt
+			}
+		})
+	}
+
+	// the network backoff stops doubling at the maximum
+	s := &SyncState{Failures: 20}
+	s.fail(ErrNetwork)
+	if got := s.NextAttempt.Sub(s.LastAttempt); got != maxBackoff {
+		t.Errorf("next attempt after %s after 21 failures, want %s", got, maxBackoff)
+	}
+}
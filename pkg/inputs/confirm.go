@@ -2,9 +2,7 @@
 package inputs
 
 import (
-	"aio/pkg/log"
 	"fmt"
-	"os"
 
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -65,7 +63,17 @@ func (c *confirm) View() string {
 
 // RunConfirm function initializes the confirm field and returns the value.
 // It takes a string as input question and returns a boolean.
-func RunConfirm(question string) bool {
+// if the user cancels the input, it returns ErrCanceled.
+// in yes mode it returns true without asking, if the user can't be asked it returns ErrNoInput.
+func RunConfirm(question string) (bool, error) {
+	if yes {
+		return true, nil
+	}
+
+	if !Interactive() {
+		return false, ErrNoInput
+	}
+
 	ti := textinput.New()
 	ti.Placeholder = "y/n"
 	ti.Prompt = ""
@@ -74,10 +82,10 @@ func RunConfirm(question string) bool {
 	m := confirm{in: ti, question: question, done: false, response: false}
 	p := tea.NewProgram(&m)
 	if _, err := p.Run(); err != nil {
-		log.Fat(err)
+		return false, err
 	}
 	if !m.done {
-		os.Exit(0)
+		return false, ErrCanceled
 	}
-	return m.response
+	return m.response, nil
 }
@@ -0,0 +1,128 @@
+// inputs currency functions
+package inputs
+
+import (
+	"aio/pkg/utils/money"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// currency struct represents an input field for an amount of money in a currency.
+// the amount can be typed or changed with the arrows, by a unit of the currency.
+type currency struct {
+	in       textinput.Model
+	currency string
+	step     int64 // minor units, a unit of the currency
+	amount   int64 // minor units, the confirmed amount
+	done     bool
+	err      error
+}
+
+// newCurrency function returns a currency field with the given placeholder, the code of the currency is the prompt.
+func newCurrency(ph, code string) *currency {
+	ti := textinput.New()
+	ti.Placeholder = ph
+	ti.Prompt = code + " "
+	ti.CharLimit = 20
+	ti.Focus()
+	return &currency{in: ti, currency: code, step: money.Minor(1, code)}
+}
+
+// Init function initializes the currency field.
+func (c *currency) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update function updates the currency field based on the message received.
+// up and down change the amount by a unit, page up and down by ten units.
+func (c *currency) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		c.err = nil
+
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			c.done = false
+			c.in.Blur()
+			return c, tea.Quit
+		case tea.KeyUp:
+			c.add(c.step)
+			return c, nil
+		case tea.KeyDown:
+			c.add(-c.step)
+			return c, nil
+		case tea.KeyPgUp:
+			c.add(10 * c.step)
+			return c, nil
+		case tea.KeyPgDown:
+			c.add(-10 * c.step)
+			return c, nil
+		case tea.KeyEnter:
+			amount, err := c.parse()
+			if err != nil {
+				c.err = err
+				return c, nil
+			}
+			c.amount = amount
+			c.done = true
+			c.in.Blur()
+			return c, tea.Quit
+		}
+	}
+
+	c.in, cmd = c.in.Update(msg)
+	return c, cmd
+}
+
+// parse function returns the amount typed in minor units of the currency, the comma is a decimal separator too.
+// an empty amount is the placeholder.
+func (c *currency) parse() (int64, error) {
+	value := strings.TrimSpace(c.in.Value())
+	if value == "" {
+		value = c.in.Placeholder
+	}
+	return money.Parse(strings.ReplaceAll(value, ",", "."), c.currency)
+}
+
+// add function adds n minor units to the amount, an invalid or empty amount counts as zero.
+func (c *currency) add(n int64) {
+	v, _ := money.Parse(strings.ReplaceAll(strings.TrimSpace(c.in.Value()), ",", "."), c.currency)
+	c.in.SetValue(money.Format(v+n, c.currency))
+	c.in.CursorEnd()
+}
+
+// View function returns the currency field as a string.
+func (c *currency) View() string {
+	view := c.in.View() + "\n"
+	if c.err != nil {
+		view += errorStyle.Render(c.err.Error()) + "\n"
+	}
+	step := money.Format(c.step, c.currency)
+	return view + helpStyle.Render("up/down: ±"+step+", pgup/pgdn: ±"+money.Format(10*c.step, c.currency)+", enter: confirm") + "\n\n"
+}
+
+// RunCurrency function initializes the currency field and returns the amount in minor units of the currency.
+// It takes a string as input placeholder, used as the amount when nothing is typed, and the code of the currency,
+// the amount can't have more decimals than the currency.
+// the arrows change the amount by a unit of the currency, page up and down by ten units.
+// if the user cancels the input, it returns ErrCanceled.
+// if the user can't be asked, it returns ErrNoInput.
+func RunCurrency(ph, code string) (int64, error) {
+	if !Interactive() {
+		return 0, ErrNoInput
+	}
+
+	m := newCurrency(ph, code)
+	p := tea.NewProgram(m)
+	if _, err := p.Run(); err != nil {
+		return 0, err
+	}
+	if !m.done {
+		return 0, ErrCanceled
+	}
+	return m.amount, nil
+}
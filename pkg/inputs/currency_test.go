@@ -0,0 +1,69 @@
+package inputs
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		ph       string
+		currency string
+		msgs     []tea.Msg
+		done     bool
+		amount   int64
+		value    string
+		invalid  bool
+	}{
+		{name: "typed amount", currency: "EUR", msgs: []tea.Msg{runes("12.5"), key(tea.KeyEnter)}, done: true, amount: 1250},
+		{name: "comma decimal separator", currency: "EUR", msgs: []tea.Msg{runes("12,5"), key(tea.KeyEnter)}, done: true, amount: 1250},
+		{name: "currency without decimals", currency: "JPY", msgs: []tea.Msg{runes("1500"), key(tea.KeyEnter)}, done: true, amount: 1500},
+		{name: "too many decimals", currency: "JPY", msgs: []tea.Msg{runes("1500.5"), key(tea.KeyEnter)}, invalid: true},
+		{name: "three decimals", currency: "KWD", msgs: []tea.Msg{runes("1.234"), key(tea.KeyEnter)}, done: true, amount: 1234},
+		{name: "not a number", currency: "EUR", msgs: []tea.Msg{runes("abc"), key(tea.KeyEnter)}, invalid: true},
+		{name: "empty is the placeholder", ph: "400.00", currency: "EUR", msgs: []tea.Msg{key(tea.KeyEnter)}, done: true, amount: 40000},
+		{name: "empty without placeholder", currency: "EUR", msgs: []tea.Msg{key(tea.KeyEnter)}, invalid: true},
+		{name: "up by a unit", currency: "EUR", msgs: []tea.Msg{key(tea.KeyUp), key(tea.KeyUp), key(tea.KeyEnter)}, done: true, amount: 200, value: "2.00"},
+		{name: "page up by ten units", currency: "JPY", msgs: []tea.Msg{key(tea.KeyPgUp), key(tea.KeyEnter)}, done: true, amount: 10, value: "10"},
+		{name: "down below zero", currency: "EUR", msgs: []tea.Msg{runes("0.50"), key(tea.KeyDown), key(tea.KeyPgDown), key(tea.KeyEnter)}, done: true, amount: -1050, value: "-10.50"},
+		{name: "canceled", currency: "EUR", msgs: []tea.Msg{runes("12"), key(tea.KeyEsc)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCurrency(tt.ph, tt.currency)
+			cmd := send(c, tt.msgs...)
+			if c.done != tt.done {
+				t.Fatalf("done = %v, want %v", c.done, tt.done)
+			}
+
+			if tt.done && c.amount != tt.amount {
+				t.Errorf("amount = %d, want %d", c.amount, tt.amount)
+			}
+
+			if tt.value != "" && c.in.Value() != tt.value {
+				t.Errorf("value = %q, want %q", c.in.Value(), tt.value)
+			}
+
+			if (c.err != nil) != tt.invalid {
+				t.Errorf("err = %v, want an error %v", c.err, tt.invalid)
+			}
+
+			if !tt.invalid && !quits(cmd) {
+				t.Errorf("the input did not quit")
+			}
+		})
+	}
+}
+
+func TestCurrencyView(t *testing.T) {
+	view := newCurrency("", "JPY").View()
+	for _, want := range []string{"JPY ", "±1,", "±10,"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view %q does not contain %q", view, want)
+		}
+	}
+}
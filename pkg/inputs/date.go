@@ -0,0 +1,130 @@
+// inputs date picker functions
+package inputs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// datePicker struct represents a calendar to pick a date.
+type datePicker struct {
+	date time.Time
+	done bool
+}
+
+// newDatePicker function returns a date picker on the given date, or on today if it is zero.
+func newDatePicker(initial time.Time) *datePicker {
+	if initial.IsZero() {
+		initial = time.Now()
+	}
+	y, m, d := initial.Date()
+	return &datePicker{date: time.Date(y, m, d, 0, 0, 0, 0, time.Local)}
+}
+
+// Init function initializes the date picker.
+func (d *datePicker) Init() tea.Cmd {
+	return nil
+}
+
+// Update function updates the date picker based on the message received.
+// the arrows move by a day or by a week, page up and down by a month, t goes back to today.
+func (d *datePicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			d.done = false
+			return d, tea.Quit
+		case tea.KeyLeft:
+			d.date = d.date.AddDate(0, 0, -1)
+		case tea.KeyRight:
+			d.date = d.date.AddDate(0, 0, 1)
+		case tea.KeyUp:
+			d.date = d.date.AddDate(0, 0, -7)
+		case tea.KeyDown:
+			d.date = d.date.AddDate(0, 0, 7)
+		case tea.KeyPgUp:
+			d.date = addMonths(d.date, -1)
+		case tea.KeyPgDown:
+			d.date = addMonths(d.date, 1)
+		case tea.KeyEnter:
+			d.done = true
+			return d, tea.Quit
+		}
+
+		switch msg.String() {
+		case "t":
+			*d = *newDatePicker(time.Time{})
+		case "[":
+			d.date = addMonths(d.date, -12)
+		case "]":
+			d.date = addMonths(d.date, 12)
+		}
+	}
+
+	return d, nil
+}
+
+// addMonths function adds n months to a date, keeping the day in the month:
+// 31 Jan plus a month is the last day of February.
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m, 1, 0, 0, 0, 0, t.Location()).AddDate(0, n, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	if d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// View function returns the calendar of the month of the selected date, the weeks start on monday.
+func (d *datePicker) View() string {
+	var b strings.Builder
+	b.WriteString(selectedStyle.Render(fmt.Sprintf("%s %d", d.date.Month(), d.date.Year())) + "\n")
+	b.WriteString("Mo Tu We Th Fr Sa Su\n")
+
+	first := d.date.AddDate(0, 0, 1-d.date.Day())
+	offset := (int(first.Weekday()) + 6) % 7
+	b.WriteString(strings.Repeat("   ", offset))
+
+	days := first.AddDate(0, 1, -1).Day()
+	for day := 1; day <= days; day++ {
+		cell := fmt.Sprintf("%2d", day)
+		if day == d.date.Day() {
+			cell = matchStyle.Reverse(true).Render(cell)
+		}
+		b.WriteString(cell)
+
+		if (offset+day)%7 == 0 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+
+	b.WriteString("\n" + helpStyle.Render("arrows: day/week, pgup/pgdn: month, [/]: year, t: today, enter: confirm") + "\n\n")
+	return b.String()
+}
+
+// RunDate function initializes the date picker and returns the picked date, at midnight in the local time.
+// It takes as input the date selected at the start, today if it is zero.
+// if the user cancels the input, it returns ErrCanceled.
+// if the user can't be asked, it returns ErrNoInput.
+func RunDate(initial time.Time) (time.Time, error) {
+	if !Interactive() {
+		return time.Time{}, ErrNoInput
+	}
+
+	m := newDatePicker(initial)
+	p := tea.NewProgram(m)
+	if _, err := p.Run(); err != nil {
+		return time.Time{}, err
+	}
+	if !m.done {
+		return time.Time{}, ErrCanceled
+	}
+	return m.date, nil
+}
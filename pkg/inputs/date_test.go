@@ -0,0 +1,77 @@
+package inputs
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDatePicker(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		msgs []tea.Msg
+		want string
+		done bool
+	}{
+		{name: "enter keeps the date", msgs: []tea.Msg{key(tea.KeyEnter)}, want: "2024-01-31", done: true},
+		{name: "next day", msgs: []tea.Msg{key(tea.KeyRight), key(tea.KeyEnter)}, want: "2024-02-01", done: true},
+		{name: "previous day", msgs: []tea.Msg{key(tea.KeyLeft), key(tea.KeyEnter)}, want: "2024-01-30", done: true},
+		{name: "next week", msgs: []tea.Msg{key(tea.KeyDown), key(tea.KeyEnter)}, want: "2024-02-07", done: true},
+		{name: "previous week", msgs: []tea.Msg{key(tea.KeyUp), key(tea.KeyEnter)}, want: "2024-01-24", done: true},
+		{name: "next month in a leap year", msgs: []tea.Msg{key(tea.KeyPgDown), key(tea.KeyEnter)}, want: "2024-02-29", done: true},
+		{name: "previous month", msgs: []tea.Msg{key(tea.KeyPgUp), key(tea.KeyEnter)}, want: "2023-12-31", done: true},
+		{name: "next year", msgs: []tea.Msg{runes("]"), key(tea.KeyEnter)}, want: "2025-01-31", done: true},
+		{name: "previous year", msgs: []tea.Msg{runes("["), key(tea.KeyEnter)}, want: "2023-01-31", done: true},
+		{name: "today", msgs: []tea.Msg{runes("]"), runes("t"), key(tea.KeyEnter)}, want: time.Now().Format(time.DateOnly), done: true},
+		{name: "canceled", msgs: []tea.Msg{key(tea.KeyRight), key(tea.KeyEsc)}, want: "2024-02-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDatePicker(start)
+			cmd := send(d, tt.msgs...)
+			if got := d.date.Format(time.DateOnly); got != tt.want {
+				t.Errorf("date = %s, want %s", got, tt.want)
+			}
+
+			if d.done != tt.done {
+				t.Errorf("done = %v, want %v", d.done, tt.done)
+			}
+
+			if !quits(cmd) {
+				t.Errorf("the date picker did not quit")
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		date string
+		n    int
+		want string
+	}{
+		{date: "2024-01-15", n: 1, want: "2024-02-15"},
+		{date: "2024-01-31", n: 1, want: "2024-02-29"},
+		{date: "2023-01-31", n: 1, want: "2023-02-28"},
+		{date: "2024-03-31", n: -1, want: "2024-02-29"},
+		{date: "2024-12-31", n: 2, want: "2025-02-28"},
+		{date: "2024-02-29", n: 12, want: "2025-02-28"},
+		{date: "2024-05-31", n: -5, want: "2023-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			date, err := time.ParseInLocation(time.DateOnly, tt.date, time.Local)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := addMonths(date, tt.n).Format(time.DateOnly); got != tt.want {
+				t.Errorf("addMonths(%s, %d) = %s, want %s", tt.date, tt.n, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+// inputs form functions
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Field struct represents a step of a form.
+type Field struct {
+	Key         string                                             // key of the value in the result of the form
+	Title       string                                             // question asked to the user
+	Description string                                             // optional text shown before the question
+	Placeholder string                                             // text shown when the input is empty
+	Default     string                                             // value used when the input is empty
+	Validate    func(string) error                                 // optional validation of the value
+	Check       func(value string, values map[string]string) error // optional validation of the value, given the previous values
+	Skip        func(values map[string]string) bool                // optional condition to skip the step, given the previous values
+}
+
+// Form struct represents a form, a sequence of fields asked one at a time, followed by a review of the values.
+type Form struct {
+	Title  string
+	Fields []Field
+	Values map[string]string // optional values known before the form, given to Check and Skip with the answers, a field with a value starts with it
+}
+
+// form struct is the model of a running form.
+// step is the index of the current field, len(fields) is the review screen.
+type form struct {
+	Form
+	in       textinput.Model
+	values   map[string]string
+	step     int
+	review   int // cursor of the review screen, len(active) is the confirm line
+	editing  bool
+	err      error
+	done     bool
+	canceled bool
+}
+
+// newForm function returns a running form at its first step.
+func newForm(f Form) *form {
+	ti := textinput.New()
+	ti.CharLimit = 156
+	ti.Focus()
+	m := &form{Form: f, in: ti, values: maps.Clone(f.Values), step: -1}
+	if m.values == nil {
+		m.values = map[string]string{}
+	}
+	m.next()
+	return m
+}
+
+// skipped function returns true if the field at index i is skipped, given the current values.
+func (f *form) skipped(i int) bool {
+	return f.Fields[i].Skip != nil && f.Fields[i].Skip(f.values)
+}
+
+// active function returns the indexes of the fields not skipped.
+func (f *form) active() []int {
+	indexes := []int{}
+	for i := range f.Fields {
+		if !f.skipped(i) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// show function moves to the field at index i, or to the review screen if i is len(fields).
+func (f *form) show(i int) {
+	f.step, f.err = i, nil
+	if i >= len(f.Fields) {
+		f.review = min(f.review, len(f.active()))
+		f.in.Blur()
+		return
+	}
+
+	field := f.Fields[i]
+	f.in.Placeholder = field.Placeholder
+	if field.Default != "" {
+		f.in.Placeholder = field.Default
+	}
+	f.in.SetValue(f.values[field.Key])
+	f.in.CursorEnd()
+	f.in.Focus()
+}
+
+// next function moves to the next field not skipped, or to the review screen.
+// while a field is edited from the review screen, it goes back to the review screen,
+// unless the edit activated a field not answered yet.
+func (f *form) next() {
+	if f.editing {
+		f.editing = false
+		for _, i := range f.active() {
+			if _, ok := f.values[f.Fields[i].Key]; !ok {
+				f.show(i)
+				return
+			}
+		}
+		f.show(len(f.Fields))
+		return
+	}
+
+	i := f.step + 1
+	for i < len(f.Fields) && f.skipped(i) {
+		i++
+	}
+	f.show(i)
+}
+
+// prev function moves to the previous field not skipped, it stays on the first one.
+func (f *form) prev() {
+	i := f.step - 1
+	for i >= 0 && f.skipped(i) {
+		i--
+	}
+	if i >= 0 {
+		f.editing = false
+		f.show(i)
+	}
+}
+
+// submit function validates the value of the current field and stores it.
+// an empty value is replaced by the default of the field.
+func (f *form) submit() bool {
+	field := f.Fields[f.step]
+	value := strings.TrimSpace(f.in.Value())
+	if value == "" {
+		value = field.Default
+	}
+
+	if field.Validate != nil {
+		err := field.Validate(value)
+		if err != nil {
+			f.err = err
+			return false
+		}
+	}
+
+	if field.Check != nil {
+		err := field.Check(value, f.values)
+		if err != nil {
+			f.err = err
+			return false
+		}
+	}
+
+	f.values[field.Key] = value
+	return true
+}
+
+// Init function initializes the form.
+func (f *form) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update function updates the form based on the message received.
+// enter and tab confirm the value and go to the next field, shift+tab goes back to the previous one.
+// in the review screen enter edits the field under the cursor, or submits the form on the confirm line.
+func (f *form) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if f.step < len(f.Fields) {
+			f.in, cmd = f.in.Update(msg)
+		}
+		return f, cmd
+	}
+
+	switch key.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		f.canceled = true
+		return f, tea.Quit
+	case tea.KeyShiftTab:
+		if f.step >= len(f.Fields) {
+			f.editing = false
+			f.step = len(f.Fields)
+		}
+		f.prev()
+		return f, nil
+	}
+
+	if f.step >= len(f.Fields) {
+		return f.updateReview(key)
+	}
+
+	switch key.Type {
+	case tea.KeyEnter, tea.KeyTab:
+		if f.submit() {
+			f.next()
+		}
+		return f, nil
+	}
+
+	f.err = nil
+	f.in, cmd = f.in.Update(msg)
+	return f, cmd
+}
+
+// updateReview function updates the review screen based on the key pressed.
+func (f *form) updateReview(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	active := f.active()
+	switch key.Type {
+	case tea.KeyUp:
+		f.review = max(f.review-1, 0)
+	case tea.KeyDown:
+		f.review = min(f.review+1, len(active))
+	case tea.KeyEnter:
+		if f.review == len(active) {
+			f.done = true
+			return f, tea.Quit
+		}
+		f.editing = true
+		f.show(active[f.review])
+	}
+
+	return f, nil
+}
+
+// View function returns the current field, or the review screen, as a string.
+func (f *form) View() string {
+	if f.done || f.canceled {
+		return ""
+	}
+
+	view := ""
+	if f.Title != "" {
+		view += selectedStyle.Render(f.Title) + "\n\n"
+	}
+
+	if f.step >= len(f.Fields) {
+		return view + f.reviewView()
+	}
+
+	field := f.Fields[f.step]
+	active := f.active()
+	pos := 0
+	for pos < len(active) && active[pos] != f.step {
+		pos++
+	}
+
+	if field.Description != "" {
+		view += field.Description + "\n"
+	}
+	view += fmt.Sprintf("%s %s\n%s\n", helpStyle.Render(fmt.Sprintf("[%d/%d]", pos+1, len(active))), field.Title, f.in.View())
+	if f.err != nil {
+		view += errorStyle.Render(f.err.Error()) + "\n"
+	}
+
+	return view + "\n" + helpStyle.Render("enter: next, shift+tab: back, esc: cancel") + "\n\n"
+}
+
+// reviewView function returns the review screen: the values of the fields and the confirm line.
+func (f *form) reviewView() string {
+	view := "Review your answers:\n\n"
+	active := f.active()
+	for i, index := range active {
+		cursor := "  "
+		if i == f.review {
+			cursor = "> "
+		}
+		field := f.Fields[index]
+		view += fmt.Sprintf("%s%s %s\n", cursor, field.Title, selectedStyle.Render(f.values[field.Key]))
+	}
+
+	cursor := "  "
+	if f.review == len(active) {
+		cursor = "> "
+	}
+	view += "\n" + cursor + matchStyle.Render("Confirm") + "\n"
+	return view + "\n" + helpStyle.Render("enter: edit or confirm, shift+tab: back, esc: cancel") + "\n\n"
+}
+
+// Values function returns the values of the fields not skipped.
+func (f *form) Values() map[string]string {
+	values := map[string]string{}
+	for _, i := range f.active() {
+		values[f.Fields[i].Key] = f.values[f.Fields[i].Key]
+	}
+	return values
+}
+
+// RunForm function runs a form and returns its values, by the key of the fields.
+// the fields are asked one at a time, then the user reviews the values and can edit them before confirming.
+// the skipped fields are not in the result.
+// if the user cancels the form, it returns ErrCanceled.
+// if the user can't be asked, it returns ErrNoInput.
+func RunForm(f Form) (map[string]string, error) {
+	if len(f.Fields) == 0 {
+		return map[string]string{}, nil
+	}
+
+	for _, field := range f.Fields {
+		if field.Key == "" {
+			return nil, errors.New("form field without key: " + field.Title)
+		}
+	}
+
+	if !Interactive() {
+		return nil, ErrNoInput
+	}
+
+	m := newForm(f)
+	p := tea.NewProgram(m)
+	if _, err := p.Run(); err != nil {
+		return nil, err
+	}
+	if !m.done {
+		return nil, ErrCanceled
+	}
+	return m.Values(), nil
+}
@@ -0,0 +1,157 @@
+package inputs
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// answer function returns the messages of a value typed and confirmed with enter.
+func answer(s string) []tea.Msg {
+	return []tea.Msg{runes(s), key(tea.KeyEnter)}
+}
+
+// msgs function joins the messages of the steps of a test.
+func msgs(steps ...[]tea.Msg) []tea.Msg {
+	all := []tea.Msg{}
+	for _, s := range steps {
+		all = append(all, s...)
+	}
+	return all
+}
+
+// keys function returns the messages of a special key pressed n times.
+func keys(t tea.KeyType, n int) []tea.Msg {
+	all := []tea.Msg{}
+	for range n {
+		all = append(all, key(t))
+	}
+	return all
+}
+
+// testForm function returns a form with a required field, a default, a field skipped for the fish
+// and a field checked with the currency known before the form.
+func testForm() Form {
+	return Form{
+		Title: "New pet",
+		Fields: []Field{
+			{Key: "name", Title: "Name?", Validate: func(s string) error {
+				if s == "" {
+					return errors.New("the name is required")
+				}
+				return nil
+			}},
+			{Key: "pet", Title: "Pet?", Default: "cat"},
+			{Key: "legs", Title: "Legs?", Skip: func(values map[string]string) bool { return values["pet"] == "fish" }},
+			{Key: "budget", Title: "Budget?", Check: func(value string, values map[string]string) error {
+				if values["currency"] == "JPY" && strings.Contains(value, ".") {
+					return errors.New("JPY has no decimals")
+				}
+				return nil
+			}},
+		},
+		Values: map[string]string{"currency": "JPY"},
+	}
+}
+
+func TestForm(t *testing.T) {
+	review := 4 // the step of the review screen
+	tests := []struct {
+		name     string
+		msgs     []tea.Msg
+		step     int
+		input    string // the value in the input of the step, checked if not empty
+		invalid  bool
+		done     bool
+		canceled bool
+		values   map[string]string // the result of the form, checked if done
+	}{
+		{name: "first field", step: 0},
+		{name: "required field", msgs: []tea.Msg{key(tea.KeyEnter)}, step: 0, invalid: true},
+		{name: "default value", msgs: msgs(answer("Rex"), keys(tea.KeyEnter, 1)), step: 2},
+		{name: "tab confirms", msgs: []tea.Msg{runes("Rex"), key(tea.KeyTab)}, step: 1},
+		{name: "back to the previous field", msgs: msgs(answer("Rex"), keys(tea.KeyShiftTab, 1)), step: 0, input: "Rex"},
+		{name: "back on the first field", msgs: keys(tea.KeyShiftTab, 2), step: 0},
+		{name: "answer changed going back", msgs: msgs(answer("Rex"), keys(tea.KeyShiftTab, 1), answer("y")), step: 1},
+		{name: "skipped field", msgs: msgs(answer("Nemo"), answer("fish")), step: 3},
+		{name: "back over a skipped field", msgs: msgs(answer("Nemo"), answer("fish"), keys(tea.KeyShiftTab, 1)), step: 1, input: "fish"},
+		{name: "checked with the known values", msgs: msgs(answer("Rex"), answer("dog"), answer("4"), answer("1500.50")), step: 3, invalid: true},
+		{name: "review screen", msgs: msgs(answer("Rex"), answer("dog"), answer("4"), answer("1500")), step: review},
+		{name: "back from the review screen", msgs: msgs(answer("Nemo"), answer("fish"), answer("300"), keys(tea.KeyShiftTab, 1)), step: 3, input: "300"},
+		{
+			name: "confirmed",
+			msgs: msgs(answer("Rex"), keys(tea.KeyEnter, 1), answer("4"), answer("1500"), keys(tea.KeyDown, 5), keys(tea.KeyEnter, 1)),
+			step: review, done: true,
+			values: map[string]string{"name": "Rex", "pet": "cat", "legs": "4", "budget": "1500"},
+		},
+		{name: "edit from the review screen", msgs: msgs(answer("Rex"), answer("dog"), answer("4"), answer("1500"), keys(tea.KeyDown, 1), keys(tea.KeyEnter, 1)), step: 1, input: "dog"},
+		{
+			name: "edited answer back to the review screen",
+			msgs: msgs(answer("Rex"), answer("dog"), answer("4"), answer("1500"), keys(tea.KeyDown, 1), keys(tea.KeyEnter, 1), keys(tea.KeyBackspace, 3), answer("fish"),
+				keys(tea.KeyDown, 3), keys(tea.KeyEnter, 1)),
+			step: review, done: true,
+			values: map[string]string{"name": "Rex", "pet": "fish", "budget": "1500"},
+		},
+		{
+			name: "edit activating a field not answered",
+			msgs: msgs(answer("Nemo"), answer("fish"), answer("300"), keys(tea.KeyDown, 1), keys(tea.KeyEnter, 1), keys(tea.KeyBackspace, 4), answer("dog")),
+			step: 2,
+		},
+		{name: "canceled", msgs: msgs(answer("Rex"), keys(tea.KeyEsc, 1)), step: 1, canceled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newForm(testForm())
+			cmd := send(f, tt.msgs...)
+			if f.step != tt.step {
+				t.Fatalf("step = %d, want %d", f.step, tt.step)
+			}
+
+			if tt.input != "" && f.in.Value() != tt.input {
+				t.Errorf("input = %q, want %q", f.in.Value(), tt.input)
+			}
+
+			if (f.err != nil) != tt.invalid {
+				t.Errorf("err = %v, want an error %v", f.err, tt.invalid)
+			}
+
+			if f.done != tt.done || f.canceled != tt.canceled {
+				t.Errorf("done = %v, canceled = %v, want %v, %v", f.done, f.canceled, tt.done, tt.canceled)
+			}
+
+			if (tt.done || tt.canceled) && !quits(cmd) {
+				t.Error("the form did not quit")
+			}
+
+			if tt.done && !maps.Equal(f.Values(), tt.values) {
+				t.Errorf("Values() = %v, want %v", f.Values(), tt.values)
+			}
+		})
+	}
+}
+
+func TestFormView(t *testing.T) {
+	f := newForm(testForm())
+	send(f, msgs(answer("Nemo"), answer("fish"))...)
+
+	// the skipped field is not counted
+	if view := f.View(); !strings.Contains(view, "[3/3]") || !strings.Contains(view, "Budget?") {
+		t.Errorf("view %q, want the budget as the third of three fields", view)
+	}
+
+	send(f, answer("300")...)
+	view := f.View()
+	for _, want := range []string{"Review your answers", "Name?", "Nemo", "fish", "300", "Confirm"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("review %q does not contain %q", view, want)
+		}
+	}
+
+	if strings.Contains(view, "Legs?") {
+		t.Errorf("review %q contains the skipped field", view)
+	}
+}
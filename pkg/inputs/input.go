@@ -2,10 +2,8 @@
 package inputs
 
 import (
-	"aio/pkg/log"
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -13,6 +11,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// ErrCanceled is returned when the user cancels an input with ctrl+c or esc.
+var ErrCanceled = errors.New("input canceled by the user")
+
 // input struct represents an input field.
 type input struct {
 	in         textinput.Model
@@ -76,7 +77,13 @@ func (i *input) View() string {
 // RunInputWithValidation function initializes the input field and returns the value.
 // It takes a string as input placeholder and a validation function and returns a string.
 // the validation function takes a string as input and returns an error.
-func RunInputWithValidation(ph string, validation func(string) error) string {
+// if the user cancels the input, it returns ErrCanceled.
+// if the user can't be asked, it returns ErrNoInput.
+func RunInputWithValidation(ph string, validation func(string) error) (string, error) {
+	if !Interactive() {
+		return "", ErrNoInput
+	}
+
 	ti := textinput.New()
 	ti.Placeholder = ph
 	ti.Focus()
@@ -86,23 +93,25 @@ func RunInputWithValidation(ph string, validation func(string) error) string {
 	m := input{in: ti, normStyle: norms, errStyle: errs, validation: validation, ph: ph, done: false, err: nil}
 	p := tea.NewProgram(&m)
 	if _, err := p.Run(); err != nil {
-		log.Fat(err)
+		return "", err
 	}
 	if !m.done {
-		os.Exit(0)
+		return "", ErrCanceled
+	}
+	return m.in.Value(), nil
+}
+
+// NotEmpty function is a validation function that returns an error if the value is empty.
+func NotEmpty(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("please enter a value")
 	}
-	return m.in.Value()
+	return nil
 }
 
 // RunInput function initializes the input field and returns the value.
 // It takes a string as input placeholder and returns a string.
 // the value can't be empty.
-func RunInput(ph string) string {
-	validation := func(s string) error {
-		if strings.TrimSpace(s) == "" {
-			return errors.New("please enter a value")
-		}
-		return nil
-	}
-	return RunInputWithValidation(ph, validation)
+func RunInput(ph string) (string, error) {
+	return RunInputWithValidation(ph, NotEmpty)
 }
@@ -0,0 +1,34 @@
+package inputs
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// send function sends the messages to a model in order, like the keys pressed by the user, and returns the last command.
+func send(m tea.Model, msgs ...tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	for _, msg := range msgs {
+		m, cmd = m.Update(msg)
+	}
+	return cmd
+}
+
+// key function returns the message of a special key, like enter or the arrows.
+func key(t tea.KeyType) tea.Msg {
+	return tea.KeyMsg{Type: t}
+}
+
+// runes function returns the message of the characters typed by the user.
+func runes(s string) tea.Msg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// quits function returns true if the command quits the program.
+// it must be called only on the commands of the keys that end the input, the other ones may wait for a timer.
+func quits(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
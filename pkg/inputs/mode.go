@@ -0,0 +1,38 @@
+// inputs mode functions
+package inputs
+
+import (
+	"errors"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ErrNoInput is returned when an input is required but the user can't be asked,
+// because the input is disabled or the standard input is not a terminal.
+var ErrNoInput = errors.New("input required, but the prompts are disabled or the input is not a terminal")
+
+// the input mode, read from the environment when the program starts:
+// AIO_NO_INPUT disables the prompts, AIO_YES answers yes to the confirmations.
+var (
+	noInput = os.Getenv("AIO_NO_INPUT") != ""
+	yes     = os.Getenv("AIO_YES") != ""
+)
+
+// SetNoInput function disables the prompts, if n is true.
+// the inputs return ErrNoInput instead of asking the user.
+func SetNoInput(n bool) {
+	noInput = noInput || n
+}
+
+// SetYes function answers yes to every confirmation, without asking the user, if y is true.
+func SetYes(y bool) {
+	yes = yes || y
+}
+
+// Interactive function returns true if the user can be asked for an input:
+// the prompts are enabled and the standard input is a terminal.
+func Interactive() bool {
+	fd := os.Stdin.Fd()
+	return !noInput && (isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd))
+}
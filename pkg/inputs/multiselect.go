@@ -0,0 +1,128 @@
+// inputs multi select functions
+package inputs
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// multiSelect struct represents a select field with a checkbox for every option.
+type multiSelect struct {
+	index    int
+	options  []string
+	selected map[int]bool
+	done     bool
+}
+
+// newMultiSelect function returns a multi select field, with the options at the given indexes already checked.
+func newMultiSelect(options []string, checked []int) *multiSelect {
+	m := &multiSelect{options: options, selected: map[int]bool{}}
+	for _, i := range checked {
+		if i >= 0 && i < len(options) {
+			m.selected[i] = true
+		}
+	}
+	return m
+}
+
+// Init function initializes the multi select field.
+func (m *multiSelect) Init() tea.Cmd {
+	return nil
+}
+
+// Update function updates the multi select field based on the message received.
+// space checks or unchecks the option under the cursor, a checks or unchecks every option.
+func (m *multiSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			m.done = false
+			return m, tea.Quit
+		case tea.KeyUp:
+			if m.index > 0 {
+				m.index--
+			}
+		case tea.KeyDown:
+			if m.index < len(m.options)-1 {
+				m.index++
+			}
+		case tea.KeySpace:
+			m.selected[m.index] = !m.selected[m.index]
+		case tea.KeyEnter:
+			m.done = true
+			return m, tea.Quit
+		}
+
+		switch msg.String() {
+		case "k":
+			if m.index > 0 {
+				m.index--
+			}
+		case "j":
+			if m.index < len(m.options)-1 {
+				m.index++
+			}
+		case "a":
+			all := len(m.Selected()) < len(m.options)
+			for i := range m.options {
+				m.selected[i] = all
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// View function returns the multi select field as a string.
+func (m *multiSelect) View() string {
+	view := ""
+	for i, option := range m.options {
+		cursor := "  "
+		if i == m.index {
+			cursor = "> "
+		}
+
+		box := "[ ]"
+		if m.selected[i] {
+			box = "[x]"
+			option = selectedStyle.Render(option)
+		}
+
+		view += fmt.Sprintf("%s%s %s\n", cursor, box, option)
+	}
+
+	return view + "\n" + helpStyle.Render("space: check, a: check all, enter: confirm") + "\n\n"
+}
+
+// Selected function returns the indexes of the checked options, in the order of the options.
+func (m *multiSelect) Selected() []int {
+	indexes := []int{}
+	for i := range m.options {
+		if m.selected[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// RunMultiSelect function initializes the multi select field and returns the indexes of the checked options.
+// It takes a slice of strings as input options and the indexes of the options checked at the start.
+// if the user cancels the input, it returns ErrCanceled.
+// if the user can't be asked, it returns ErrNoInput.
+func RunMultiSelect(options []string, checked []int) ([]int, error) {
+	if !Interactive() {
+		return nil, ErrNoInput
+	}
+
+	m := newMultiSelect(options, checked)
+	p := tea.NewProgram(m)
+	if _, err := p.Run(); err != nil {
+		return nil, err
+	}
+	if !m.done {
+		return nil, ErrCanceled
+	}
+	return m.Selected(), nil
+}
@@ -0,0 +1,47 @@
+package inputs
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultiSelect(t *testing.T) {
+	options := []string{"groceries", "rent", "fun"}
+	tests := []struct {
+		name    string
+		checked []int
+		msgs    []tea.Msg
+		want    []int
+		done    bool
+	}{
+		{name: "initial checks", checked: []int{2, 0}, msgs: []tea.Msg{key(tea.KeyEnter)}, want: []int{0, 2}, done: true},
+		{name: "invalid initial checks", checked: []int{-1, 3}, msgs: []tea.Msg{key(tea.KeyEnter)}, done: true},
+		{name: "check with space", msgs: []tea.Msg{key(tea.KeyDown), key(tea.KeySpace), key(tea.KeyEnter)}, want: []int{1}, done: true},
+		{name: "uncheck with space", checked: []int{0, 1}, msgs: []tea.Msg{key(tea.KeySpace), key(tea.KeyEnter)}, want: []int{1}, done: true},
+		{name: "cursor stops at the ends", msgs: []tea.Msg{key(tea.KeyUp), key(tea.KeySpace), key(tea.KeyDown), key(tea.KeyDown), key(tea.KeyDown), key(tea.KeySpace), key(tea.KeyEnter)}, want: []int{0, 2}, done: true},
+		{name: "vim keys", msgs: []tea.Msg{runes("j"), runes("j"), runes("k"), key(tea.KeySpace), key(tea.KeyEnter)}, want: []int{1}, done: true},
+		{name: "check all", checked: []int{1}, msgs: []tea.Msg{runes("a"), key(tea.KeyEnter)}, want: []int{0, 1, 2}, done: true},
+		{name: "uncheck all", msgs: []tea.Msg{runes("a"), runes("a"), key(tea.KeyEnter)}, done: true},
+		{name: "canceled", checked: []int{0}, msgs: []tea.Msg{key(tea.KeyEsc)}, want: []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMultiSelect(options, tt.checked)
+			cmd := send(m, tt.msgs...)
+			if got := m.Selected(); !slices.Equal(got, tt.want) {
+				t.Errorf("Selected() = %v, want %v", got, tt.want)
+			}
+
+			if m.done != tt.done {
+				t.Errorf("done = %v, want %v", m.done, tt.done)
+			}
+
+			if !quits(cmd) {
+				t.Errorf("the multi select did not quit")
+			}
+		})
+	}
+}
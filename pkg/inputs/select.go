@@ -2,127 +2,194 @@
 package inputs
 
 import (
-	"aio/pkg/log"
 	"fmt"
-	"math"
-	"os"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
 )
 
+// lines of the select field that are not options: the filter, the page and the help
+const selectChrome = 7
+
 // selectField struct represents a select field.
+// the options are filtered with a fuzzy search while the user types, the best matches first,
+// and shown a page at a time. the page size follows the height of the terminal.
 type selectField struct {
-	index   int
-	page    int
-	options []string
-	done    bool
+	in       textinput.Model
+	options  []string
+	matches  fuzzy.Matches
+	index    int
+	pageSize int
+	done     bool
+}
+
+// newSelectField function returns a select field with the given options, showing pageSize options at a time.
+func newSelectField(options []string, pageSize int) *selectField {
+	ti := textinput.New()
+	ti.Placeholder = "type to filter"
+	ti.Prompt = "/ "
+	ti.Focus()
+	s := &selectField{in: ti, options: options, pageSize: max(pageSize, 1)}
+	s.filter()
+	return s
+}
+
+// filter function updates the matches with the current filter and moves the cursor to the first match.
+// without a filter every option matches, in the original order.
+func (s *selectField) filter() {
+	query := strings.TrimSpace(s.in.Value())
+	if query == "" {
+		s.matches = make(fuzzy.Matches, len(s.options))
+		for i, o := range s.options {
+			s.matches[i] = fuzzy.Match{Str: o, Index: i}
+		}
+	} else {
+		s.matches = fuzzy.Find(query, s.options)
+	}
+
+	s.index = 0
+}
+
+// move function moves the cursor by n options, staying between the first and the last match.
+func (s *selectField) move(n int) {
+	s.index = min(max(s.index+n, 0), max(len(s.matches)-1, 0))
 }
 
 // Init function initializes the select field.
 func (s *selectField) Init() tea.Cmd {
-	return nil
+	return textinput.Blink
 }
 
 // Update function updates the select field based on the message received.
+// up and down move by an option, left, right, page up and page down by a page, home and end to the first and last option.
+// the other keys edit the filter.
 func (s *selectField) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	itemsxpage := 10
-	numofpages := int(math.Ceil(float64(len(s.options)) / float64(itemsxpage)))
+	var cmd tea.Cmd
+
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.pageSize = max(msg.Height-selectChrome, 1)
+		return s, nil
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
 			s.done = false
 			return s, tea.Quit
 		case tea.KeyUp:
-			if s.index%itemsxpage == 0 {
-				if s.page > 1 {
-					s.page--
-					s.index--
-				}
-			} else {
-				s.index--
-			}
-
-			if s.index < 0 {
-				s.index = 0
-			}
+			s.move(-1)
+			return s, nil
 		case tea.KeyDown:
-			if s.index%itemsxpage == itemsxpage-1 {
-				if s.page < numofpages {
-					s.page++
-					s.index++
-				}
-			} else {
-				s.index++
-			}
-
-			if s.index >= len(s.options) {
-				s.index = len(s.options) - 1
-			}
-		case tea.KeyLeft:
-			if s.page > 1 {
-				s.page--
-				s.index -= itemsxpage
-			}
-
-			if s.index < 0 {
-				s.index = 0
-			}
-		case tea.KeyRight:
-			if s.page < numofpages {
-				s.page++
-				s.index += itemsxpage
-			}
-
-			if s.index >= len(s.options) {
-				s.index = len(s.options) - 1
-			}
+			s.move(1)
+			return s, nil
+		case tea.KeyLeft, tea.KeyPgUp:
+			s.move(-s.pageSize)
+			return s, nil
+		case tea.KeyRight, tea.KeyPgDown:
+			s.move(s.pageSize)
+			return s, nil
+		case tea.KeyHome:
+			s.index = 0
+			return s, nil
+		case tea.KeyEnd:
+			s.move(len(s.matches))
+			return s, nil
 		case tea.KeyEnter:
+			if len(s.matches) == 0 {
+				return s, nil
+			}
 			s.done = true
 			return s, tea.Quit
 		}
 	}
 
-	return s, nil
+	value := s.in.Value()
+	s.in, cmd = s.in.Update(msg)
+	if s.in.Value() != value {
+		s.filter()
+	}
+
+	return s, cmd
 }
 
-// View function returns the select field as a string.
+// View function returns the select field as a string, with the matched characters highlighted.
 func (s *selectField) View() string {
-	view := ""
-	itemsxpage := 10
-	numofpages := int(math.Ceil(float64(len(s.options)) / float64(itemsxpage)))
-	start := (s.page - 1) * itemsxpage
-	end := s.page * itemsxpage
-
-	if end > len(s.options) {
-		end = len(s.options)
+	view := s.in.View() + "\n\n"
+	if len(s.matches) == 0 {
+		return view + helpStyle.Render("no matches") + "\n\n"
 	}
 
-	for i, option := range s.options[start:end] {
+	page := s.index / s.pageSize
+	pages := (len(s.matches) + s.pageSize - 1) / s.pageSize
+	start := page * s.pageSize
+	end := min(start+s.pageSize, len(s.matches))
+
+	for i, m := range s.matches[start:end] {
 		cursor := "  "
-		if s.index%itemsxpage == i {
+		if start+i == s.index {
 			cursor = "> "
 		}
-		view += fmt.Sprintf("%s%s\n", cursor, option)
+		view += fmt.Sprintf("%s%s\n", cursor, highlight(m.Str, m.MatchedIndexes))
+	}
+
+	info := fmt.Sprintf("%d/%d", len(s.matches), len(s.options))
+	if pages > 1 {
+		info = fmt.Sprintf("Page %d/%d, %s", page+1, pages, info)
+	}
+
+	return view + "\n" + helpStyle.Render(info+", pgup/pgdn: page, home/end: first/last") + "\n\n"
+}
+
+// highlight function returns s with the bytes at the given indexes rendered with the match style.
+func highlight(s string, indexes []int) string {
+	if len(indexes) == 0 {
+		return s
+	}
+
+	matched := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		matched[i] = true
 	}
 
-	if numofpages > 1 {
-		view += fmt.Sprintf("\nPage %d/%d\n\n", s.page, numofpages)
+	var b strings.Builder
+	for i, r := range s {
+		if matched[i] {
+			b.WriteString(matchStyle.Render(string(r)))
+			continue
+		}
+		b.WriteRune(r)
 	}
+	return b.String()
+}
 
-	return view
+// Selected function returns the index in the options and the value of the option under the cursor.
+// it returns -1 if no option matches the filter.
+func (s *selectField) Selected() (int, string) {
+	if len(s.matches) == 0 {
+		return -1, ""
+	}
+	m := s.matches[s.index]
+	return m.Index, m.Str
 }
 
-// RunSelect function initializes the select field and returns the value.
-// It takes a slice of strings as input options and returns a string.
-func RunSelect(options []string) string {
-	m := selectField{index: 0, page: 1, options: options, done: false}
-	p := tea.NewProgram(&m)
+// RunSelect function initializes the select field and returns the index and the value of the chosen option.
+// It takes a slice of strings as input options, filtered with a fuzzy search while the user types.
+// if the user cancels the input, it returns ErrCanceled.
+// if the user can't be asked, it returns ErrNoInput.
+func RunSelect(options []string) (int, string, error) {
+	if !Interactive() {
+		return -1, "", ErrNoInput
+	}
+
+	m := newSelectField(options, 10)
+	p := tea.NewProgram(m)
 	if _, err := p.Run(); err != nil {
-		log.Fat(err)
+		return -1, "", err
 	}
 	if !m.done {
-		os.Exit(0)
+		return -1, "", ErrCanceled
 	}
-	return m.options[m.index]
+	i, v := m.Selected()
+	return i, v, nil
 }
@@ -0,0 +1,91 @@
+package inputs
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSelectFilter(t *testing.T) {
+	options := []string{"transport", "restaurants", "rent", "entertainment", "groceries"}
+	tests := []struct {
+		name  string
+		msgs  []tea.Msg
+		want  []string // the matches in order
+		index int      // the index in the options of the selected one, -1 if none
+		done  bool
+	}{
+		{name: "no filter in order", want: options, index: 0},
+		{name: "best match first", msgs: []tea.Msg{runes("rent")}, want: []string{"rent", "restaurants", "entertainment"}, index: 2},
+		{name: "cursor back to the first match", msgs: []tea.Msg{key(tea.KeyDown), key(tea.KeyDown), runes("gro")}, want: []string{"groceries"}, index: 4},
+		{name: "filter removed", msgs: []tea.Msg{runes("gro"), key(tea.KeyBackspace), key(tea.KeyBackspace), key(tea.KeyBackspace)}, want: options, index: 0},
+		{name: "no match", msgs: []tea.Msg{runes("xyz"), key(tea.KeyEnter)}, want: []string{}, index: -1},
+		{name: "chosen", msgs: []tea.Msg{runes("rent"), key(tea.KeyDown), key(tea.KeyEnter)}, want: []string{"rent", "restaurants", "entertainment"}, index: 1, done: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSelectField(options, 10)
+			send(s, tt.msgs...)
+
+			got := []string{}
+			for _, m := range s.matches {
+				got = append(got, m.Str)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("matches %q, want %q", got, tt.want)
+			}
+
+			if i, _ := s.Selected(); i != tt.index {
+				t.Errorf("Selected() = %d, want %d", i, tt.index)
+			}
+
+			if s.done != tt.done {
+				t.Errorf("done = %v, want %v", s.done, tt.done)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	// the matched characters in brackets, the styles are not rendered without a terminal
+	style := matchStyle
+	matchStyle = lipgloss.NewStyle().Transform(func(s string) string { return "[" + s + "]" })
+	t.Cleanup(func() { matchStyle = style })
+
+	tests := []struct {
+		label string
+		query string
+		want  string
+	}{
+		{label: "groceries", query: "gro", want: "[g][r][o]ceries"},
+		{label: "Crème brûlée", query: "cb", want: "[C]rème [b]rûlée"},
+		{label: "Crème brûlée", query: "èû", want: "Cr[è]me br[û]lée"},
+		{label: "Crème brûlée", query: "lée", want: "Crème brû[l][é][e]"},
+		{label: "Café", query: "fé", want: "Ca[f][é]"},
+		{label: "日本円", query: "本円", want: "日[本][円]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label+" "+tt.query, func(t *testing.T) {
+			s := newSelectField([]string{tt.label}, 10)
+			send(s, runes(tt.query))
+			if len(s.matches) != 1 {
+				t.Fatalf("%q does not match %q", tt.query, tt.label)
+			}
+
+			// the indexes of the matches are byte offsets, every one is the start of a character
+			m := s.matches[0]
+			if got := highlight(m.Str, m.MatchedIndexes); got != tt.want {
+				t.Errorf("highlight(%q, %v) = %q, want %q", m.Str, m.MatchedIndexes, got, tt.want)
+			}
+		})
+	}
+
+	if got := highlight("Café", nil); got != "Café" {
+		t.Errorf("highlight without matches = %q", got)
+	}
+}
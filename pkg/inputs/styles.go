@@ -0,0 +1,19 @@
+// inputs styles
+package inputs
+
+import "github.com/charmbracelet/lipgloss"
+
+// styles of the widgets
+var (
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true)
+	matchStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// SetColors function sets the colors of the widgets, as ANSI codes (0-255) or hex codes (#ff8800).
+func SetColors(bright, err, change string) {
+	selectedStyle = selectedStyle.Foreground(lipgloss.Color(bright))
+	errorStyle = errorStyle.Foreground(lipgloss.Color(err))
+	matchStyle = matchStyle.Foreground(lipgloss.Color(change))
+}
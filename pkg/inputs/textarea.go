@@ -0,0 +1,79 @@
+// inputs textarea functions
+package inputs
+
+import (
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// textArea struct represents a multi line input field, for notes and descriptions.
+type textArea struct {
+	in   textarea.Model
+	done bool
+}
+
+// newTextArea function returns a text area with the given placeholder and initial value.
+func newTextArea(ph, value string) *textArea {
+	ta := textarea.New()
+	ta.Placeholder = ph
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetWidth(72)
+	ta.SetHeight(8)
+	ta.SetValue(value)
+	ta.Focus()
+	return &textArea{in: ta}
+}
+
+// Init function initializes the text area.
+func (t *textArea) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+// Update function updates the text area based on the message received.
+// enter adds a new line, ctrl+d confirms the text.
+func (t *textArea) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			t.done = false
+			t.in.Blur()
+			return t, tea.Quit
+		case tea.KeyCtrlD:
+			t.done = true
+			t.in.Blur()
+			return t, tea.Quit
+		}
+	}
+
+	t.in, cmd = t.in.Update(msg)
+	return t, cmd
+}
+
+// View function returns the text area as a string.
+func (t *textArea) View() string {
+	return t.in.View() + "\n" + helpStyle.Render("ctrl+d: confirm, esc: cancel") + "\n\n"
+}
+
+// RunTextarea function initializes the text area and returns the text.
+// It takes a string as input placeholder and the initial text, to edit an existing note.
+// if the user cancels the input, it returns ErrCanceled.
+// if the user can't be asked, it returns ErrNoInput.
+func RunTextarea(ph, value string) (string, error) {
+	if !Interactive() {
+		return "", ErrNoInput
+	}
+
+	m := newTextArea(ph, value)
+	p := tea.NewProgram(m)
+	if _, err := p.Run(); err != nil {
+		return "", err
+	}
+	if !m.done {
+		return "", ErrCanceled
+	}
+	return m.in.Value(), nil
+}
@@ -0,0 +1,40 @@
+package inputs
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTextArea(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		msgs  []tea.Msg
+		want  string
+		done  bool
+	}{
+		{name: "initial value", value: "buy milk", msgs: []tea.Msg{key(tea.KeyCtrlD)}, want: "buy milk", done: true},
+		{name: "typed text", value: "buy milk", msgs: []tea.Msg{runes(" and eggs"), key(tea.KeyCtrlD)}, want: "buy milk and eggs", done: true},
+		{name: "enter adds a line", msgs: []tea.Msg{runes("one"), key(tea.KeyEnter), runes("two"), key(tea.KeyCtrlD)}, want: "one\ntwo", done: true},
+		{name: "canceled", value: "buy milk", msgs: []tea.Msg{runes("!"), key(tea.KeyEsc)}, want: "buy milk!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTextArea("", tt.value)
+			cmd := send(ta, tt.msgs...)
+			if got := ta.in.Value(); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+
+			if ta.done != tt.done {
+				t.Errorf("done = %v, want %v", ta.done, tt.done)
+			}
+
+			if !quits(cmd) {
+				t.Errorf("the text area did not quit")
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// jobs package, budgets job
+package jobs
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// budgets function applies the penalties of the budgets overspent by the character of every profile,
+// showing a desktop notification for every penalty.
+func budgets(j Job) error {
+	now := time.Now()
+	return db.ForEachProfile(func(db.Profile) error {
+		return penalize(now)
+	})
+}
+
+// penalize function applies the penalties of the budgets overspent by the character of the current profile.
+func penalize(now time.Time) error {
+	penalties, err := db.BudgetPenalize(now)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, p := range penalties {
+		log.Info("budget overspent", "category", p.Category, "month", p.Month.Format("2006-01"), "overspent", money.Format(p.Overspent, p.Currency), "currency", p.Currency)
+		err = beeep.Notify("aio: budget overspent", PenaltyMessage(p), "")
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// PenaltyMessage function returns the description of a budget penalty, like
+// "You overspent the groceries budget of October by 12.30 EUR: -10 HP, -1 karma."
+func PenaltyMessage(p db.Penalty) string {
+	message := "You overspent the " + p.Category + " budget of " + p.Month.Format("January") + " by " + money.Format(p.Overspent, p.Currency) + " " + p.Currency
+	if p.HP > 0 {
+		message += ", -" + strconv.Itoa(p.HP) + " HP"
+	}
+	if p.Karma > 0 {
+		message += ", -" + strconv.Itoa(p.Karma) + " karma"
+	}
+	return message + "."
+}
@@ -0,0 +1,19 @@
+// jobs package, clean logs job
+package jobs
+
+import (
+	"aio/pkg/log"
+)
+
+// cleanLogs function applies the retention policy to the log files:
+// the rotated files are compressed and the ones older than the retention are deleted.
+func cleanLogs(j Job) error {
+	log.Deb("cleaning logs directory...")
+	err := log.Clean()
+	if err != nil {
+		log.Err("failed to clean the logs directory")
+		return err
+	}
+
+	return nil
+}
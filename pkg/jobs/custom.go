@@ -0,0 +1,80 @@
+// jobs package, user defined jobs
+package jobs
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/cmd"
+	"aio/pkg/utils/fs"
+	"aio/pkg/utils/money"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/gen2brain/beeep"
+)
+
+// command function runs the shell command of the job in the data directory.
+func command(j Job) error {
+	line := j.Command
+	var output []byte
+	var err error
+	if runtime.GOOS == "windows" {
+		output, err = cmd.Output("cmd", "/C", line)
+	} else {
+		output, err = cmd.Output("sh", "-c", line)
+	}
+
+	if err != nil {
+		log.Err("failed to run command", "command", line, "output", string(output))
+		return errors.Join(errors.New("failed to run command: "+line), err)
+	}
+
+	log.Info("command executed", "command", line, "output", string(output))
+	return nil
+}
+
+// reminder function shows a desktop notification with the message of the job.
+func reminder(j Job) error {
+	err := beeep.Notify("aio: "+j.Name, j.Message, "")
+	if err != nil {
+		log.Err("failed to show the reminder", "job", j.Name)
+		return err
+	}
+
+	return nil
+}
+
+// backup function creates a backup of the database.
+func backup(j Job) error {
+	return fs.Backup()
+}
+
+// report function shows a desktop notification with the stats of the character of every profile.
+func report(j Job) error {
+	return db.ForEachProfile(func(db.Profile) error {
+		return reportChar()
+	})
+}
+
+// reportChar function shows a desktop notification with the stats of the character of the current profile.
+func reportChar() error {
+	c, err := db.CharGet()
+	if err != nil {
+		log.Err("failed to get the character")
+		return err
+	}
+
+	message := fmt.Sprintf(
+		"Level %d, XP %d/%d, HP %d/%d, PP %d/%d, %d coins, balance %s %s",
+		c.Level, c.XP, c.NextLevelXP, c.HP, c.MaxHP, c.PP, c.MaxPP, c.Coins, money.Format(c.Balance, c.Currency), c.Currency,
+	)
+
+	err = beeep.Notify("aio: "+c.NickName+" report", message, "")
+	if err != nil {
+		log.Err("failed to show the report")
+		return err
+	}
+
+	return nil
+}
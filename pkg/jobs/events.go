@@ -0,0 +1,37 @@
+// jobs package, events job
+package jobs
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"errors"
+
+	"github.com/gen2brain/beeep"
+)
+
+// events function celebrates the birthday and the other yearly events of the character of every profile,
+// showing a desktop notification for every event of today not yet notified.
+func events(j Job) error {
+	return db.ForEachProfile(func(db.Profile) error {
+		return celebrate()
+	})
+}
+
+// celebrate function notifies the events of today of the character of the current profile.
+func celebrate() error {
+	celebrated, err := db.EventsNotify()
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, e := range celebrated {
+		log.Info("notifying event", "kind", e.Kind, "years", e.Years)
+		err = beeep.Notify("aio: "+e.Title(), "Your gift is ready: "+e.Gift()+".", "")
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
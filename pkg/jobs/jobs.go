@@ -0,0 +1,258 @@
+// jobs package provides the registry of the jobs run by the cron service.
+package jobs
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/cmd"
+	"errors"
+	"slices"
+
+	"github.com/robfig/cron/v3"
+)
+
+// job kinds, the built in jobs have their own kind, the others can be defined by the user
+const (
+	KindBuiltin  = "builtin"  // a job defined by aio, like the push of the database
+	KindCommand  = "command"  // runs a shell command
+	KindReminder = "reminder" // shows a desktop notification with a message
+	KindBackup   = "backup"   // creates a backup of the database
+	KindReport   = "report"   // shows a desktop notification with the character stats
+)
+
+// Job struct represents a job of the cron service.
+type Job struct {
+	Name        string
+	Kind        string
+	Schedule    string
+	Enabled     *bool
+	Command     string
+	Message     string
+	Description string
+	LeadTimes   []string
+	run         func(j Job) error
+}
+
+// run triggers, recorded in the job runs history
+const (
+	TriggerSchedule = "schedule" // the run was started by the cron service
+	TriggerManual   = "manual"   // the run was started by the user with aio cron run
+)
+
+// ErrStillRunning is returned when a job is started while its previous run has not completed.
+var ErrStillRunning = errors.New("job still running")
+
+// builtins function returns the jobs defined by aio with their default settings:
+// the push runs every sync.interval and the reminders are shown at the reminders.lead_times of the config.
+func builtins(c config.Config) []Job {
+	enabled := true
+	return []Job{
+		{Name: "push", Kind: KindBuiltin, Schedule: "@every " + c.Sync.Interval, Enabled: &enabled, Description: "commit the changes and push them to the remote repository", run: push},
+		{Name: "cleanlogs", Kind: KindBuiltin, Schedule: "@every 24h", Enabled: &enabled, Description: "compress the rotated log files and delete the ones older than the retention", run: cleanLogs},
+		{Name: "reminders", Kind: KindBuiltin, Schedule: "@every 1m", Enabled: &enabled, Description: "remind the upcoming tasks and habits", LeadTimes: c.Reminders.LeadTimes, run: reminders},
+		{Name: "events", Kind: KindBuiltin, Schedule: "@every 1h", Enabled: &enabled, Description: "celebrate the birthday and the anniversaries of the character", run: events},
+		{Name: "recurring", Kind: KindBuiltin, Schedule: "@every 1h", Enabled: &enabled, Description: "record the due recurring transactions and warn the renewals of the subscriptions", run: recurring},
+		{Name: "budgets", Kind: KindBuiltin, Schedule: "@every 1h", Enabled: &enabled, Description: "apply the penalties of the overspent category budgets", run: budgets},
+	}
+}
+
+// IsEnabled function returns true if the job is enabled, the jobs are enabled by default.
+func (j Job) IsEnabled() bool {
+	return j.Enabled == nil || *j.Enabled
+}
+
+// Run function runs the job once.
+func (j Job) Run() error {
+	if j.run == nil {
+		return errors.New("job " + j.Name + " has nothing to run")
+	}
+	return j.run(j)
+}
+
+// Exec function runs the job once and records the run in the job runs history.
+// a job can't overlap with itself, in any process: if the previous run is still running, the run is skipped
+// and recorded as skipped. the history is best effort, a failure to record it does not stop the job.
+func (j Job) Exec(trigger string) error {
+	unlock, err := lock(j.Name)
+	if errors.Is(err, ErrStillRunning) {
+		log.Warn("job still running, skipping this run", "job", j.Name)
+		_, err := db.JobRunCreate(j.Name, trigger, db.RunSkipped)
+		if err != nil {
+			log.Warn("failed to record the skipped run", "job", j.Name, "err", err)
+		}
+		return ErrStillRunning
+	}
+
+	if err != nil {
+		log.Err("failed to lock the job", "job", j.Name)
+		return err
+	}
+
+	defer unlock()
+
+	id, err := db.JobRunCreate(j.Name, trigger, db.RunRunning)
+	if err != nil {
+		log.Warn("failed to record the job run", "job", j.Name, "err", err)
+	}
+
+	runErr := j.Run()
+	if id != 0 {
+		err = db.JobRunEnd(id, runErr)
+		if err != nil {
+			log.Warn("failed to record the end of the job run", "job", j.Name, "err", err)
+		}
+	}
+
+	return runErr
+}
+
+// bind function sets the function run by a job defined in the config file, based on its kind.
+func (j *Job) bind() error {
+	switch j.Kind {
+	case KindCommand:
+		if j.Command == "" {
+			return errors.New("job " + j.Name + " has no command")
+		}
+		j.run = command
+	case KindReminder:
+		if j.Message == "" {
+			return errors.New("job " + j.Name + " has no message")
+		}
+		j.run = reminder
+	case KindBackup:
+		j.run = backup
+	case KindReport:
+		j.run = report
+	default:
+		return errors.New("job " + j.Name + " has an unknown kind: " + j.Kind)
+	}
+
+	return nil
+}
+
+// Load function returns the jobs of the registry.
+// the built in jobs are merged with the settings of the jobs of the config file,
+// the other jobs of the config file are added after them.
+// every schedule is validated with the robfig cron parser.
+func Load() ([]Job, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	registry := builtins(cfg)
+	for _, j := range cfg.Jobs {
+		c := Job{Name: j.Name, Kind: j.Kind, Schedule: j.Schedule, Enabled: j.Enabled, Command: j.Command, Message: j.Message, Description: j.Description, LeadTimes: j.LeadTimes}
+		i := slices.IndexFunc(registry, func(j Job) bool { return j.Name == c.Name })
+		if i != -1 {
+			// override the settings of a built in job
+			if c.Schedule != "" {
+				registry[i].Schedule = c.Schedule
+			}
+			if c.Enabled != nil {
+				registry[i].Enabled = c.Enabled
+			}
+			if c.LeadTimes != nil {
+				registry[i].LeadTimes = c.LeadTimes
+			}
+			continue
+		}
+
+		if c.Name == "" {
+			return nil, errors.New("a job in the config file has no name")
+		}
+
+		err := c.bind()
+		if err != nil {
+			return nil, err
+		}
+
+		registry = append(registry, c)
+	}
+
+	for _, j := range registry {
+		_, err := cron.ParseStandard(j.Schedule)
+		if err != nil {
+			return nil, errors.Join(errors.New("job "+j.Name+" has an invalid schedule: "+j.Schedule), err)
+		}
+	}
+
+	return registry, nil
+}
+
+// Find function returns the job with the given name.
+func Find(name string) (Job, error) {
+	registry, err := Load()
+	if err != nil {
+		return Job{}, err
+	}
+
+	i := slices.IndexFunc(registry, func(j Job) bool { return j.Name == name })
+	if i == -1 {
+		return Job{}, errors.New("job not found: " + name)
+	}
+
+	return registry[i], nil
+}
+
+// SetEnabled function enables or disables a job and saves the setting in the config file.
+func SetEnabled(name string, enabled bool) error {
+	_, err := Find(name)
+	if err != nil {
+		return err
+	}
+
+	return config.Update(func(c *config.Config) error {
+		i := slices.IndexFunc(c.Jobs, func(j config.Job) bool { return j.Name == name })
+		if i == -1 {
+			c.Jobs = append(c.Jobs, config.Job{Name: name})
+			i = len(c.Jobs) - 1
+		}
+
+		c.Jobs[i].Enabled = &enabled
+		return nil
+	})
+}
+
+// IsRunning function returns true if the job is in execution, in the cron service or in another process.
+func IsRunning(name string) bool {
+	pid, err := lockPid(name)
+	return err == nil && pid != 0 && cmd.Alive(pid)
+}
+
+// Schedule function adds the enabled jobs to the cron service.
+// every run is executed with Exec, so a job is skipped while its previous run is still running.
+// it returns the names of the jobs by the ids of the added entries, used to remove them when the jobs are reloaded.
+func Schedule(c *cron.Cron, registry []Job) (map[cron.EntryID]string, error) {
+	ids := map[cron.EntryID]string{}
+	for _, j := range registry {
+		if !j.IsEnabled() {
+			log.Deb("job disabled, skipping", "job", j.Name)
+			continue
+		}
+
+		id, err := c.AddFunc(j.Schedule, func() {
+			log.Deb("--- " + j.Name + " job started ---")
+			err := j.Exec(TriggerSchedule)
+			if errors.Is(err, ErrStillRunning) {
+				return
+			}
+
+			if err != nil {
+				log.Err("job failed", "job", j.Name, "err", err)
+				return
+			}
+			log.Deb("--- " + j.Name + " job ended ---")
+		})
+
+		if err != nil {
+			log.Err("failed to add job", "job", j.Name)
+			return ids, err
+		}
+
+		ids[id] = j.Name
+	}
+
+	return ids, nil
+}
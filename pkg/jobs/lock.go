@@ -0,0 +1,110 @@
+// jobs package, job locks
+package jobs
+
+import (
+	"aio/pkg/log"
+	"aio/pkg/utils/cmd"
+	"aio/pkg/utils/fs"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lockFile function returns the path of the lock file of a job, in the jobs folder of the state directory.
+// the name of the job is escaped, the names of the jobs of the config file can have any character.
+func lockFile(name string) (string, error) {
+	dir, err := fs.StatePath("jobs")
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, url.PathEscape(name)+".lock"), nil
+}
+
+// lock function writes the pid of the current process in the lock file of a job.
+// the file is created exclusively, so a job never overlaps with itself, also when it is run by aio cron run
+// while the cron service runs it. a lock file left by a crashed process is replaced.
+// it returns ErrStillRunning if the job is running, and a function that removes the lock file.
+func lock(name string) (func(), error) {
+	file, err := lockFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for range 2 {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			f.Close()
+			if err != nil {
+				os.Remove(file)
+				return nil, err
+			}
+
+			return func() { os.Remove(file) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		pid, err := lockPid(name)
+		if err != nil {
+			return nil, err
+		}
+
+		// a lock file just created may be still empty, its process is running
+		if pid == -1 || (pid != 0 && cmd.Alive(pid)) {
+			return nil, ErrStillRunning
+		}
+
+		log.Warn("removing stale job lock", "file", file)
+		os.Remove(file)
+	}
+
+	return nil, errors.New("failed to lock the job: " + file)
+}
+
+// Lock function locks a job for a command of the cli that does the same work, e.g. aio sync now commits and pushes
+// like the push job, so the command and the job never commit or push at the same time.
+// it returns ErrStillRunning if the job is running, and a function that removes the lock.
+func Lock(name string) (func(), error) {
+	return lock(name)
+}
+
+// lockPid function returns the pid written in the lock file of a job, 0 if the job is not locked
+// or the pid is invalid, -1 if the lock file is still empty.
+func lockPid(name string) (int, error) {
+	file, err := lockFile(name)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) == 0 {
+		return -1, nil
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, nil
+	}
+
+	return pid, nil
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"aio/pkg/utils/fs"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+)
+
+func TestLock(t *testing.T) {
+	fs.SetHome(t.TempDir())
+
+	// the names of the jobs of the config file can have any character
+	for _, name := range []string{"push", "backup/photos"} {
+		unlock, err := lock(name)
+		if err != nil {
+			t.Fatalf("lock(%q) = %v", name, err)
+		}
+
+		if !IsRunning(name) {
+			t.Errorf("job %q not running while locked", name)
+		}
+
+		// a second acquirer is refused, the lock belongs to a running process
+		_, err = lock(name)
+		if !errors.Is(err, ErrStillRunning) {
+			t.Errorf("second lock(%q) = %v, want ErrStillRunning", name, err)
+		}
+
+		unlock()
+		if IsRunning(name) {
+			t.Errorf("job %q still running after the unlock", name)
+		}
+
+		unlock, err = lock(name)
+		if err != nil {
+			t.Fatalf("lock(%q) after the unlock = %v", name, err)
+		}
+		unlock()
+	}
+}
+
+func TestLockStale(t *testing.T) {
+	fs.SetHome(t.TempDir())
+
+	// the pid of a process that has exited, like a cron service that crashed in a run
+	c := exec.Command("true")
+	err := c.Run()
+	if err != nil {
+		t.Skip("no true command: ", err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{name: "dead process", content: strconv.Itoa(c.Process.Pid)},
+		{name: "invalid pid", content: "not a pid"},
+		{name: "lock being written", content: "", err: ErrStillRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := lockFile("reminders")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = os.WriteFile(file, []byte(tt.content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			unlock, err := lock("reminders")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("lock() = %v, want %v", err, tt.err)
+			}
+
+			if err != nil {
+				os.Remove(file)
+				return
+			}
+
+			defer unlock()
+			pid, err := lockPid("reminders")
+			if err != nil || pid != os.Getpid() {
+				t.Errorf("lockPid() = %d, %v, want the pid of the test %d", pid, err, os.Getpid())
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// jobs package, push job
+package jobs
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/git"
+	"aio/pkg/log"
+)
+
+// push function commits the changes to the database and pushes them to the remote repository.
+// when the push fails, the sync state machine delays the next attempts with an exponential backoff.
+func push(j Job) error {
+	err := git.Main() // check out the main branch
+	if err != nil {
+		log.Err("failed to check out the main branch")
+		return err
+	}
+
+	// commit the changes to the database, the changes recorded in the journal are summarised in the message
+	err = db.JournalCommit("")
+	if err != nil {
+		log.Err("failed to commit the changes")
+		return err
+	}
+
+	_, err = git.Sync(false) // push the database to the remote repository, unless waiting for a retry
+	if err != nil {
+		log.Err("failed to push the database to the remote repository")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// jobs package, recurring transactions job
+package jobs
+
+import (
+	"aio/pkg/config"
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"aio/pkg/utils/money"
+	"aio/pkg/utils/tm"
+	"errors"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// renewalKind is the kind of the notifications of the renewals of the subscriptions.
+const renewalKind = "renewal"
+
+// recurring function records in the ledger the due occurrences of the recurring transactions of every profile,
+// and warns the renewals of their subscriptions, the recurring expenses repeated every month or less often,
+// money.renewal_warning days before them.
+// a renewal is warned once, it is recorded in the notifications like the reminders once it has been shown.
+func recurring(j Job) error {
+	now := time.Now()
+	return db.ForEachProfile(func(db.Profile) error {
+		return recordRecurring(now)
+	})
+}
+
+// recordRecurring function records the due recurring transactions of the current profile and warns its renewals.
+func recordRecurring(now time.Time) error {
+	recorded, err := db.RecurringRecord(now)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range recorded {
+		log.Info("recorded recurring transaction", "description", t.Description, "date", t.Date.Format("2006-01-02"), "amount", money.Format(t.Amount, t.Currency), "currency", t.Currency)
+	}
+
+	days := config.Get().Money.RenewalWarning
+	if days == 0 {
+		return nil
+	}
+
+	recurrings, err := db.Recurrings()
+	if err != nil {
+		return err
+	}
+
+	lead := time.Duration(days) * 24 * time.Hour
+	errs := []error{}
+	for _, r := range recurrings {
+		if r.Amount >= 0 || r.Ended() || r.NextAt.After(now.Add(lead)) {
+			continue
+		}
+
+		// the expenses repeated more than once a month, like the daily coffee, are not renewals
+		sc, err := tm.ParseSchedule(r.Schedule)
+		if err != nil || sc.PerYear() > 12 {
+			continue
+		}
+
+		id, err := db.NotificationFind(renewalKind, r.ID, r.NextAt, lead)
+		if err != nil {
+			return err
+		}
+
+		if id != 0 {
+			continue // already warned
+		}
+
+		log.Info("warning renewal", "description", r.Description, "date", r.NextAt.Format("2006-01-02"))
+		message := r.Description + " renews on " + r.NextAt.Format("Mon 02 Jan") + ", " + money.Format(-r.Amount, r.Currency) + " " + r.Currency + " will be charged."
+		err = beeep.Notify("aio: renewal of "+r.Description, message, "")
+		if err != nil {
+			// the renewal is not recorded, the next run warns it again
+			errs = append(errs, err)
+			continue
+		}
+
+		_, err = db.NotificationCreate(renewalKind, r.ID, r.Description, r.NextAt, lead)
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.Join(errs...)
+}
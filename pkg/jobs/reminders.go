@@ -0,0 +1,19 @@
+// jobs package, reminders job
+package jobs
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/remind"
+)
+
+// reminders function shows the reminders of the upcoming tasks and habits of every profile, at the lead times of the job.
+func reminders(j Job) error {
+	leads, err := remind.ParseLeads(j.LeadTimes)
+	if err != nil {
+		return err
+	}
+
+	return db.ForEachProfile(func(db.Profile) error {
+		return remind.New(leads).Run()
+	})
+}
@@ -0,0 +1,33 @@
+// log package, journal functions
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+)
+
+// priorities maps the levels to the syslog priorities, used as line prefixes understood by journald
+var priorities = map[Level]int{DebugLevel: 7, InfoLevel: 6, WarnLevel: 4, ErrorLevel: 3, FatalLevel: 2}
+
+// journalEnabled function returns true if the messages must also be written to the standard error for journald.
+// it is enabled by the AIO_LOG_JOURNAL variable, set by the systemd unit of the cron service.
+func journalEnabled() bool {
+	return os.Getenv("AIO_LOG_JOURNAL") != ""
+}
+
+// journal function writes a message to the standard error with the syslog priority prefix,
+// so that journald stores it with the right level. the timestamp is added by journald.
+// the messages below the log level are not written.
+func journal(l Level, msg string, args ...any) {
+	if !journalEnabled() || l < level {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	logger := log.NewWithOptions(buf, log.Options{Formatter: log.LogfmtFormatter, Level: log.DebugLevel})
+	logger.Print(msg, args...)
+	fmt.Fprintf(os.Stderr, "<%d>%s", priorities[l], buf.String())
+}
@@ -0,0 +1,51 @@
+// log package, level functions
+package log
+
+import "github.com/charmbracelet/log"
+
+// level is the minimum level of the messages written to the log file and to the console.
+// it is the log.level of the config file, info by default.
+var level = InfoLevel
+
+// levelSet is true when the level is set with SetLevel, so the config file does not change it.
+var levelSet bool
+
+// quiet disables the console output, except the errors.
+var quiet bool
+
+// SetLevel function sets the minimum level of the messages written to the log file and to the console.
+// the caller of the messages is reported only at the debug level.
+func SetLevel(l Level) {
+	levelSet = true
+	setLevel(l)
+}
+
+// setLevel function applies the level to the log file and to the console.
+func setLevel(l Level) {
+	level = l
+	log.SetLevel(l)
+	if quiet {
+		log.SetLevel(max(l, ErrorLevel))
+	}
+
+	if logger != nil {
+		logger.SetLevel(l)
+		logger.SetReportCaller(l == DebugLevel)
+	}
+}
+
+// GetLevel function returns the minimum level of the messages.
+func GetLevel() Level {
+	return level
+}
+
+// SetQuiet function disables the console output, except the errors, if q is true.
+// the messages are still written to the log file.
+func SetQuiet(q bool) {
+	quiet = q
+	if q {
+		log.SetLevel(max(level, ErrorLevel))
+		return
+	}
+	log.SetLevel(level)
+}
@@ -0,0 +1,43 @@
+package log
+
+import "testing"
+
+func TestConfigure(t *testing.T) {
+	// the level of the config (the file or AIO_LOG_LEVEL) applies unless the flags set one
+	tests := []struct {
+		name   string
+		flag   string // the --log-level flag, empty if not passed
+		config Level
+		want   Level
+	}{
+		{name: "config", config: WarnLevel, want: WarnLevel},
+		{name: "flag over config", flag: "debug", config: WarnLevel, want: DebugLevel},
+		{name: "upper case flag", flag: "ERROR", config: DebugLevel, want: ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				levelSet = false
+				setLevel(InfoLevel)
+			})
+
+			if tt.flag != "" {
+				l, err := ParseLevel(tt.flag)
+				if err != nil {
+					t.Fatal(err)
+				}
+				SetLevel(l)
+			}
+
+			Configure(Settings{Level: tt.config, Format: "text", MaxSize: 10, Retention: 14})
+			if got := GetLevel(); got != tt.want {
+				t.Errorf("level = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel() accepts an invalid level")
+	}
+}
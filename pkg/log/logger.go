@@ -3,23 +3,55 @@ package log
 
 import (
 	"aio/pkg/utils/fs"
-	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/gen2brain/beeep"
 )
 
+// text time format of the log files, also used to parse them
+const textTimeFormat = "[Monday, 02 Jan 2006 15:04:05]"
+
+// Settings struct represents the settings of the log files.
+type Settings struct {
+	Level     Level  // minimum level of the messages, unless it is set with SetLevel
+	Format    string // format of the log files, "text" or "json"
+	MaxSize   int64  // maximum size of a log file in megabytes, before it is rotated
+	Retention int    // number of days the rotated log files are kept
+}
+
+// settings are the settings of the log files, the defaults until Configure is called.
+var settings = Settings{Level: InfoLevel, Format: "text", MaxSize: 10, Retention: 14}
+
+// Configure function applies the settings of the config file.
+// it is called when the program starts, before the first message is logged:
+// the format and the maximum size of the log file can't change after the file is opened.
+// the level set with SetLevel, e.g. by the --log-level flag, is kept.
+func Configure(s Settings) {
+	settings = s
+	if !levelSet {
+		setLevel(s.Level)
+	}
+}
+
+// the logger of the process, initialized on the first log call
+var (
+	loggerOnce sync.Once
+	logger     *log.Logger
+)
+
 // getLogDir function returns the log directory
 func getLogDir() (string, error) {
-	execDir, err := fs.ExecDir()
+	logDir, err := fs.StatePath("logs")
 	if err != nil {
 		return "", err
 	}
 
-	logDir := filepath.Join(execDir, "logs")
 	if err := os.MkdirAll(logDir, os.ModePerm); err != nil {
 		return "", err
 	}
@@ -27,73 +59,67 @@ func getLogDir() (string, error) {
 	return logDir, nil
 }
 
-// loggerInit function initializes the logger
-func loggerInit() (*log.Logger, *os.File, error) {
-	// get the log directory
-	logDir, err := getLogDir()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// create a log file with the current date if it does not exist
-	logFileName := filepath.Join(logDir, time.Now().Format("2006-01-02")+".log")
-	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		return nil, nil, err
-	}
+// Dir function returns the directory of the log files.
+func Dir() (string, error) {
+	return getLogDir()
+}
 
-	logger := log.New(logFile)
-	logger.SetReportTimestamp(true)
-	logger.SetTimeFormat("[Monday, 02 Jan 2006 15:04:05]")
-	logger.SetLevel(log.DebugLevel)
-	logger.SetReportCaller(true)
-	logger.SetCallerOffset(1)
+// processName function returns the name of the current program, used as name of its log file,
+// so every process writes and rotates its own file (aio.log, cron.log).
+func processName() string {
+	name := filepath.Base(os.Args[0])
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
 
-	return logger, logFile, nil
+// getLogger function returns the logger of the process, initializing it on the first call.
+// if the log file can't be opened, the messages are written to the standard error.
+func getLogger() *log.Logger {
+	loggerOnce.Do(func() {
+		var w io.Writer = os.Stderr
+		logDir, err := getLogDir()
+		if err == nil {
+			w = newRotator(filepath.Join(logDir, processName()+".log"), settings.MaxSize*1024*1024)
+		} else {
+			PrintWarn("failed to get log directory, logging to the standard error", "err", err)
+		}
+
+		logger = log.New(w)
+		logger.SetReportTimestamp(true)
+		logger.SetTimeFormat(textTimeFormat)
+		if settings.Format == "json" {
+			logger.SetFormatter(log.JSONFormatter)
+			logger.SetTimeFormat(time.RFC3339)
+		}
+		logger.SetLevel(level)
+		logger.SetReportCaller(level == DebugLevel)
+		logger.SetCallerOffset(1)
+	})
+
+	return logger
 }
 
 // Deb function logs a debug message
 func Deb(msg string, args ...any) {
-	logger, file, err := loggerInit() // initialize the logger
-	if err != nil {
-		Fat(errors.Join(err, file.Close())) // close the file if an error occurs
-	}
-
-	defer file.Close()         // close the file when the function ends
-	logger.Debug(msg, args...) // log the message
+	getLogger().Debug(msg, args...) // log the message
+	journal(DebugLevel, msg, args...)
 }
 
 // Info function logs an info message
 func Info(msg string, args ...any) {
-	logger, file, err := loggerInit() // initialize the logger
-	if err != nil {
-		Fat(errors.Join(err, file.Close())) // close the file if an error occurs
-	}
-
-	defer file.Close()        // close the file when the function ends
-	logger.Info(msg, args...) // log the message
+	getLogger().Info(msg, args...) // log the message
+	journal(InfoLevel, msg, args...)
 }
 
 // Warn function logs a warning message
 func Warn(msg string, args ...any) {
-	logger, file, err := loggerInit() // initialize the logger
-	if err != nil {
-		Fat(errors.Join(err, file.Close())) // close the file if an error occurs
-	}
-
-	defer file.Close()        // close the file when the function ends
-	logger.Warn(msg, args...) // log the message
+	getLogger().Warn(msg, args...) // log the message
+	journal(WarnLevel, msg, args...)
 }
 
 // Err function logs an error message
 func Err(msg string, args ...any) {
-	logger, file, err := loggerInit() // initialize the logger
-	if err != nil {
-		Fat(errors.Join(err, file.Close())) // close the file if an error occurs
-	}
-
-	defer file.Close()         // close the file when the function ends
-	logger.Error(msg, args...) // log the message
+	getLogger().Error(msg, args...) // log the message
+	journal(ErrorLevel, msg, args...)
 }
 
 // Fat function logs a fatal error message and exits the program
@@ -103,26 +129,17 @@ func Fat(err error) {
 		return
 	}
 
-	logger, file, err := loggerInit() // initialize the logger
-	if err != nil {
-		err = errors.Join(err, file.Close()) // close the file if an error occurs
-		err = errors.Join(errors.New("failed to initialize logger"), err)
-		beeep.Alert("aio: an error occurred", err.Error(), "")
-		os.Exit(1)
-	}
+	getLogger().Error("FATAL", "error", err) // log the message
+	journal(FatalLevel, "FATAL", "error", err)
 
-	logDir, err := getLogDir() // get the log directory
-	if err != nil {
-		err = errors.Join(err, file.Close()) // close the file if an error occurs
-		err = errors.Join(errors.New("failed to get log directory"), err)
+	logDir, dirErr := getLogDir() // get the log directory
+	if dirErr != nil {
 		beeep.Alert("aio: an error occurred", err.Error(), "")
 		os.Exit(1)
 	}
 
-	logger.Error("FATAL", "error", err) // log the message
 	PrintErr("an error occurred, check the log files", "log-dir", logDir)
 	beeep.Alert("aio: an error occurred", "To see the full error, check the log file in this folder: "+logDir, "") // display an alert to check the logs
-	file.Close()                                                                                                   // close the file
 	os.Exit(1)                                                                                                     // exit the program
 }
 
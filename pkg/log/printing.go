@@ -15,8 +15,12 @@ func Print(msg string, args ...any) {
 }
 
 // PrintS function prints a message to the console
-// with the specified style
+// with the specified style, unless the console output is quiet
 func PrintS(msg string, style lipgloss.Style, args ...any) {
+	if quiet {
+		return
+	}
+
 	s := fmt.Sprintf(msg, args...)
 	s = style.Render(s)
 	println(s)
@@ -0,0 +1,303 @@
+// log package, log files reading
+package log
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Level is the severity of a log entry.
+type Level = log.Level
+
+// levels of the log entries
+const (
+	DebugLevel = log.DebugLevel
+	InfoLevel  = log.InfoLevel
+	WarnLevel  = log.WarnLevel
+	ErrorLevel = log.ErrorLevel
+	FatalLevel = log.FatalLevel
+)
+
+// ParseLevel function returns the level with the given name (debug, info, warn, error, fatal).
+func ParseLevel(s string) (Level, error) {
+	return log.ParseLevel(strings.ToLower(s))
+}
+
+// textLevels maps the level labels of the text log files to their level.
+var textLevels = map[string]Level{"DEBU": DebugLevel, "INFO": InfoLevel, "WARN": WarnLevel, "ERRO": ErrorLevel, "FATA": FatalLevel}
+
+// logName matches the names of the log files and captures the process name,
+// e.g. aio.log, cron-20241020-153000.log.gz.
+var logName = regexp.MustCompile(`^(.+?)(-\d{8}-\d{6})?\.log(\.gz)?$`)
+
+// Entry struct represents an entry of a log file.
+// an entry can span more lines, e.g. when an error has a multi line message.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Process string
+	Text    string
+}
+
+// parseEntry function parses the first line of a log entry, in text or json format.
+// it returns false if the line is not the start of an entry.
+func parseEntry(line string) (Entry, bool) {
+	if strings.HasPrefix(line, "{") {
+		var fields struct {
+			Time  string `json:"time"`
+			Level string `json:"level"`
+		}
+
+		if json.Unmarshal([]byte(line), &fields) != nil {
+			return Entry{}, false
+		}
+
+		t, err := time.Parse(time.RFC3339, fields.Time)
+		if err != nil {
+			return Entry{}, false
+		}
+
+		level, err := ParseLevel(fields.Level)
+		if err != nil {
+			return Entry{}, false
+		}
+
+		return Entry{Time: t, Level: level, Text: line}, true
+	}
+
+	end := strings.Index(line, "]")
+	if !strings.HasPrefix(line, "[") || end == -1 {
+		return Entry{}, false
+	}
+
+	t, err := time.ParseInLocation(textTimeFormat, line[:end+1], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	label, _, _ := strings.Cut(strings.TrimSpace(line[end+1:]), " ")
+	level, ok := textLevels[label]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return Entry{Time: t, Level: level, Text: line}, true
+}
+
+// logFiles function returns the log files of a process, or of every process if process is empty,
+// sorted by modification time, so the entries are read from the oldest to the newest.
+func logFiles(process string) ([]string, error) {
+	logDir, err := getLogDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(logDir)
+	if err != nil {
+		return nil, err
+	}
+
+	type file struct {
+		path string
+		mod  time.Time
+	}
+
+	found := []file{}
+	for _, f := range files {
+		m := logName.FindStringSubmatch(f.Name())
+		if f.IsDir() || m == nil || (process != "" && m[1] != process) {
+			continue
+		}
+
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+
+		found = append(found, file{filepath.Join(logDir, f.Name()), info.ModTime()})
+	}
+
+	slices.SortFunc(found, func(a, b file) int { return a.mod.Compare(b.mod) })
+	paths := make([]string, len(found))
+	for i, f := range found {
+		paths[i] = f.path
+	}
+
+	return paths, nil
+}
+
+// fileProcess function returns the name of the process that wrote a log file.
+// the daily files written by the previous versions have no process name.
+func fileProcess(path string) string {
+	m := logName.FindStringSubmatch(filepath.Base(path))
+	if m == nil || rotatedName.MatchString(m[1]+".log") {
+		return ""
+	}
+	return m[1]
+}
+
+// scanEntries function reads the entries of a log file and calls fn for every entry.
+// the lines that are not the start of an entry are appended to the previous one.
+func scanEntries(r io.Reader, process string, fn func(Entry)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var current *Entry
+	for scanner.Scan() {
+		line := scanner.Text()
+		if e, ok := parseEntry(line); ok {
+			if current != nil {
+				fn(*current)
+			}
+			e.Process = process
+			current = &e
+			continue
+		}
+
+		if current != nil {
+			current.Text += "\n" + line
+		}
+	}
+
+	if current != nil {
+		fn(*current)
+	}
+
+	return scanner.Err()
+}
+
+// Read function returns the entries of the log files written since the given time,
+// with at least the given level. if process is empty, the entries of every process are returned.
+// the compressed log files are read too.
+func Read(process string, since time.Time, level Level) ([]Entry, error) {
+	files, err := logFiles(process)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	for _, path := range files {
+		if info, err := os.Stat(path); err != nil || info.ModTime().Before(since) {
+			continue // the file has been written for the last time before since
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var r io.Reader = f
+		if strings.HasSuffix(path, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			r = gz
+		}
+
+		err = scanEntries(r, fileProcess(path), func(e Entry) {
+			if e.Level >= level && !e.Time.Before(since) {
+				entries = append(entries, e)
+			}
+		})
+
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int { return a.Time.Compare(b.Time) })
+	return entries, nil
+}
+
+// Follow function calls fn for every new entry written in the log files in use,
+// with at least the given level, until stop is closed.
+// a rotated file is detected by its size, and read again from the start.
+func Follow(process string, level Level, stop <-chan struct{}, fn func(Entry)) error {
+	logDir, err := getLogDir()
+	if err != nil {
+		return err
+	}
+
+	offsets := map[string]int64{}
+	current := func() []string {
+		paths := []string{}
+		files, _ := os.ReadDir(logDir)
+		for _, f := range files {
+			m := logName.FindStringSubmatch(f.Name())
+			if m == nil || m[2] != "" || m[3] != "" || fileProcess(f.Name()) == "" || (process != "" && m[1] != process) {
+				continue
+			}
+			paths = append(paths, filepath.Join(logDir, f.Name()))
+		}
+		return paths
+	}
+
+	// start from the end of the files
+	for _, path := range current() {
+		if info, err := os.Stat(path); err == nil {
+			offsets[path] = info.Size()
+		}
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		for _, path := range current() {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+
+			offset := offsets[path]
+			if info.Size() < offset {
+				offset = 0 // the file has been rotated
+			}
+
+			if info.Size() == offset {
+				continue
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+
+			data := make([]byte, info.Size()-offset)
+			n, _ := f.ReadAt(data, offset)
+			f.Close()
+
+			// read only the complete lines, the rest is read at the next tick
+			data = data[:n]
+			last := strings.LastIndex(string(data), "\n")
+			if last == -1 {
+				continue
+			}
+
+			offsets[path] = offset + int64(last) + 1
+			scanEntries(strings.NewReader(string(data[:last+1])), fileProcess(path), func(e Entry) {
+				if e.Level >= level {
+					fn(e)
+				}
+			})
+		}
+	}
+}
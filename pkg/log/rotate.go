@@ -0,0 +1,192 @@
+// log package, log files rotation
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedName matches the names of the rotated log files, e.g. aio-20241020-153000.log,
+// and of the daily log files written by the previous versions, e.g. 2024-10-20.log.
+var rotatedName = regexp.MustCompile(`^(.+-\d{8}-\d{6}|\d{4}-\d{2}-\d{2})\.log$`)
+
+// rotator struct is a writer that appends to a log file and rotates it
+// when it exceeds the maximum size or when the day changes.
+// the rotated files are compressed with gzip.
+type rotator struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+	day     string
+}
+
+// newRotator function returns a rotator that writes to the given log file.
+func newRotator(path string, maxSize int64) *rotator {
+	return &rotator{path: path, maxSize: maxSize}
+}
+
+// open function opens the log file in append mode.
+// if the existing file is from a previous day, it is rotated first.
+func (r *rotator) open() error {
+	today := time.Now().Format("2006-01-02")
+	if info, err := os.Stat(r.path); err == nil && info.Size() > 0 && info.ModTime().Format("2006-01-02") != today {
+		err = r.rename()
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file, r.size, r.day = f, info.Size(), today
+	return nil
+}
+
+// rename function moves the log file to a rotated file, named with the current time, and compresses it.
+func (r *rotator) rename() error {
+	ext := filepath.Ext(r.path)
+	rotated := strings.TrimSuffix(r.path, ext) + "-" + time.Now().Format("20060102-150405") + ext
+	err := os.Rename(r.path, rotated)
+	if err != nil {
+		return err
+	}
+
+	return compress(rotated)
+}
+
+// Write function writes to the log file, rotating it when needed.
+func (r *rotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.size > 0 && (r.size+int64(len(p)) > r.maxSize || time.Now().Format("2006-01-02") != r.day) {
+		r.file.Close()
+		r.file = nil
+		if err := r.rename(); err != nil {
+			return 0, err
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// compress function compresses a log file with gzip and removes the original.
+// the compressed file keeps the modification time of the original, used by the retention policy.
+func compress(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err == nil {
+		err = gz.Close()
+	}
+
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	in.Close()
+	os.Chtimes(path+".gz", info.ModTime(), info.ModTime())
+	return os.Remove(path)
+}
+
+// Clean function applies the retention policy to the log directory:
+// the rotated files not yet compressed are compressed, and the files older than the retention are deleted.
+// the log files in use are never deleted.
+func Clean() error {
+	logDir, err := getLogDir()
+	if err != nil {
+		return err
+	}
+
+	files, err := os.ReadDir(logDir)
+	if err != nil {
+		return err
+	}
+
+	limit := time.Now().AddDate(0, 0, -settings.Retention)
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(logDir, file.Name())
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		if !rotatedName.MatchString(file.Name()) && !strings.HasSuffix(file.Name(), ".log.gz") {
+			continue // a log file in use
+		}
+
+		if info.ModTime().Before(limit) {
+			err = os.Remove(path)
+			if err != nil {
+				Err("failed to remove old log file", "file", path, "err", err)
+				continue
+			}
+			Info("deleted old log file", "file", path)
+			continue
+		}
+
+		if filepath.Ext(path) == ".log" {
+			err = compress(path)
+			if err != nil {
+				Err("failed to compress log file", "file", path, "err", err)
+				continue
+			}
+			Deb("compressed log file", "file", path)
+		}
+	}
+
+	return nil
+}
@@ -7,10 +7,22 @@ import "github.com/charmbracelet/lipgloss"
 var (
 	BrigthColor = lipgloss.Color("15")
 	ErrorColor  = lipgloss.Color("196")
+	ChangeColor = lipgloss.Color("214")
 )
 
 // styles
 var (
-	TitleStyle = lipgloss.NewStyle().Foreground(BrigthColor).Bold(true)
-	ErrorStyle = lipgloss.NewStyle().Foreground(ErrorColor).Bold(true)
+	TitleStyle   = lipgloss.NewStyle().Foreground(BrigthColor).Bold(true)
+	ErrorStyle   = lipgloss.NewStyle().Foreground(ErrorColor).Bold(true)
+	ChangedStyle = lipgloss.NewStyle().Foreground(ChangeColor)
+	BannerStyle  = lipgloss.NewStyle().Foreground(ChangeColor).Bold(true).Border(lipgloss.DoubleBorder()).BorderForeground(ChangeColor).Padding(1, 4).Align(lipgloss.Center)
 )
+
+// SetColors function sets the colors of the styles, as ANSI codes (0-255) or hex codes (#ff8800).
+func SetColors(bright, err, change string) {
+	BrigthColor, ErrorColor, ChangeColor = lipgloss.Color(bright), lipgloss.Color(err), lipgloss.Color(change)
+	TitleStyle = TitleStyle.Foreground(BrigthColor)
+	ErrorStyle = ErrorStyle.Foreground(ErrorColor)
+	ChangedStyle = ChangedStyle.Foreground(ChangeColor)
+	BannerStyle = BannerStyle.Foreground(ChangeColor).BorderForeground(ChangeColor)
+}
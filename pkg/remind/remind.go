@@ -0,0 +1,200 @@
+// remind package scans the upcoming tasks and habits and reminds them to the user.
+package remind
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// Notifier interface represents the way the reminders are shown to the user.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// Clock interface represents the source of the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// desktop struct shows the reminders as desktop notifications.
+type desktop struct{}
+
+// Notify function shows a desktop notification.
+func (desktop) Notify(title, message string) error {
+	return beeep.Notify(title, message, "")
+}
+
+// system struct returns the current time of the system.
+type system struct{}
+
+// Now function returns the current time.
+func (system) Now() time.Time {
+	return time.Now()
+}
+
+// Scheduler struct scans the upcoming items and shows a reminder for every lead time reached.
+// the notifier and the clock can be replaced, e.g. to test the scheduler.
+type Scheduler struct {
+	Notifier Notifier
+	Clock    Clock
+	Leads    []time.Duration
+}
+
+// item struct represents a task or a habit occurrence to remind.
+type item struct {
+	kind  string
+	id    int
+	title string
+	due   time.Time
+}
+
+// New function returns a scheduler that shows desktop notifications at the given lead times.
+func New(leads []time.Duration) *Scheduler {
+	return &Scheduler{Notifier: desktop{}, Clock: system{}, Leads: leads}
+}
+
+// ParseLeads function parses the lead times (e.g. "1h", "15m").
+func ParseLeads(leads []string) ([]time.Duration, error) {
+	durations := []time.Duration{}
+	for _, l := range leads {
+		d, err := time.ParseDuration(l)
+		if err != nil {
+			return nil, errors.Join(errors.New("invalid lead time: "+l), err)
+		}
+
+		if d <= 0 {
+			return nil, errors.New("the lead time must be positive: " + l)
+		}
+
+		durations = append(durations, d)
+	}
+
+	return durations, nil
+}
+
+// upcoming function returns the tasks and the habit occurrences due in the range (now, until].
+func upcoming(now, until time.Time) ([]item, error) {
+	items := []item{}
+	tasks, err := db.TasksDue(now, until)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tasks {
+		items = append(items, item{kind: "task", id: t.ID, title: t.Title, due: *t.DueAt})
+	}
+
+	habits, err := db.Habits()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range habits {
+		next, err := h.Next(now)
+		if err != nil {
+			log.Warn("skipping habit with an invalid schedule", "habit", h.Title, "err", err)
+			continue
+		}
+
+		if !next.After(until) {
+			items = append(items, item{kind: "habit", id: h.ID, title: h.Title, due: next})
+		}
+	}
+
+	return items, nil
+}
+
+// message function returns the text of the reminder of an item.
+func message(kind, title string, due, now time.Time, id int) string {
+	in := due.Sub(now).Round(time.Minute)
+	text := fmt.Sprintf("%s %q is due at %s", kind, title, due.Format("15:04"))
+	if in > 0 {
+		text += fmt.Sprintf(" (in %s)", in)
+	}
+	return text + fmt.Sprintf(".\nSnooze it with: aio remind snooze %d", id)
+}
+
+// Run function shows the reminders of the upcoming items and the snoozed reminders due now.
+// for every item only the smallest lead time reached is notified, the larger ones are recorded as shown,
+// so a reminder is never shown twice for the same lead time. a reminder that can't be shown is not recorded,
+// the next run shows it again.
+func (s *Scheduler) Run() error {
+	if len(s.Leads) == 0 {
+		return nil
+	}
+
+	now := s.Clock.Now()
+	items, err := upcoming(now, now.Add(slices.Max(s.Leads)))
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, it := range items {
+		reached := []time.Duration{}
+		for _, l := range s.Leads {
+			if !now.Before(it.due.Add(-l)) {
+				reached = append(reached, l)
+			}
+		}
+
+		if len(reached) == 0 {
+			continue
+		}
+
+		lead := slices.Min(reached)
+		id, err := db.NotificationFind(it.kind, it.id, it.due, lead)
+		if err != nil {
+			return err
+		}
+
+		if id != 0 {
+			continue // already shown
+		}
+
+		// the larger lead times may have been shown by the previous runs, they are not recorded again
+		var shown error
+		err = db.NotificationShow(it.kind, it.id, it.title, it.due, reached, lead, func(id int) error {
+			log.Info("showing reminder", "kind", it.kind, "title", it.title, "lead", lead)
+			shown = s.Notifier.Notify("aio: "+it.title, message(it.kind, it.title, it.due, now, id))
+			return shown
+		})
+
+		if shown != nil {
+			// the reminder has not been shown, it is shown again by the next run
+			errs = append(errs, shown)
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	snoozed, err := db.NotificationsSnoozedDue(now)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range snoozed {
+		log.Info("showing snoozed reminder", "kind", n.Kind, "title", n.Title)
+		err = s.Notifier.Notify("aio: "+n.Title, message(n.Kind, n.Title, n.DueAt, now, n.ID))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		err = db.NotificationRefire(n.ID, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,205 @@
+package remind
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/utils/fs"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// notifier struct records the reminders shown, or fails to show them.
+type notifier struct {
+	shown []string
+	fail  bool
+}
+
+// Notify function records the title of the reminder, or fails.
+func (n *notifier) Notify(title, message string) error {
+	if n.fail {
+		return errors.New("no notification daemon")
+	}
+	n.shown = append(n.shown, title)
+	return nil
+}
+
+// clock struct returns the time set by the test.
+type clock struct {
+	now time.Time
+}
+
+// Now function returns the time set by the test.
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// setup function initializes a database with a character in a temporary directory.
+func setup(t *testing.T) {
+	t.Helper()
+	err := fs.SetHome(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.SetProfile("")
+	err = db.Init(db.Seed{FirstName: "Jane", LastName: "Smith", NickName: "Jay", BirthDate: "02 Jan 2000", Currency: "EUR", Budget: "1500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseLeads(t *testing.T) {
+	tests := []struct {
+		leads   []string
+		want    []time.Duration
+		invalid bool
+	}{
+		{leads: []string{"1h", "15m"}, want: []time.Duration{time.Hour, 15 * time.Minute}},
+		{leads: []string{}, want: []time.Duration{}},
+		{leads: []string{"soon"}, invalid: true},
+		{leads: []string{"0s"}, invalid: true},
+		{leads: []string{"-5m"}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLeads(tt.leads)
+		if (err != nil) != tt.invalid || len(got) != len(tt.want) {
+			t.Errorf("ParseLeads(%q) = %v, %v, want %v", tt.leads, got, err, tt.want)
+			continue
+		}
+
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseLeads(%q) = %v, want %v", tt.leads, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	due := time.Date(2026, time.October, 20, 18, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{name: "before the due time", now: due.Add(-15 * time.Minute), want: []string{`task "Pay the rent" is due at 18:00`, "(in 15m0s)", "aio remind snooze 7"}},
+		{name: "at the due time", now: due, want: []string{`task "Pay the rent" is due at 18:00.`, "aio remind snooze 7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := message("task", "Pay the rent", due, tt.now, 7)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("message() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunWithoutLeads(t *testing.T) {
+	// without lead times the scheduler is disabled, nothing is read or shown
+	n := &notifier{}
+	s := &Scheduler{Notifier: n, Clock: &clock{now: time.Now()}}
+	err := s.Run()
+	if err != nil || len(n.shown) > 0 {
+		t.Errorf("Run() = %v, shown %q, want nothing shown", err, n.shown)
+	}
+}
+
+func TestRun(t *testing.T) {
+	setup(t)
+
+	due := time.Date(2026, time.October, 20, 18, 0, 0, 0, time.Local)
+	err := db.TaskCreate("Pay the rent", &due)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, c := &notifier{}, &clock{}
+	s := &Scheduler{Notifier: n, Clock: c, Leads: []time.Duration{time.Hour, 15 * time.Minute}}
+
+	// the runs are in order, like the runs of the reminders job
+	tests := []struct {
+		name   string
+		before time.Duration // the time of the run before the due time
+		fail   bool
+		shown  int // the reminders shown by the run
+		snooze time.Duration
+	}{
+		{name: "before the leads", before: 90 * time.Minute},
+		{name: "first lead reached", before: 55 * time.Minute, shown: 1},
+		{name: "first lead already shown", before: 50 * time.Minute},
+		{name: "notification failed", before: 14 * time.Minute, fail: true},
+		{name: "failed notification shown again", before: 13 * time.Minute, shown: 1, snooze: 5 * time.Minute},
+		{name: "snoozed", before: 10 * time.Minute},
+		{name: "snooze expired", before: 7 * time.Minute, shown: 1},
+		{name: "snoozed reminder shown once", before: 5 * time.Minute},
+		{name: "after the due time", before: -time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.now = due.Add(-tt.before)
+			n.shown, n.fail = nil, tt.fail
+			err := s.Run()
+			if (err != nil) != tt.fail {
+				t.Fatalf("Run() error = %v, want an error %v", err, tt.fail)
+			}
+
+			if len(n.shown) != tt.shown {
+				t.Errorf("%d reminders shown, want %d: %q", len(n.shown), tt.shown, n.shown)
+			}
+
+			if tt.snooze == 0 {
+				return
+			}
+
+			notifications, err := db.Notifications(1)
+			if err != nil || len(notifications) != 1 {
+				t.Fatalf("Notifications() = %v, %v", notifications, err)
+			}
+
+			err = db.NotificationSnooze(notifications[0].ID, c.now.Add(tt.snooze))
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRunFirstLeads(t *testing.T) {
+	setup(t)
+
+	due := time.Date(2026, time.October, 20, 18, 0, 0, 0, time.Local)
+	err := db.TaskCreate("Call the bank", &due)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first run after both leads shows only the smallest one, the larger one is recorded as shown
+	n := &notifier{}
+	s := &Scheduler{Notifier: n, Clock: &clock{now: due.Add(-10 * time.Minute)}, Leads: []time.Duration{time.Hour, 15 * time.Minute}}
+	for range 2 {
+		err = s.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(n.shown) != 1 || n.shown[0] != "aio: Call the bank" {
+		t.Errorf("reminders shown %q, want one of Call the bank", n.shown)
+	}
+
+	notifications, err := db.Notifications(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notifications) != 2 {
+		t.Errorf("%d notifications recorded, want 2", len(notifications))
+	}
+}
@@ -0,0 +1,132 @@
+// transfer package csv format functions
+package transfer
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// writeCSV function writes the tables as a csv export, a file for every table in the directory dir.
+// the first line of a file has the names of the columns, the null values are empty cells.
+func writeCSV(dir string, ts []db.Table) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		log.Err("failed to create the export directory", "dir", dir)
+		return err
+	}
+
+	for _, t := range ts {
+		file, err := os.Create(filepath.Join(dir, t.Name+".csv"))
+		if err != nil {
+			log.Err("failed to create the export file", "table", t.Name)
+			return err
+		}
+
+		w := csv.NewWriter(file)
+		w.Write(t.Columns)
+		for _, r := range t.Rows {
+			record := make([]string, len(r))
+			for i, v := range r {
+				record[i] = text(v)
+			}
+			w.Write(record)
+		}
+
+		w.Flush()
+		err = errors.Join(w.Error(), file.Close())
+		if err != nil {
+			log.Err("failed to write the export file", "table", t.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readCSV function reads the tables of a csv export, the path is the directory of the export or a single file.
+// the name of a table is the name of its file, the empty cells are null in the columns that can be null.
+func readCSV(path string) ([]db.Table, error) {
+	files := []string{path}
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Err("failed to open the export", "path", path)
+		return nil, err
+	}
+
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.csv"))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) == 0 {
+			return nil, errors.Join(ErrInvalid, errors.New("no csv files found in "+path))
+		}
+		slices.Sort(files)
+	}
+
+	ts := []db.Table{}
+	for _, f := range files {
+		t, err := readTable(f)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+
+	return ts, nil
+}
+
+// readTable function reads a csv file of an export, the table named after the file.
+func readTable(file string) (db.Table, error) {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	t := db.Table{Name: name, Rows: [][]any{}}
+
+	f, err := os.Open(file)
+	if err != nil {
+		log.Err("failed to open the export file", "file", file)
+		return t, err
+	}
+
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return t, errors.Join(ErrInvalid, errors.New("the csv of "+file+" can't be read"), err)
+	}
+
+	if len(records) == 0 {
+		return t, errors.Join(ErrInvalid, errors.New(file+" is missing the header with the columns"))
+	}
+
+	columns, err := db.Columns(name)
+	if err != nil {
+		return t, err
+	}
+
+	// the columns that can be null, the other ones get an empty string from an empty cell
+	nullable := map[string]bool{}
+	for _, c := range columns {
+		nullable[c.Name] = !c.NotNull
+	}
+
+	t.Columns = records[0]
+	for _, record := range records[1:] {
+		values := make([]any, len(record))
+		for i, v := range record {
+			values[i] = v
+			if v == "" && i < len(t.Columns) && nullable[t.Columns[i]] {
+				values[i] = nil
+			}
+		}
+		t.Rows = append(t.Rows, values)
+	}
+
+	return t, nil
+}
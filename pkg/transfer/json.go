@@ -0,0 +1,191 @@
+// transfer package json format functions
+package transfer
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// document struct represents a json export, the tables are objects with the rows of the table.
+// the schema is the version of the schema of the database, an export can't be imported by an older version of aio.
+type document struct {
+	Schema     int    `json:"schema"`
+	ExportedAt string `json:"exported_at"`
+	Tables     tables `json:"tables"`
+}
+
+// tables type represents the tables of a json export, written in the order of the export.
+type tables []db.Table
+
+// MarshalJSON function writes the tables as an object, every row is an object with the keys in the order of the columns.
+func (ts tables) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, t := range ts {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		name, err := json.Marshal(t.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(name)
+		b.WriteString(":[")
+		for j, r := range t.Rows {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+
+			b.WriteByte('{')
+			for k, c := range t.Columns {
+				if k > 0 {
+					b.WriteByte(',')
+				}
+
+				v := r[k]
+				if raw, ok := v.([]byte); ok {
+					v = string(raw)
+				}
+
+				key, err := json.Marshal(c)
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+
+				b.Write(key)
+				b.WriteByte(':')
+				b.Write(value)
+			}
+			b.WriteByte('}')
+		}
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// writeJSON function writes the tables as a json export.
+func writeJSON(w io.Writer, version int, ts []db.Table) error {
+	doc := document{Schema: version, ExportedAt: time.Now().Format(time.RFC3339), Tables: ts}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Err("failed to encode the export")
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// readJSON function reads the tables of a json export, from the standard input if path is "-".
+// the rows of a table must have the same keys, the columns of the table.
+func readJSON(path string) ([]db.Table, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Err("failed to open the export file", "file", path)
+			return nil, err
+		}
+
+		defer file.Close()
+		r = file
+	}
+
+	var doc struct {
+		Schema *int                        `json:"schema"`
+		Tables map[string][]map[string]any `json:"tables"`
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber() // the integers are kept as they are, not as floats
+	err := dec.Decode(&doc)
+	if err != nil {
+		return nil, errors.Join(ErrInvalid, errors.New("the json of "+path+" can't be read"), err)
+	}
+
+	if doc.Schema == nil || doc.Tables == nil {
+		return nil, errors.Join(ErrInvalid, errors.New(path+" is missing the schema or the tables"))
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	if *doc.Schema > version {
+		return nil, errors.Join(ErrInvalid, errors.New(path+" has been created by a newer version of aio (schema "+strconv.Itoa(*doc.Schema)+"), update aio to import it"))
+	}
+
+	names := []string{}
+	for name := range doc.Tables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	result := []db.Table{}
+	for _, name := range names {
+		t := db.Table{Name: name, Columns: []string{}, Rows: [][]any{}}
+		for i, obj := range doc.Tables[name] {
+			if i == 0 {
+				for c := range obj {
+					t.Columns = append(t.Columns, c)
+				}
+				slices.Sort(t.Columns)
+			}
+
+			if len(obj) != len(t.Columns) {
+				return nil, errors.Join(ErrInvalid, errors.New("table "+name+": row "+strconv.Itoa(i+1)+" has different columns than the first row"))
+			}
+
+			values := make([]any, len(t.Columns))
+			for j, c := range t.Columns {
+				v, ok := obj[c]
+				if !ok {
+					return nil, errors.Join(ErrInvalid, errors.New("table "+name+": row "+strconv.Itoa(i+1)+" is missing the column "+c))
+				}
+
+				values[j], err = jsonValue(v)
+				if err != nil {
+					return nil, errors.Join(ErrInvalid, errors.New("table "+name+": row "+strconv.Itoa(i+1)+", column "+c), err)
+				}
+			}
+			t.Rows = append(t.Rows, values)
+		}
+
+		// the rows of an older schema, e.g. the amounts before the currencies, are upgraded to the current one
+		result = append(result, db.Upgrade(t, *doc.Schema))
+	}
+
+	return result, nil
+}
+
+// jsonValue function converts a json value to a value of the database.
+func jsonValue(v any) (any, error) {
+	switch v := v.(type) {
+	case nil, string, bool:
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	default:
+		return nil, errors.New("the value must be a string, a number, a boolean or null")
+	}
+}
@@ -0,0 +1,40 @@
+// transfer package markdown format functions
+package transfer
+
+import (
+	"aio/pkg/db"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// mdCell replaces the characters that break a cell of a markdown table.
+var mdCell = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// writeMD function writes the tables as a markdown export, to read the data. it can't be imported.
+func writeMD(w io.Writer, version int, ts []db.Table) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# aio export\n\nExported at %s, schema %d.\n", time.Now().Format("2006-01-02 15:04"), version)
+
+	for _, t := range ts {
+		fmt.Fprintf(&b, "\n## %s\n\n", t.Name)
+		if len(t.Rows) == 0 {
+			b.WriteString("No rows.\n")
+			continue
+		}
+
+		b.WriteString("| " + strings.Join(t.Columns, " | ") + " |\n")
+		b.WriteString(strings.Repeat("| --- ", len(t.Columns)) + "|\n")
+		for _, r := range t.Rows {
+			cells := make([]string, len(r))
+			for i, v := range r {
+				cells[i] = mdCell.Replace(text(v))
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
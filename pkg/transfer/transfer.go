@@ -0,0 +1,154 @@
+// transfer package exports the data of the database to files, and imports them back.
+// the exports are json, csv or markdown files, the imports are json or csv files.
+package transfer
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/log"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// formats of the exports
+const (
+	FormatJSON = "json" // a single file with every table, it can be imported
+	FormatCSV  = "csv"  // a directory with a file for every table, it can be imported
+	FormatMD   = "md"   // a single file with a table for every table, to read the data
+)
+
+// Formats are the formats of the exports.
+var Formats = []string{FormatJSON, FormatCSV, FormatMD}
+
+// ErrFormat is returned when the format is unknown, or it can't be used for the operation.
+var ErrFormat = errors.New("invalid format")
+
+// ErrInvalid is returned when an export can't be read, or it has been created by a newer version of aio.
+var ErrInvalid = errors.New("invalid export")
+
+// Detect function returns the format of an export from its path: a directory is a csv export,
+// a file is detected by its extension. it returns an empty string if the format is unknown.
+func Detect(path string) string {
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		return FormatCSV
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if ext == "markdown" {
+		ext = FormatMD
+	}
+
+	if slices.Contains(Formats, ext) {
+		return ext
+	}
+	return ""
+}
+
+// Export function exports the tables with the given names, or every table if names is empty, in the given format.
+// the json and md exports are written to the file path, or to the standard output if path is empty,
+// the csv export is written to the directory path, with a file for every table.
+func Export(format, path string, names []string) error {
+	if format == FormatCSV && path == "" {
+		return errors.Join(ErrFormat, errors.New("the csv export needs a directory, pass it with --output"))
+	}
+
+	tables, err := db.Export(names)
+	if err != nil {
+		log.Err("failed to read the tables")
+		return err
+	}
+
+	if format == FormatCSV {
+		return writeCSV(path, tables)
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			log.Err("failed to create the export file", "file", path)
+			return err
+		}
+
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, version, tables)
+	case FormatMD:
+		return writeMD(w, version, tables)
+	default:
+		return errors.Join(ErrFormat, errors.New("unknown format "+format+", the formats are: "+strings.Join(Formats, ", ")))
+	}
+}
+
+// Read function reads the tables of an export in the given format, to be imported with db.Import.
+// the path of a csv export is a directory, or a single file named after its table.
+// the json export is read from the standard input if path is "-".
+// only the tables with the given names are returned, or every table if names is empty.
+func Read(format, path string, names []string) ([]db.Table, error) {
+	var tables []db.Table
+	var err error
+	switch format {
+	case FormatJSON:
+		tables, err = readJSON(path)
+	case FormatCSV:
+		tables, err = readCSV(path)
+	case FormatMD:
+		return nil, errors.Join(ErrFormat, errors.New("the markdown exports can't be imported, use json or csv"))
+	default:
+		return nil, errors.Join(ErrFormat, errors.New("unknown format "+format+", the formats are: "+FormatJSON+", "+FormatCSV))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		return tables, nil
+	}
+
+	selected := []db.Table{}
+	for _, name := range names {
+		i := slices.IndexFunc(tables, func(t db.Table) bool { return t.Name == name })
+		if i < 0 {
+			return nil, errors.Join(ErrInvalid, errors.New("table "+name+" not found in "+path))
+		}
+		selected = append(selected, tables[i])
+	}
+
+	return selected, nil
+}
+
+// text function returns the value of the database as text, an empty string for null.
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		log.Warn("unexpected value type in the database", "value", v)
+		return ""
+	}
+}
@@ -0,0 +1,210 @@
+package transfer
+
+import (
+	"aio/pkg/db"
+	"aio/pkg/utils/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setup function initializes a database with a character in a temporary directory.
+func setup(t *testing.T) {
+	t.Helper()
+	err := fs.SetHome(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.SetProfile("")
+	err = db.Init(db.Seed{FirstName: "Jane", LastName: "Smith", NickName: "Jay", BirthDate: "02 Jan 2000", Currency: "EUR", Budget: "1500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fill function adds rows to the tables of the database: a profile, tasks, a habit, transactions and a budget.
+func fill(t *testing.T) {
+	t.Helper()
+	_, err := db.ProfileCreate(db.Seed{FirstName: "John", LastName: "Smith", NickName: "Jo", BirthDate: "03 Mar 1998", Currency: "JPY", Budget: "50000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	due := time.Date(2026, time.October, 20, 18, 0, 0, 0, time.Local)
+	for _, title := range []string{"Pay the rent", "Call \"the\" bank, today"} {
+		err = db.TaskCreate(title, &due)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = db.TaskCreate("Fix the bike", nil) // a null due date
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.HabitCreate("Run", "mon,wed", "07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	_, err = db.TransactionsImport([]db.Transaction{
+		{Date: day, Amount: -250, Currency: "EUR", Description: "Coffee", Payee: "Bar", Account: "IT60X"},
+		{Date: day, Amount: 250000, Currency: "EUR", Description: "Salary\nOctober", Account: "IT60X"},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.BudgetSet("groceries", 40000, true, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// dump function returns the rows of every table of the database by the name of the table.
+// the journal is left out, the import records itself in it.
+func dump(t *testing.T) map[string]db.Table {
+	t.Helper()
+	exported, err := db.Export(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := map[string]db.Table{}
+	for _, tb := range exported {
+		if tb.Name != "journal" {
+			byName[tb.Name] = tb
+		}
+	}
+	return byName
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			setup(t)
+			fill(t)
+			want := dump(t)
+
+			path := filepath.Join(t.TempDir(), "export")
+			if format == FormatJSON {
+				path += ".json"
+			}
+
+			err := Export(format, path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := Detect(path); got != format {
+				t.Errorf("Detect(%s) = %q, want %q", path, got, format)
+			}
+
+			// imported in a fresh home, with only its first character
+			setup(t)
+			read, err := Read(format, path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = db.Import(read, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := dump(t)
+			for name, table := range want {
+				if !reflect.DeepEqual(got[name], table) {
+					t.Errorf("table %s imported as\n%v\nwant\n%v", name, got[name], table)
+				}
+			}
+
+			if len(got) != len(want) {
+				t.Errorf("%d tables imported, want %d", len(got), len(want))
+			}
+		})
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	setup(t)
+	fill(t)
+
+	path := filepath.Join(t.TempDir(), "export.json")
+	err := Export(FormatJSON, path, []string{"tasks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.TaskCreate("Water the plants", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := db.Export(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := Read(FormatJSON, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := db.Import(read, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []db.Imported{{Table: "tasks", Replaced: 4, Rows: 3}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Import() = %v, want %v", results, want)
+	}
+
+	// nothing changed, not even the journal
+	after, err := db.Export(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(after, before) {
+		t.Error("the dry run changed the database")
+	}
+}
+
+func TestRead(t *testing.T) {
+	setup(t)
+	fill(t)
+
+	path := filepath.Join(t.TempDir(), "export.md")
+	err := Export(FormatMD, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the markdown exports are only to read the data
+	_, err = Read(FormatMD, path, nil)
+	if err == nil {
+		t.Error("Read() of a markdown export returns no error")
+	}
+
+	path = filepath.Join(t.TempDir(), "export.json")
+	err = Export(FormatJSON, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := Read(FormatJSON, path, []string{"habits", "tasks"})
+	if err != nil || len(read) != 2 || read[0].Name != "habits" || read[1].Name != "tasks" {
+		t.Errorf("Read() of two tables = %v, %v", read, err)
+	}
+
+	_, err = Read(FormatJSON, path, []string{"pets"})
+	if err == nil {
+		t.Error("Read() of a missing table returns no error")
+	}
+}
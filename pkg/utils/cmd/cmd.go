@@ -10,10 +10,10 @@ import (
 
 // Output function executes a command and returns the output.
 // it is used to execute a command and return the output.
-// it also sets the working directory to the executable directory.
+// it also sets the working directory to the data directory.
 func Output(name string, args ...string) ([]byte, error) {
 	cmd := exec.Command(name, args...)
-	dir, err := fs.ExecDir()
+	dir, err := fs.DataDir()
 	if err != nil {
 		return nil, err
 	}
@@ -25,10 +25,10 @@ func Output(name string, args ...string) ([]byte, error) {
 
 // Start function starts a command.
 // it is used to start a command.
-// it also sets the working directory to the executable directory.
+// it also sets the working directory to the data directory.
 func Start(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
-	dir, err := fs.ExecDir()
+	dir, err := fs.DataDir()
 	if err != nil {
 		return err
 	}
@@ -37,22 +37,47 @@ func Start(name string, args ...string) error {
 	return cmd.Start()
 }
 
+// StartDetached function starts a binary in background, detached from the current process.
+// it is used to start a binary that must keep running after the current process exits.
+// it returns the pid of the started process.
+func StartDetached(binPath string, args ...string) (int, error) {
+	cmd := exec.Command(binPath, args...)
+	dir, err := fs.DataDir()
+	if err != nil {
+		return 0, err
+	}
+
+	cmd.Dir = dir
+	detach(cmd)
+	err = cmd.Start()
+	if err != nil {
+		return 0, errors.New("failed to start binary: " + err.Error())
+	}
+
+	pid := cmd.Process.Pid
+	return pid, cmd.Process.Release() // release the process, it is not waited by the current process
+}
+
 // StartBinaryWithInhibitSystemSleep function starts a binary with an inhibit system sleep.
 // it is used to start a binary with an inhibit system sleep.
 // it prevents the system from going to sleep while the binary is running.
-// it returns an error if the operating system is not supported.
+// the binary is started in background, detached from the current process.
+// if the tool to inhibit the system sleep is not available, the binary is started without it.
 // it returns an error if the command fails to start.
-func StartBinaryWithInhibitSystemSleep(binPath string) error {
-	var cmd *exec.Cmd
+func StartBinaryWithInhibitSystemSleep(binPath string, args ...string) (int, error) {
+	var name string
+	var cmdArgs []string
 
 	// check the operating system and run the appropriate command
 	switch runtime.GOOS {
 	case "darwin":
 		// -i: prevent idle sleep -s: prevent system sleep
-		cmd = exec.Command("caffeinate", "-i", "-s", binPath)
+		name = "caffeinate"
+		cmdArgs = append([]string{"-i", "-s", binPath}, args...)
 	case "linux":
 		// --why: reason for inhibition --mode: block the system sleep
-		cmd = exec.Command("systemd-inhibit", "--why=Prevent sleep", "--mode=block", binPath)
+		name = "systemd-inhibit"
+		cmdArgs = append([]string{"--why=Prevent sleep", "--mode=block", binPath}, args...)
 	case "windows":
 		// powershell command to prevent system sleep
 		powershellCmd := `
@@ -64,19 +89,14 @@ func StartBinaryWithInhibitSystemSleep(binPath string) error {
         `
 
 		// run the powershell command
-		cmd = exec.Command("powershell", "-Command", powershellCmd)
-	default:
-		// at this moment, only darwin, linux, and windows are supported
-		// if the operating system is not supported, return an error
-		return errors.New("unsupported operating system: " + runtime.GOOS)
+		name = "powershell"
+		cmdArgs = []string{"-Command", powershellCmd}
 	}
 
-	// start the command
-	// return an error if the command fails to start
-	err := cmd.Run()
-	if err != nil {
-		return errors.New("failed to start binary with inhibit system sleep: " + err.Error())
+	if _, err := exec.LookPath(name); name == "" || err != nil {
+		// the operating system is not supported, or the tool is not installed
+		return StartDetached(binPath, args...)
 	}
 
-	return nil
+	return StartDetached(name, cmdArgs...)
 }
@@ -0,0 +1,34 @@
+//go:build !windows
+
+// cmd package, unix process functions
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach function sets the command to run in a new session,
+// so it is not stopped when the terminal that started it is closed.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// Alive function checks if a process is running.
+func Alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil // signal 0 checks the process without sending a signal
+}
+
+// Terminate function asks a process to stop gracefully.
+func Terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGTERM)
+}
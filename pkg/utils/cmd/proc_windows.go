@@ -0,0 +1,39 @@
+//go:build windows
+
+// cmd package, windows process functions
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess is the windows creation flag to run a process without a console.
+const detachedProcess = 0x00000008
+
+// detach function sets the command to run without a console and in a new process group,
+// so it is not stopped when the terminal that started it is closed.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// Alive function checks if a process is running.
+func Alive(pid int) bool {
+	p, err := os.FindProcess(pid) // on windows it fails if the process does not exist
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
+
+// Terminate function stops a process.
+// windows does not support SIGTERM, so the process is killed.
+func Terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,114 @@
+// fs package, data directories
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// home is the directory of every file of aio, set with SetHome or the AIO_HOME variable.
+// when it is empty the files are split in the data, state and config directories of the XDG specification.
+var home = os.Getenv("AIO_HOME")
+
+// SetHome function sets the directory of every file of aio, like the --data-dir flag.
+// it sets also the AIO_HOME variable, so the processes started by aio, like the cron service, use the same directory.
+// an empty dir keeps the current one.
+func SetHome(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.New("failed to get the absolute path of the data directory: " + err.Error())
+	}
+
+	home = abs
+	return os.Setenv("AIO_HOME", abs)
+}
+
+// Home function returns the directory set with SetHome or AIO_HOME, empty if the XDG directories are used.
+func Home() string {
+	return home
+}
+
+// xdgDir function returns the aio directory inside the XDG base directory of the env variable,
+// or inside the fallback if the variable is not set.
+// on windows the XDG variables are not used, the data and state are kept in the local app data directory
+// and the config in the roaming one.
+func xdgDir(env string, fallback ...string) (string, error) {
+	if home != "" {
+		return home, nil
+	}
+
+	if runtime.GOOS == "windows" {
+		if env == "XDG_CONFIG_HOME" {
+			return filepath.Join(os.Getenv("APPDATA"), "aio"), nil
+		}
+		return filepath.Join(os.Getenv("LOCALAPPDATA"), "aio"), nil
+	}
+
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "aio"), nil
+	}
+
+	userHome, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New("failed to get the home directory: " + err.Error())
+	}
+
+	return filepath.Join(append([]string{userHome}, append(fallback, "aio")...)...), nil
+}
+
+// ensure function creates a directory if it does not exist and returns it.
+func ensure(dir string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", errors.New("failed to create the directory " + dir + ": " + err.Error())
+	}
+
+	return dir, nil
+}
+
+// DataDir function returns the directory of the versioned data: the database, its git repository and the backups.
+// by default it is $XDG_DATA_HOME/aio (~/.local/share/aio).
+func DataDir() (string, error) {
+	return ensure(xdgDir("XDG_DATA_HOME", ".local", "share"))
+}
+
+// StateDir function returns the directory of the runtime data: the state database, the logs,
+// the sync status and the files of the cron service.
+// by default it is $XDG_STATE_HOME/aio (~/.local/state/aio).
+func StateDir() (string, error) {
+	return ensure(xdgDir("XDG_STATE_HOME", ".local", "state"))
+}
+
+// ConfigDir function returns the directory of the config files.
+// by default it is $XDG_CONFIG_HOME/aio (~/.config/aio).
+func ConfigDir() (string, error) {
+	return ensure(xdgDir("XDG_CONFIG_HOME", ".config"))
+}
+
+// StatePath function returns the full path of a file in the state directory.
+func StatePath(path string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, path), nil
+}
+
+// ConfigPath function returns the full path of a file in the config directory.
+func ConfigPath(path string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, path), nil
+}
@@ -11,8 +11,8 @@ import (
 )
 
 // ExecDir function returns the directory of the executable file.
-// it is used to run all the commands in the directory of the executable file.
-// this maintaning the integrity of the data.
+// it is used to find the binaries installed with aio, like the cron service,
+// and the data of the previous versions, kept next to the executable.
 func ExecDir() (string, error) {
 	execPath, err := os.Executable()
 	if err != nil {
@@ -21,14 +21,22 @@ func ExecDir() (string, error) {
 	return filepath.Dir(execPath), nil
 }
 
-// Path function returns the full path of a file.
-// it is use to retrieve the path from the executable file.
+// Path function returns the full path of a file in the data directory.
 func Path(path string) (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, path), nil
+}
+
+// BinPath function returns the full path of a binary installed next to the executable.
+func BinPath(name string) (string, error) {
 	execDir, err := ExecDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(execDir, path), nil
+	return filepath.Join(execDir, name), nil
 }
 
 // DBfile function returns the full path of the database file.
@@ -0,0 +1,202 @@
+// fs package, migration of the data kept next to the executable
+package fs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// legacyFiles maps the files kept next to the executable by the previous versions to the function
+// that returns their new directory.
+var legacyFiles = []struct {
+	pattern string
+	dir     func() (string, error)
+}{
+	{"data.db", DataDir},
+	{".git", DataDir},
+	{".gitignore", DataDir},
+	{"data_backup_*.db", DataDir},
+	{"state.db", StateDir},
+	{"sync.json", StateDir},
+	{"logs", StateDir},
+	{"jobs.json", ConfigDir},
+}
+
+// execDir returns the directory of the executable, where the previous versions kept their data.
+var execDir = ExecDir
+
+// LegacyDir function returns the directory of the data of the previous versions, next to the executable,
+// and true if it contains a database that has not been migrated yet.
+// the directory is not legacy when it is the data directory itself, e.g. with AIO_HOME set to it.
+func LegacyDir() (string, bool) {
+	dir, err := execDir()
+	if err != nil {
+		return "", false
+	}
+
+	dataDir, err := xdgDir("XDG_DATA_HOME", ".local", "share")
+	if err != nil || filepath.Clean(dataDir) == filepath.Clean(dir) {
+		return dir, false
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "data.db")); err != nil {
+		return dir, false
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, "data.db")); err == nil {
+		return dir, false // the data directory already has a database, the legacy one is left as it is
+	}
+
+	return dir, true
+}
+
+// MigrateLegacy function moves the data kept next to the executable by the previous versions
+// to the data, state and config directories, and returns the paths of the moved files.
+// it does nothing if there is no data to migrate, so it runs only once.
+// the cron service of the previous version must be stopped before.
+func MigrateLegacy() ([]string, error) {
+	legacy, ok := LegacyDir()
+	if !ok {
+		return nil, nil
+	}
+
+	moved := []string{}
+	for _, f := range legacyFiles {
+		matches, err := filepath.Glob(filepath.Join(legacy, f.pattern))
+		if err != nil {
+			return moved, err
+		}
+
+		if len(matches) == 0 {
+			continue
+		}
+
+		dir, err := f.dir()
+		if err != nil {
+			return moved, err
+		}
+
+		for _, src := range matches {
+			paths, err := merge(src, filepath.Join(dir, filepath.Base(src)))
+			moved = append(moved, paths...)
+			if err != nil {
+				return moved, err
+			}
+		}
+	}
+
+	return moved, nil
+}
+
+// merge function moves src to dst and returns the moved paths.
+// if both are directories, the entries of src are merged in dst, e.g. the logs directory
+// already created by the new version. the files of the new layout are never overwritten.
+func merge(src, dst string) ([]string, error) {
+	dstInfo, err := os.Stat(dst)
+	if os.IsNotExist(err) {
+		err = move(src, dst)
+		if err != nil {
+			return nil, errors.New("failed to move " + src + " to " + dst + ": " + err.Error())
+		}
+		return []string{dst}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	// a file with the same name is kept with the time of its last change, e.g. aio-20241020-153000.log,
+	// the name of a rotated log file
+	if !srcInfo.IsDir() && !dstInfo.IsDir() {
+		ext := filepath.Ext(dst)
+		stamped := strings.TrimSuffix(dst, ext) + "-" + srcInfo.ModTime().Format("20060102-150405") + ext
+		if _, err := os.Stat(stamped); err == nil {
+			return nil, nil
+		}
+		return merge(src, stamped)
+	}
+
+	if !srcInfo.IsDir() || !dstInfo.IsDir() {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := []string{}
+	for _, e := range entries {
+		paths, err := merge(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name()))
+		moved = append(moved, paths...)
+		if err != nil {
+			return moved, err
+		}
+	}
+
+	os.Remove(src) // removed only if empty
+	return moved, nil
+}
+
+// move function moves a file or a directory.
+// when it can't be renamed, e.g. to another file system, it is copied and then removed.
+func move(src, dst string) error {
+	if os.Rename(src, dst) == nil {
+		return nil
+	}
+
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+
+		return copyFile(path, target, info.Mode().Perm())
+	})
+
+	if err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+
+	return os.RemoveAll(src)
+}
+
+// copyFile function copies a file, with the given permissions.
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
@@ -0,0 +1,181 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// legacy function sets up the XDG directories in a temp HOME and a legacy directory of the executable
+// with the given files, by their path and content, and returns the HOME.
+func legacy(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(root, "data"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(root, "state"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "config"))
+
+	h, exec := home, execDir
+	home = ""
+	execDir = func() (string, error) { return filepath.Join(root, "bin"), nil }
+	t.Cleanup(func() { home, execDir = h, exec })
+
+	write(t, filepath.Join(root, "bin", "aio"), "binary")
+	for path, content := range files {
+		write(t, filepath.Join(root, "bin", path), content)
+	}
+	return root
+}
+
+// write function writes a file, creating its directory.
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read function returns the content of a file, empty if it does not exist.
+func read(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	root := legacy(t, map[string]string{
+		"data.db":                 "database",
+		".git/HEAD":               "ref: refs/heads/main",
+		"data_backup_20241020.db": "backup",
+		"state.db":                "state",
+		"sync.json":               "{}",
+		"logs/aio.log":            "cli logs",
+		"jobs.json":               "[]",
+	})
+
+	if _, ok := LegacyDir(); !ok {
+		t.Fatal("LegacyDir() finds no data to migrate")
+	}
+
+	moved, err := MigrateLegacy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"data/aio/data.db":                 "database",
+		"data/aio/.git/HEAD":               "ref: refs/heads/main",
+		"data/aio/data_backup_20241020.db": "backup",
+		"state/aio/state.db":               "state",
+		"state/aio/sync.json":              "{}",
+		"state/aio/logs/aio.log":           "cli logs",
+		"config/aio/jobs.json":             "[]",
+	}
+
+	for path, content := range want {
+		if got := read(t, filepath.Join(root, path)); got != content {
+			t.Errorf("%s = %q, want %q", path, got, content)
+		}
+	}
+
+	if len(moved) != len(want) {
+		t.Errorf("moved %q, want the %d files of the previous version", moved, len(want))
+	}
+
+	// only the executable is left
+	entries, err := os.ReadDir(filepath.Join(root, "bin"))
+	if err != nil || len(entries) != 1 || entries[0].Name() != "aio" {
+		t.Errorf("the directory of the executable has %v, %v, want only the executable", entries, err)
+	}
+
+	// a second run does nothing
+	if _, ok := LegacyDir(); ok {
+		t.Error("LegacyDir() finds data to migrate after the migration")
+	}
+
+	moved, err = MigrateLegacy()
+	if err != nil || len(moved) != 0 {
+		t.Errorf("second MigrateLegacy() = %q, %v, want nothing moved", moved, err)
+	}
+
+	if got := read(t, filepath.Join(root, "data/aio/data.db")); got != "database" {
+		t.Errorf("data.db = %q after the second run", got)
+	}
+}
+
+func TestMigrateLegacyMerge(t *testing.T) {
+	root := legacy(t, map[string]string{
+		"data.db":       "database",
+		"logs/aio.log":  "old cli logs",
+		"logs/cron.log": "old cron logs",
+		"jobs.json":     "[old]",
+	})
+
+	// the new version already wrote its logs and its config before the migration
+	write(t, filepath.Join(root, "state/aio/logs/aio.log"), "new cli logs")
+	write(t, filepath.Join(root, "config/aio/jobs.json"), "[new]")
+
+	modified := time.Date(2024, time.October, 20, 15, 30, 0, 0, time.Local)
+	for _, f := range []string{"logs/aio.log", "jobs.json"} {
+		err := os.Chtimes(filepath.Join(root, "bin", f), modified, modified)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, err := MigrateLegacy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"data/aio/data.db":                       "database",
+		"state/aio/logs/aio.log":                 "new cli logs",
+		"state/aio/logs/aio-20241020-153000.log": "old cli logs",
+		"state/aio/logs/cron.log":                "old cron logs",
+		"config/aio/jobs.json":                   "[new]",
+		"config/aio/jobs-20241020-153000.json":   "[old]",
+	}
+
+	for path, content := range want {
+		if got := read(t, filepath.Join(root, path)); got != content {
+			t.Errorf("%s = %q, want %q", path, got, content)
+		}
+	}
+
+	if len(moved) != 4 {
+		t.Errorf("moved %q, want the 4 files of the previous version", moved)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "bin", "logs")); !os.IsNotExist(err) {
+		t.Errorf("the legacy logs directory is left: %v", err)
+	}
+}
+
+func TestLegacyDirHome(t *testing.T) {
+	root := legacy(t, map[string]string{"data.db": "database"})
+
+	// with AIO_HOME set to the directory of the executable its data is not legacy
+	home = filepath.Join(root, "bin")
+	if _, ok := LegacyDir(); ok {
+		t.Error("LegacyDir() finds data to migrate in the home directory")
+	}
+
+	// the data directory already has a database, the legacy one is left as it is
+	home = ""
+	write(t, filepath.Join(root, "data/aio/data.db"), "new database")
+	if _, ok := LegacyDir(); ok {
+		t.Error("LegacyDir() finds data to migrate with a database in the data directory")
+	}
+}
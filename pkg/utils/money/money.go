@@ -0,0 +1,111 @@
+// money package handles the amounts of money, stored as integer minor units of their currency, e.g. cents.
+// the amounts are never kept as floats, so their sums have no rounding drift.
+package money
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// exponents are the numbers of decimals of the ISO 4217 currencies without two decimals.
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Exponent function returns the number of decimals of a currency, 2 for the currencies not listed in exponents.
+func Exponent(currency string) int {
+	if e, ok := exponents[currency]; ok {
+		return e
+	}
+	return 2
+}
+
+// Currency function validates and normalizes a currency code, three letters of ISO 4217, e.g. "eur" is EUR.
+func Currency(s string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", errors.New("invalid currency " + strconv.Quote(s) + ", use a three letters ISO 4217 code, e.g. EUR or USD")
+	}
+	return code, nil
+}
+
+// pow function returns 10 to the power of n.
+func pow(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// Parse function parses an amount written with the dot as decimal separator, e.g. "-1234.5",
+// and returns it in minor units of the currency. the amount can't have more decimals than the currency.
+func Parse(s, currency string) (int64, error) {
+	original := s
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+
+	whole, decimals, _ := strings.Cut(s, ".")
+	exp := Exponent(currency)
+	if len(decimals) > exp {
+		return 0, errors.New("invalid amount " + strconv.Quote(original) + ", " + currency + " has " + strconv.Itoa(exp) + " decimals")
+	}
+
+	if whole == "" && decimals == "" || strings.Trim(whole+decimals, "0123456789") != "" {
+		return 0, errors.New("invalid amount " + strconv.Quote(original))
+	}
+
+	decimals += strings.Repeat("0", exp-len(decimals))
+	n, err := strconv.ParseInt("0"+whole+decimals, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid amount " + strconv.Quote(original) + ", it is too large")
+	}
+
+	if negative {
+		n = -n
+	}
+	return n, nil
+}
+
+// Format function formats an amount in minor units of a currency, with its decimals, e.g. 123456 EUR is "1234.56".
+func Format(minor int64, currency string) string {
+	exp := Exponent(currency)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	s := strconv.FormatInt(minor, 10)
+	if exp == 0 {
+		return sign + s
+	}
+
+	s = strings.Repeat("0", max(exp+1-len(s), 0)) + s
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+// Major function returns an amount in minor units as a number of units of the currency, e.g. 1250 EUR is 12.5.
+// it is used to show the amounts and to compute averages, never to store them.
+func Major(minor int64, currency string) float64 {
+	return float64(minor) / float64(pow(Exponent(currency)))
+}
+
+// Minor function returns a number of units of a currency in minor units, rounded half away from zero.
+func Minor(major float64, currency string) int64 {
+	return int64(math.Round(major * float64(pow(Exponent(currency)))))
+}
+
+// Convert function converts an amount in minor units of a currency to another currency with an exchange rate,
+// the number of units of the target currency for one unit of the source currency. it is rounded half away from zero.
+func Convert(minor int64, from, to string, rate float64) int64 {
+	if from == to {
+		return minor
+	}
+	return Minor(Major(minor, from)*rate, to)
+}
@@ -0,0 +1,141 @@
+package money
+
+import "testing"
+
+func TestCurrency(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    string
+		invalid bool
+	}{
+		{s: "EUR", want: "EUR"},
+		{s: " jpy ", want: "JPY"},
+		{s: "", invalid: true},
+		{s: "EURO", invalid: true},
+		{s: "E1R", invalid: true},
+		{s: "€", invalid: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Currency(tt.s)
+		if (err != nil) != tt.invalid || got != tt.want {
+			t.Errorf("Currency(%q) = %q, %v, want %q", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s        string
+		currency string
+		want     int64
+		invalid  bool
+	}{
+		{s: "1234.56", currency: "EUR", want: 123456},
+		{s: "-1234.5", currency: "EUR", want: -123450},
+		{s: "+12", currency: "EUR", want: 1200},
+		{s: " 0.05 ", currency: "USD", want: 5},
+		{s: ".5", currency: "EUR", want: 50},
+		{s: "12.", currency: "EUR", want: 1200},
+		{s: "1234", currency: "JPY", want: 1234},
+		{s: "1.234", currency: "KWD", want: 1234},
+		{s: "-0", currency: "EUR", want: 0},
+		{s: "12.345", currency: "EUR", invalid: true},
+		{s: "12.5", currency: "JPY", invalid: true},
+		{s: "", currency: "EUR", invalid: true},
+		{s: "-", currency: "EUR", invalid: true},
+		{s: ".", currency: "EUR", invalid: true},
+		{s: "1,234.56", currency: "EUR", invalid: true},
+		{s: "12a", currency: "EUR", invalid: true},
+		{s: "1.2.3", currency: "EUR", invalid: true},
+		{s: "99999999999999999999", currency: "EUR", invalid: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.s, tt.currency)
+		if (err != nil) != tt.invalid || got != tt.want {
+			t.Errorf("Parse(%q, %s) = %d, %v, want %d", tt.s, tt.currency, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		currency string
+		want     string
+	}{
+		{minor: 123456, currency: "EUR", want: "1234.56"},
+		{minor: -123456, currency: "EUR", want: "-1234.56"},
+		{minor: 5, currency: "EUR", want: "0.05"},
+		{minor: -5, currency: "EUR", want: "-0.05"},
+		{minor: 0, currency: "EUR", want: "0.00"},
+		{minor: 1234, currency: "JPY", want: "1234"},
+		{minor: -1234, currency: "JPY", want: "-1234"},
+		{minor: 1234, currency: "KWD", want: "1.234"},
+		{minor: 7, currency: "KWD", want: "0.007"},
+	}
+
+	for _, tt := range tests {
+		got := Format(tt.minor, tt.currency)
+		if got != tt.want {
+			t.Errorf("Format(%d, %s) = %q, want %q", tt.minor, tt.currency, got, tt.want)
+		}
+
+		// the formatted amount is parsed back to the same minor units
+		if back, err := Parse(got, tt.currency); err != nil || back != tt.minor {
+			t.Errorf("Parse(Format(%d, %s)) = %d, %v", tt.minor, tt.currency, back, err)
+		}
+	}
+}
+
+func TestMinorMajor(t *testing.T) {
+	tests := []struct {
+		major    float64
+		currency string
+		minor    int64
+	}{
+		{major: 12.5, currency: "EUR", minor: 1250},
+		{major: 0.125, currency: "EUR", minor: 13},
+		{major: -0.125, currency: "EUR", minor: -13},
+		{major: 1234, currency: "JPY", minor: 1234},
+		{major: 1234.5, currency: "JPY", minor: 1235},
+		{major: 1.2345, currency: "KWD", minor: 1235},
+	}
+
+	for _, tt := range tests {
+		if got := Minor(tt.major, tt.currency); got != tt.minor {
+			t.Errorf("Minor(%v, %s) = %d, want %d", tt.major, tt.currency, got, tt.minor)
+		}
+	}
+
+	if got := Major(1250, "EUR"); got != 12.5 {
+		t.Errorf("Major(1250, EUR) = %v, want 12.5", got)
+	}
+
+	if got := Major(1234, "JPY"); got != 1234 {
+		t.Errorf("Major(1234, JPY) = %v, want 1234", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		from, to string
+		rate     float64
+		want     int64
+	}{
+		{minor: 10000, from: "EUR", to: "USD", rate: 1.0321, want: 10321},
+		{minor: 10000, from: "EUR", to: "JPY", rate: 162.74, want: 16274},
+		{minor: 1234, from: "JPY", to: "USD", rate: 0.0067, want: 827},
+		{minor: -1234, from: "JPY", to: "USD", rate: 0.0067, want: -827},
+		{minor: 1000, from: "KWD", to: "EUR", rate: 3, want: 300},
+		{minor: 999, from: "EUR", to: "EUR", rate: 2, want: 999},
+	}
+
+	for _, tt := range tests {
+		if got := Convert(tt.minor, tt.from, tt.to, tt.rate); got != tt.want {
+			t.Errorf("Convert(%d, %s, %s, %v) = %d, want %d", tt.minor, tt.from, tt.to, tt.rate, got, tt.want)
+		}
+	}
+}
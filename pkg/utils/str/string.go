@@ -2,6 +2,7 @@
 package str
 
 import (
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -24,3 +25,26 @@ func CapitalizeAll(s string) string {
 	}
 	return strings.Join(words, " ")
 }
+
+// Ordinal function returns the ordinal form of a number, e.g. 1st, 2nd, 3rd, 11th, 22nd.
+func Ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// Plural function returns a number followed by a word, in the plural form for every number but 1, e.g. 1 date, 3 dates.
+func Plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return strconv.Itoa(n) + " " + word + "s"
+}
@@ -8,8 +8,9 @@ import (
 
 // DBParse is a helper function to parse a time string from a database.
 // the function get a string and return a time.Time object.
+// the database stores the times in the local timezone, so the string is parsed in the local timezone.
 func DBParse(s string) (time.Time, error) {
-	t, err := time.Parse(dbtimeformat, s)
+	t, err := time.ParseInLocation(dbtimeformat, s, time.Local)
 	if err != nil {
 		return time.Time{}, errors.New("failed to parse time: " + err.Error())
 	}
@@ -0,0 +1,354 @@
+// tm package recurring schedules functions
+package tm
+
+import (
+	"aio/pkg/utils/str"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// units of a recurring schedule
+const (
+	UnitDay   = "day"
+	UnitWeek  = "week"
+	UnitMonth = "month"
+	UnitYear  = "year"
+)
+
+const validschedules = `
+	Please provide a valid schedule in the following format:
+	- "daily", "weekly", "fortnightly", "monthly", "quarterly", "yearly", "annually"
+	- "every" + "day", "week", "month", "quarter", "year"
+	- "every" + number or "other" + "days", "weeks", "months", "quarters", "years"
+	- the days of the week, e.g. "mondays", "every monday and thursday", "mon, wed, fri", "weekdays", "weekends"
+	- a weekly schedule + "on" + the days of the week, e.g. "every 2 weeks on friday"
+	- a monthly schedule + "on the" + the day of the month or "last day", e.g. "every month on the 1st"
+	- a yearly schedule + "on" + the day and the month, e.g. "every year on 02 Jan"
+
+	Please note that the schedule is case insensitive
+	Without a day, the schedule repeats the day of its start
+
+	Example:
+	- "Every month on the 1st"
+	- "Mondays"
+	- "Every 3 months on the last day"
+	- "Yearly on 15 March"
+`
+
+// Schedule struct represents a recurring schedule of dates, e.g. "every month on the 1st" or "mondays".
+// the occurrences are counted from a start date, so "every 2 weeks" repeats the weekday of the start every other week.
+type Schedule struct {
+	Unit     string         // day, week, month or year
+	Every    int            // the number of units between two occurrences
+	Weekdays []time.Weekday // the days of a weekly schedule, the day of the start if empty
+	Day      int            // the day of a monthly or yearly schedule, -1 for the last day, the day of the start if 0
+	Month    time.Month     // the month of a yearly schedule, the month of the start if 0
+}
+
+// aliases are the single words of the usual schedules.
+var aliases = map[string]Schedule{
+	"daily":       {Unit: UnitDay, Every: 1},
+	"weekly":      {Unit: UnitWeek, Every: 1},
+	"fortnightly": {Unit: UnitWeek, Every: 2},
+	"biweekly":    {Unit: UnitWeek, Every: 2},
+	"monthly":     {Unit: UnitMonth, Every: 1},
+	"quarterly":   {Unit: UnitMonth, Every: 3},
+	"yearly":      {Unit: UnitYear, Every: 1},
+	"annually":    {Unit: UnitYear, Every: 1},
+	"weekdays":    {Unit: UnitWeek, Every: 1, Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+	"weekends":    {Unit: UnitWeek, Every: 1, Weekdays: []time.Weekday{time.Saturday, time.Sunday}},
+}
+
+// fillers are the words of a schedule without a meaning of their own.
+var fillers = []string{"every", "each", "on", "in", "the", "and", "of", "a", "day"}
+
+// unit is a helper function to get the unit of a schedule and its multiplier from a word, e.g. "quarters" is 3 months.
+func unit(s string) (string, int, bool) {
+	switch strings.TrimSuffix(s, "s") {
+	case "day":
+		return UnitDay, 1, true
+	case "week":
+		return UnitWeek, 1, true
+	case "fortnight":
+		return UnitWeek, 2, true
+	case "month":
+		return UnitMonth, 1, true
+	case "quarter":
+		return UnitMonth, 3, true
+	case "year":
+		return UnitYear, 1, true
+	default:
+		return "", 0, false
+	}
+}
+
+// ordinal is a helper function to parse a day of the month, e.g. "1st", "22nd" or "15".
+func ordinal(s string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		s = strings.TrimSuffix(s, suffix)
+	}
+
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// month is a helper function to parse the name of a month, e.g. "jan" or "january".
+func month(s string) (time.Month, bool) {
+	s = str.CapitalizeFirst(s)
+	for _, layout := range []string{"Jan", "January"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Month(), true
+		}
+	}
+	return 0, false
+}
+
+func getScheduleErr(err error) error {
+	e := errors.New("Valid schedule formats: " + validschedules)
+	return errors.Join(err, e)
+}
+
+// ParseSchedule is a helper function to parse a recurring schedule, with the words of Parse.
+// e.g. "every month on the 1st", "mondays", "every 2 weeks on friday", "quarterly" or "every year on 02 Jan".
+func ParseSchedule(s string) (Schedule, error) {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(s, ",", " ")))
+	if len(words) == 0 {
+		return Schedule{}, getScheduleErr(errors.New("the schedule is empty"))
+	}
+
+	sc := Schedule{}
+	every := 0
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		next := ""
+		if i+1 < len(words) {
+			next = words[i+1]
+		}
+
+		if alias, ok := aliases[w]; ok {
+			if sc.Unit != "" && sc.Unit != alias.Unit {
+				return Schedule{}, getScheduleErr(errors.New("the schedule has two units: " + s))
+			}
+			sc.Unit = alias.Unit
+			sc.Weekdays = append(sc.Weekdays, alias.Weekdays...)
+			if every == 0 {
+				every = alias.Every
+			}
+			continue
+		}
+
+		// "day" is a unit in "every day", a filler in "on the last day"
+		isUnit := func(w string) bool {
+			_, _, ok := unit(w)
+			return ok && (w != "day" || sc.Unit == "" && sc.Day == 0)
+		}
+
+		if u, n, ok := unit(w); ok && isUnit(w) {
+			if sc.Unit != "" && sc.Unit != u {
+				return Schedule{}, getScheduleErr(errors.New("the schedule has two units: " + s))
+			}
+			sc.Unit = u
+			every = max(every, 1) * n
+			continue
+		}
+
+		if w == "other" {
+			every = 2
+			continue
+		}
+
+		if w == "last" {
+			sc.Day = -1
+			continue
+		}
+
+		if slices.Contains(fillers, w) {
+			continue
+		}
+
+		if wd, err := ParseWeekday(w); err == nil {
+			if sc.Unit != "" && sc.Unit != UnitWeek {
+				return Schedule{}, getScheduleErr(errors.New("the days of the week are valid only in a weekly schedule: " + s))
+			}
+			sc.Unit = UnitWeek
+			if !slices.Contains(sc.Weekdays, wd) {
+				sc.Weekdays = append(sc.Weekdays, wd)
+			}
+			continue
+		}
+
+		if m, ok := month(w); ok {
+			sc.Month = m
+			continue
+		}
+
+		// a number before a unit is the interval, otherwise it is the day of the month
+		if n, err := strconv.Atoi(w); err == nil {
+			if isUnit(next) {
+				if n < 1 {
+					return Schedule{}, getScheduleErr(errors.New("the interval must be at least 1: " + s))
+				}
+				every = n
+				continue
+			}
+		}
+
+		if d, ok := ordinal(w); ok {
+			if d < 1 || d > 31 {
+				return Schedule{}, getScheduleErr(errors.New("invalid day of the month: " + w))
+			}
+			sc.Day = d
+			continue
+		}
+
+		return Schedule{}, getScheduleErr(errors.New("invalid word in the schedule: " + w))
+	}
+
+	// "on the 1st" alone is a monthly schedule, "on 02 Jan" a yearly one
+	switch {
+	case sc.Unit == "" && sc.Month != 0:
+		sc.Unit = UnitYear
+	case sc.Unit == "" && sc.Day != 0:
+		sc.Unit = UnitMonth
+	case sc.Unit == "":
+		return Schedule{}, getScheduleErr(errors.New("the schedule has no unit: " + s))
+	}
+
+	sc.Every = max(every, 1)
+	switch {
+	case sc.Day != 0 && sc.Unit != UnitMonth && sc.Unit != UnitYear:
+		return Schedule{}, getScheduleErr(errors.New("the day of the month is valid only in a monthly or yearly schedule: " + s))
+	case sc.Month != 0 && sc.Unit != UnitYear:
+		return Schedule{}, getScheduleErr(errors.New("the month is valid only in a yearly schedule: " + s))
+	case sc.Month != 0 && sc.Day > daysIn(2024, sc.Month):
+		return Schedule{}, getScheduleErr(errors.New("invalid day of " + sc.Month.String() + ": " + strconv.Itoa(sc.Day)))
+	}
+
+	// the weeks start on monday
+	slices.SortFunc(sc.Weekdays, func(a, b time.Weekday) int { return int(a+6)%7 - int(b+6)%7 })
+	return sc, nil
+}
+
+// daysIn is a helper function to get the number of days of a month.
+func daysIn(year int, m time.Month) int {
+	return time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// midnight is a helper function to get the start of the day of a time.
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// between is a helper function to get the number of days between two midnights, rounded for the daylight saving time.
+func between(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// Next function returns the first occurrence of the schedule started at start that is after the day of after,
+// at midnight. the occurrences before the day of start are skipped.
+// a day of the month missing in a month, like the 31st, is the last day of that month.
+func (sc Schedule) Next(start, after time.Time) time.Time {
+	start = midnight(start)
+	from := midnight(after).AddDate(0, 0, 1)
+	if from.Before(start) {
+		from = start
+	}
+
+	every := max(sc.Every, 1)
+	switch sc.Unit {
+	case UnitDay, UnitWeek:
+		step := every
+		if sc.Unit == UnitWeek {
+			step *= 7
+		}
+
+		if sc.Unit == UnitWeek && len(sc.Weekdays) > 0 {
+			// the weeks start on monday, the first week is the one of the start
+			monday := start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+			for d := from; ; d = d.AddDate(0, 0, 1) {
+				if (between(monday, d)/7)%every == 0 && slices.Contains(sc.Weekdays, d.Weekday()) {
+					return d
+				}
+			}
+		}
+
+		k := (between(start, from) + step - 1) / step
+		return start.AddDate(0, 0, k*step)
+	default:
+		months := every
+		if sc.Unit == UnitYear {
+			months *= 12
+		}
+
+		day, m := sc.Day, start.Month()
+		if day == 0 {
+			day = start.Day()
+		}
+
+		if sc.Unit == UnitYear && sc.Month != 0 {
+			m = sc.Month
+		}
+
+		elapsed := (from.Year()-start.Year())*12 + int(from.Month()-m)
+		for k := max(elapsed/months-1, 0); ; k++ {
+			first := time.Date(start.Year(), m+time.Month(k*months), 1, 0, 0, 0, 0, start.Location())
+			last := daysIn(first.Year(), first.Month())
+			d := first.AddDate(0, 0, min(day, last)-1)
+			if day < 0 {
+				d = first.AddDate(0, 0, last-1)
+			}
+
+			if !d.Before(from) {
+				return d
+			}
+		}
+	}
+}
+
+// PerYear function returns the average number of occurrences of the schedule in a year.
+func (sc Schedule) PerYear() float64 {
+	every := float64(max(sc.Every, 1))
+	switch sc.Unit {
+	case UnitDay:
+		return 365.25 / every
+	case UnitWeek:
+		return 365.25 / 7 * float64(max(len(sc.Weekdays), 1)) / every
+	case UnitMonth:
+		return 12 / every
+	default:
+		return 1 / every
+	}
+}
+
+// String function returns the schedule in words, e.g. "every month on the 1st".
+func (sc Schedule) String() string {
+	s := "every " + sc.Unit
+	if sc.Every > 1 {
+		s = "every " + strconv.Itoa(sc.Every) + " " + sc.Unit + "s"
+	}
+
+	switch {
+	case len(sc.Weekdays) > 0:
+		names := []string{}
+		for _, wd := range sc.Weekdays {
+			names = append(names, wd.String()[:3])
+		}
+		s += " on " + strings.Join(names, ", ")
+	case sc.Unit == UnitYear && sc.Month != 0 && sc.Day == -1:
+		s += " on the last day of " + sc.Month.String()
+	case sc.Unit == UnitYear && sc.Month != 0 && sc.Day != 0:
+		s += " on " + time.Date(2024, sc.Month, sc.Day, 0, 0, 0, 0, time.UTC).Format("02 Jan")
+	case sc.Unit == UnitYear && sc.Month != 0:
+		s += " in " + sc.Month.String()
+	case sc.Day == -1:
+		s += " on the last day"
+	case sc.Day != 0:
+		s += " on the " + str.Ordinal(sc.Day)
+	}
+
+	return s
+}
@@ -0,0 +1,139 @@
+package tm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    string
+		invalid bool
+	}{
+		{s: "daily", want: "every day"},
+		{s: "every day", want: "every day"},
+		{s: "every 3 days", want: "every 3 days"},
+		{s: "weekly", want: "every week"},
+		{s: "fortnightly", want: "every 2 weeks"},
+		{s: "every other week", want: "every 2 weeks"},
+		{s: "mondays", want: "every week on Mon"},
+		{s: "every monday and thursday", want: "every week on Mon, Thu"},
+		{s: "mon, wed, fri", want: "every week on Mon, Wed, Fri"},
+		{s: "sunday, monday", want: "every week on Mon, Sun"},
+		{s: "weekends", want: "every week on Sat, Sun"},
+		{s: "every 2 weeks on friday", want: "every 2 weeks on Fri"},
+		{s: "monthly", want: "every month"},
+		{s: "Every Month On The 1ST", want: "every month on the 1st"},
+		{s: "every 3 months on the last day", want: "every 3 months on the last day"},
+		{s: "quarterly", want: "every 3 months"},
+		{s: "every quarter on the 22nd", want: "every 3 months on the 22nd"},
+		{s: "on the 15th", want: "every month on the 15th"},
+		{s: "yearly on 15 March", want: "every year on 15 Mar"},
+		{s: "every year on 02 Jan", want: "every year on 02 Jan"},
+		{s: "on 29 feb", want: "every year on 29 Feb"},
+		{s: "every year in june", want: "every year in June"},
+		{s: "every year on the last day of december", want: "every year on the last day of December"},
+		{s: "", invalid: true},
+		{s: "every", invalid: true},
+		{s: "sometimes", invalid: true},
+		{s: "every 0 days", invalid: true},
+		{s: "daily weekly", invalid: true},
+		{s: "mondays on the 1st", invalid: true},
+		{s: "every month on monday", invalid: true},
+		{s: "every month in june", invalid: true},
+		{s: "on the 32nd", invalid: true},
+		{s: "on 30 feb", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			sc, err := ParseSchedule(tt.s)
+			if (err != nil) != tt.invalid {
+				t.Fatalf("ParseSchedule(%q) error = %v, want an error %v", tt.s, err, tt.invalid)
+			}
+
+			if !tt.invalid && sc.String() != tt.want {
+				t.Errorf("ParseSchedule(%q) = %q, want %q", tt.s, sc.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// date function parses a day, or a day with the time
+	date := func(s string) time.Time {
+		layout := "2006-01-02"
+		if len(s) > len(layout) {
+			layout += " 15:04"
+		}
+
+		d, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		start    string
+		after    string
+		want     string
+	}{
+		{name: "next day", schedule: "daily", start: "2026-10-01", after: "2026-10-05 15:30", want: "2026-10-06"},
+		{name: "before the start", schedule: "daily", start: "2026-10-10", after: "2026-10-01", want: "2026-10-10"},
+		{name: "every 3 days", schedule: "every 3 days", start: "2026-10-01", after: "2026-10-05", want: "2026-10-07"},
+		{name: "weekday of the start", schedule: "weekly", start: "2026-10-01", after: "2026-10-01", want: "2026-10-08"},
+		{name: "days of the week", schedule: "mon, wed, fri", start: "2026-10-01", after: "2026-10-02", want: "2026-10-05"},
+		{name: "every other week, first week", schedule: "every 2 weeks on friday", start: "2026-10-01", after: "2026-10-01", want: "2026-10-02"},
+		{name: "every other week, skipped week", schedule: "every 2 weeks on friday", start: "2026-10-01", after: "2026-10-02", want: "2026-10-16"},
+		{name: "31st in february", schedule: "every month on the 31st", start: "2026-01-31", after: "2026-01-31", want: "2026-02-28"},
+		{name: "31st after february", schedule: "every month on the 31st", start: "2026-01-31", after: "2026-02-28", want: "2026-03-31"},
+		{name: "day of the start in a short month", schedule: "monthly", start: "2026-01-31", after: "2026-04-01", want: "2026-04-30"},
+		{name: "last day of the first month", schedule: "every 3 months on the last day", start: "2026-01-15", after: "2026-01-15", want: "2026-01-31"},
+		{name: "last day of the quarter", schedule: "every 3 months on the last day", start: "2026-01-15", after: "2026-01-31", want: "2026-04-30"},
+		{name: "29 feb in a common year", schedule: "every year on 29 feb", start: "2026-01-01", after: "2026-03-01", want: "2027-02-28"},
+		{name: "29 feb in a leap year", schedule: "every year on 29 feb", start: "2027-06-01", after: "2027-06-01", want: "2028-02-29"},
+		{name: "yearly from 29 feb", schedule: "yearly", start: "2024-02-29", after: "2024-03-01", want: "2025-02-28"},
+		{name: "month before the start", schedule: "every year in june", start: "2026-10-18", after: "2026-10-18", want: "2027-06-18"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseSchedule(tt.schedule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := sc.Next(date(tt.start), date(tt.after)).Format("2006-01-02")
+			if got != tt.want {
+				t.Errorf("Next(%s, %s) of %q = %s, want %s", tt.start, tt.after, tt.schedule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulePerYear(t *testing.T) {
+	tests := map[string]float64{
+		"daily":         365.25,
+		"mon, wed, fri": 365.25 / 7 * 3,
+		"fortnightly":   365.25 / 14,
+		"monthly":       12,
+		"quarterly":     4,
+		"yearly":        1,
+		"every 2 years": 0.5,
+	}
+
+	for s, want := range tests {
+		sc, err := ParseSchedule(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := sc.PerYear(); got != want {
+			t.Errorf("PerYear of %q = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -78,6 +78,34 @@ var days = []string{
 	"Sundays",
 }
 
+// weekday is a helper function to get the day of the week from one of the capitalized names in days.
+func weekday(s string) (time.Weekday, error) {
+	switch s {
+	case "Mon", "Monday", "Mondays":
+		return time.Monday, nil
+	case "Tue", "Tuesday", "Tuesdays":
+		return time.Tuesday, nil
+	case "Wed", "Wednesday", "Wednesdays":
+		return time.Wednesday, nil
+	case "Thu", "Thursday", "Thursdays":
+		return time.Thursday, nil
+	case "Fri", "Friday", "Fridays":
+		return time.Friday, nil
+	case "Sat", "Saturday", "Saturdays":
+		return time.Saturday, nil
+	case "Sun", "Sunday", "Sundays":
+		return time.Sunday, nil
+	default:
+		return time.Sunday, errors.New("invalid day of the week: " + s)
+	}
+}
+
+// ParseWeekday is a helper function to parse the name of a day of the week.
+// the function accepts the short, long and plural names, case insensitive (e.g. "mon", "Monday", "mondays").
+func ParseWeekday(s string) (time.Weekday, error) {
+	return weekday(str.CapitalizeFirst(strings.TrimSpace(s)))
+}
+
 func getErr(err error) error {
 	e := errors.New("Valid time foramts: " + validformats)
 	return errors.Join(err, e)
@@ -130,28 +158,10 @@ func parseWeekDay(s, at string, multiplier int) (time.Time, error) {
 		return t.AddDate(0, 0, 7*multiplier), err // add the time based on the multiplier
 	default:
 		t := now
-		di := slices.Index(days, s)
-		wds := days[di] // get the day of the week
-		var wd time.Weekday
 
 		// set the day of the week for the filter
-		switch wds {
-		case "Mon", "Monday", "Mondays":
-			wd = time.Monday
-		case "Tue", "Tuesday", "Tuesdays":
-			wd = time.Tuesday
-		case "Wed", "Wednesday", "Wednesdays":
-			wd = time.Wednesday
-		case "Thu", "Thursday", "Thursdays":
-			wd = time.Thursday
-		case "Fri", "Friday", "Fridays":
-			wd = time.Friday
-		case "Sat", "Saturday", "Saturdays":
-			wd = time.Saturday
-		case "Sun", "Sunday", "Sundays":
-			wd = time.Sunday
-		default:
-			err := errors.New("invalid day of the week")
+		wd, err := weekday(s)
+		if err != nil {
 			return time.Time{}, getErr(err)
 		}
 